@@ -17,20 +17,24 @@ func main() {
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
 		AllowCredentials: true,
 	}))
 
 	r.POST("/command", handlers.HandleCommand)
+	r.POST("/command/stream", handlers.HandleCommandStream) // Ejecución del script con un evento por línea
 	r.POST("/login", handlers.HandleLogin)
 	r.POST("/logout", handlers.HandleLogout)
 	r.GET("/disks", handlers.HandleDisk)                      // Ruta para listar discos
 	r.GET("/disks/partitions", handlers.HandleDiskPartitions) // Modificado para usar query param
 	r.GET("/directory", handlers.HandleDirectoryLs)           // Nueva ruta para listar directorios en JSON
 	r.POST("/read-file", handlers.HandleReadFile)             // Nueva ruta para leer contenido de archivos
+	r.POST("/upload", handlers.HandleUpload)                  // Subir un archivo del cliente (multipart, campo "file")
+	r.GET("/download", handlers.HandleDownload)               // Descargar un archivo como application/octet-stream
 	r.GET("/journaling", func(c *gin.Context) {               // Nueva ruta para obtener el journaling
 		handlers.GetJournaling(c.Writer, c.Request)
 	})
+	r.GET("/reports/:name", handlers.HandleReport) // Datos de los reportes de rep en JSON
 
 	filePath := "/discos/NAME.txt"
 	err := os.WriteFile(filePath, []byte("Jorge"), 0644)
@@ -0,0 +1,303 @@
+package args
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Token es una palabra de una línea de comando ya sin comillas ni escapes
+type Token struct {
+	Text   string
+	Column int  // Columna (desde 1) donde empieza el token en la línea original
+	Plain  bool // Indica si el token se escribió sin comillas, escapes ni variables
+
+	// Posición en Text del primer '=' escrito fuera de comillas, o -1 si no hay
+	equals int
+	// Indica si el token empieza con un '-' escrito fuera de comillas
+	dash bool
+}
+
+// escapable son los caracteres que '\' escapa fuera de comillas
+const escapable = " \t\r'\"\\#$"
+
+// ParseError es un error de sintaxis en una línea de comando
+type ParseError struct {
+	Column  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("error de sintaxis en la columna %d: %s", e.Column, e.Message)
+}
+
+// lexer recorre una línea respetando comillas simples, comillas dobles y escapes:
+//   - Entre comillas simples todo es literal
+//   - Entre comillas dobles '\' escapa '"', '\' y '$'
+//   - Fuera de comillas '\' escapa un espacio, una comilla, '\', '#' o '$';
+//     antes de cualquier otro carácter es literal, como en C:\discos\a.mia
+//   - Fuera de comillas '#' inicia un comentario
+//
+// Si lookup no es nil, $VAR y ${VAR} se reemplazan por su valor fuera de
+// comillas y entre comillas dobles; el valor nunca se vuelve a interpretar
+type lexer struct {
+	line    []rune
+	tokens  []Token
+	comment int // Posición (en runas) donde empieza el comentario, o -1
+	lookup  func(name string) (string, bool)
+}
+
+func (l *lexer) run() error {
+	l.comment = -1
+
+	var current strings.Builder
+	var token *Token
+
+	// start abre un token nuevo en la posición i si no hay uno abierto
+	start := func(i int) {
+		if token == nil {
+			token = &Token{Column: i + 1, Plain: true, equals: -1}
+			current.Reset()
+		}
+	}
+	// finish cierra el token abierto
+	finish := func() {
+		if token != nil {
+			token.Text = current.String()
+			l.tokens = append(l.tokens, *token)
+			token = nil
+		}
+	}
+
+	for i := 0; i < len(l.line); i++ {
+		r := l.line[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\r':
+			finish()
+
+		case r == '#':
+			finish()
+			l.comment = i
+			return nil
+
+		case r == '\\' && i+1 < len(l.line) && strings.ContainsRune(escapable, l.line[i+1]):
+			start(i)
+			token.Plain = false
+			i++
+			current.WriteRune(l.line[i])
+
+		case r == '\'' || r == '"':
+			start(i)
+			token.Plain = false
+			end, err := l.quoted(i, &current)
+			if err != nil {
+				return err
+			}
+			i = end
+
+		case r == '$' && l.lookup != nil:
+			start(i)
+			end, expanded, err := l.variable(i, &current)
+			if err != nil {
+				return err
+			}
+			if expanded {
+				token.Plain = false
+			}
+			i = end
+
+		default:
+			start(i)
+			if r == '-' && current.Len() == 0 {
+				token.dash = true
+			}
+			if r == '=' && token.equals == -1 {
+				token.equals = current.Len()
+			}
+			current.WriteRune(r)
+		}
+	}
+
+	finish()
+	return nil
+}
+
+// quoted copia a current el texto entre la comilla en la posición open y la
+// comilla que la cierra, y retorna la posición de la comilla de cierre
+func (l *lexer) quoted(open int, current *strings.Builder) (int, error) {
+	quote := l.line[open]
+
+	for i := open + 1; i < len(l.line); i++ {
+		r := l.line[i]
+
+		if r == quote {
+			return i, nil
+		}
+		if quote == '"' && r == '\\' && i+1 < len(l.line) && strings.ContainsRune(`"\$`, l.line[i+1]) {
+			i++
+			r = l.line[i]
+		} else if quote == '"' && r == '$' && l.lookup != nil {
+			end, _, err := l.variable(i, current)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+			continue
+		}
+		current.WriteRune(r)
+	}
+
+	return 0, &ParseError{Column: open + 1, Message: fmt.Sprintf("falta cerrar la comilla %c", quote)}
+}
+
+// variable escribe en current el valor de la variable que empieza con el '$' en
+// la posición dollar y retorna la posición de su último carácter. Un '$' que no
+// va seguido de un nombre se copia tal cual y expanded es false
+func (l *lexer) variable(dollar int, current *strings.Builder) (end int, expanded bool, err error) {
+	i := dollar + 1
+	braces := i < len(l.line) && l.line[i] == '{'
+	if braces {
+		i++
+	}
+
+	nameStart := i
+	for i < len(l.line) && isNameRune(l.line[i]) {
+		i++
+	}
+	name := string(l.line[nameStart:i])
+
+	if braces {
+		if i >= len(l.line) || l.line[i] != '}' || name == "" {
+			return 0, false, &ParseError{Column: dollar + 1, Message: "variable mal formada, se esperaba ${NOMBRE}"}
+		}
+		i++
+	}
+	if name == "" {
+		current.WriteRune('$')
+		return dollar, false, nil
+	}
+
+	value, ok := l.lookup(name)
+	if !ok {
+		return 0, false, fmt.Errorf("variable no definida: $%s", name)
+	}
+	current.WriteString(value)
+	return i - 1, true, nil
+}
+
+// isNameRune indica si r puede formar parte del nombre de una variable
+func isNameRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// Tokenize divide una línea de comando en tokens, sin incluir el comentario
+func Tokenize(line string) ([]Token, error) {
+	l := &lexer{line: []rune(line)}
+	if err := l.run(); err != nil {
+		return nil, err
+	}
+	return l.tokens, nil
+}
+
+// Expand divide una línea en tokens como Tokenize, reemplazando $VAR y ${VAR}
+// con lookup. Las variables no se expanden entre comillas simples ni si el '$'
+// está escapado con '\'; una variable no definida es un error
+func Expand(line string, lookup func(name string) (string, bool)) ([]Token, error) {
+	l := &lexer{line: []rune(line), lookup: lookup}
+	if err := l.run(); err != nil {
+		return nil, err
+	}
+	return l.tokens, nil
+}
+
+// Join arma una línea que Tokenize vuelve a dividir en los mismos tokens. Los
+// valores con caracteres especiales van entre comillas dobles, y un '-' inicial
+// o un '=' que venían entre comillas se mantienen como texto y no como flag
+func Join(tokens []Token) string {
+	words := make([]string, len(tokens))
+
+	for i, token := range tokens {
+		switch {
+		case token.dash && token.equals != -1:
+			name, value := token.Text[1:token.equals], token.Text[token.equals+1:]
+			words[i] = "-" + quoteWord(name, strings.Contains(name, "=")) + "=" + quoteWord(value, false)
+		case token.dash:
+			words[i] = "-" + quoteWord(token.Text[1:], false)
+		default:
+			words[i] = quoteWord(token.Text, token.Text == "" || strings.HasPrefix(token.Text, "-"))
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// quoteWord retorna s entre comillas dobles si tiene caracteres especiales o
+// si force es true
+func quoteWord(s string, force bool) string {
+	if !force && !strings.ContainsAny(s, escapable) {
+		return s
+	}
+
+	var word strings.Builder
+	word.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' || r == '$' {
+			word.WriteByte('\\')
+		}
+		word.WriteRune(r)
+	}
+	word.WriteByte('"')
+	return word.String()
+}
+
+// StripComment elimina el comentario de una línea, ignorando los '#' que
+// están entre comillas. Si la línea tiene un error de sintaxis se retorna
+// completa para que el error se reporte al ejecutarla
+func StripComment(line string) string {
+	l := &lexer{line: []rune(line)}
+	if err := l.run(); err != nil || l.comment == -1 {
+		return strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(string(l.line[:l.comment]))
+}
+
+// ParseArgs divide una línea en los argumentos para cobra. Los flags -flag=valor
+// se convierten en "--flag=valor", con el nombre del flag en minúsculas
+func ParseArgs(command string) ([]string, error) {
+	tokens, err := Tokenize(command)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(tokens))
+
+	for _, token := range tokens {
+		if !token.dash || token.Text == "-" || token.Text == "--" {
+			result = append(result, token.Text)
+			continue
+		}
+
+		flag, value := token.Text, ""
+		if token.equals != -1 {
+			flag, value = token.Text[:token.equals], token.Text[token.equals+1:]
+		}
+		if flag == "-" || flag == "--" {
+			return nil, &ParseError{Column: token.Column, Message: "flag sin nombre"}
+		}
+
+		// Convertir -flag a --flag y normalizar a minúsculas
+		if len(flag) > 2 && flag[1] != '-' {
+			flag = "-" + flag
+		}
+		flag = strings.ToLower(flag)
+
+		// El valor va unido al flag para que cobra no lo confunda con otro
+		// flag cuando empieza con '-' o cuando está vacío
+		if token.equals != -1 {
+			flag += "=" + value
+		}
+		result = append(result, flag)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,233 @@
+package args
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// texts retorna el texto de cada token
+func texts(tokens []Token) []string {
+	result := make([]string, len(tokens))
+	for i, token := range tokens {
+		result[i] = token.Text
+	}
+	return result
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"palabras", "mkdisk -size=10 -path=/tmp/a.mia", []string{"mkdisk", "-size=10", "-path=/tmp/a.mia"}},
+		{"espacios repetidos", "  mkdir \t -p  ", []string{"mkdir", "-p"}},
+		{"comillas dobles", `mkdisk -path="/tmp/con espacios/a.mia"`, []string{"mkdisk", "-path=/tmp/con espacios/a.mia"}},
+		{"comillas simples", `cat 'a "b" c'`, []string{"cat", `a "b" c`}},
+		{"comillas pegadas", `a"b c"'d e'f`, []string{"ab cd ef"}},
+		{"token vacío", `a "" ''`, []string{"a", "", ""}},
+		{"escapes entre comillas dobles", `x "a \"b\" \\ \$c"`, []string{"x", `a "b" \ $c`}},
+		{"barra literal entre comillas dobles", `x "a\nb"`, []string{"x", `a\nb`}},
+		{"barra literal entre comillas simples", `x 'a\'`, []string{"x", `a\`}},
+		{"espacio escapado", `mkfile -path=/a\ b.txt`, []string{"mkfile", "-path=/a b.txt"}},
+		{"comillas escapadas", `x \"a\'`, []string{"x", `"a'`}},
+		{"ruta de Windows", `mkdisk -path=C:\discos\nuevo\a.mia`, []string{"mkdisk", `-path=C:\discos\nuevo\a.mia`}},
+		{"barra al final", `mkdisk -path=C:\discos\`, []string{"mkdisk", `-path=C:\discos\`}},
+		{"comentario", "mkdir -path=/a # crea /a", []string{"mkdir", "-path=/a"}},
+		{"numeral entre comillas dobles", `mkdir -path="/a # b"`, []string{"mkdir", "-path=/a # b"}},
+		{"numeral entre comillas simples", `mkdir -path='/a#b' #fin`, []string{"mkdir", "-path=/a#b"}},
+		{"numeral escapado", `mkdir -path=/a\#b`, []string{"mkdir", "-path=/a#b"}},
+		{"dólar sin expandir", `mkusr -pass=p$1`, []string{"mkusr", "-pass=p$1"}},
+		{"multibyte", "mkdir -path=/año/ñandú", []string{"mkdir", "-path=/año/ñandú"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := Tokenize(tt.line)
+			if err != nil {
+				t.Fatalf("Tokenize(%q): %v", tt.line, err)
+			}
+			if got := texts(tokens); !slices.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, se esperaba %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeColumns(t *testing.T) {
+	tokens, err := Tokenize(`mkdisk  -path="a b" ñ x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Las columnas cuentan runas, no bytes
+	want := []int{1, 9, 21, 23}
+	for i, token := range tokens {
+		if token.Column != want[i] {
+			t.Errorf("columna del token %q = %d, se esperaba %d", token.Text, token.Column, want[i])
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		line    string
+		column  int
+		message string
+	}{
+		{`mkdisk -path="/tmp/a.mia`, 14, `falta cerrar la comilla "`},
+		{`cat 'a`, 5, "falta cerrar la comilla '"},
+		{`ñ "a`, 3, `falta cerrar la comilla "`},
+		{`a "b" 'c`, 7, "falta cerrar la comilla '"},
+	}
+
+	for _, tt := range tests {
+		_, err := Tokenize(tt.line)
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Tokenize(%q): se esperaba un ParseError, se obtuvo %v", tt.line, err)
+			continue
+		}
+		if parseErr.Column != tt.column || parseErr.Message != tt.message {
+			t.Errorf("Tokenize(%q) = columna %d %q, se esperaba columna %d %q",
+				tt.line, parseErr.Column, parseErr.Message, tt.column, tt.message)
+		}
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"mkdir -path=/a # crea /a", "mkdir -path=/a"},
+		{`mkdir -path="/a # b"`, `mkdir -path="/a # b"`},
+		{`mkdir -path=/a\#b # c`, `mkdir -path=/a\#b`},
+		{"# solo comentario", ""},
+		{`mkdir -path="/a # sin cerrar`, `mkdir -path="/a # sin cerrar`},
+	}
+
+	for _, tt := range tests {
+		if got := StripComment(tt.line); got != tt.want {
+			t.Errorf("StripComment(%q) = %q, se esperaba %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`MKDISK -Size=3 -PATH="/a b"`, []string{"MKDISK", "--size=3", "--path=/a b"}},
+		{"mkfile -r -cont=", []string{"mkfile", "-r", "--cont="}},
+		{"mkdir --Path=/a", []string{"mkdir", "--path=/a"}},
+		{`mkfile -path=/a -cont="-x"`, []string{"mkfile", "--path=/a", "--cont=-x"}},
+		{`cat "-file1=/a"`, []string{"cat", "-file1=/a"}},
+		{`mkusr -pass=a=b`, []string{"mkusr", "--pass=a=b"}},
+		{"fdisk - --", []string{"fdisk", "-", "--"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseArgs(tt.line)
+		if err != nil {
+			t.Errorf("ParseArgs(%q): %v", tt.line, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseArgs(%q) = %q, se esperaba %q", tt.line, got, tt.want)
+		}
+	}
+
+	_, err := ParseArgs("mkdir -=x")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Column != 7 {
+		t.Errorf(`ParseArgs("mkdir -=x") = %v, se esperaba un ParseError en la columna 7`, err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"D": "/tmp/con espacio", "N": "1", "F": "->"}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"mkdisk -path=$D/a.mia", []string{"mkdisk", "-path=/tmp/con espacio/a.mia"}},
+		{"mkdisk -path=${D}x", []string{"mkdisk", "-path=/tmp/con espaciox"}},
+		{`mkdisk -path="$D"`, []string{"mkdisk", "-path=/tmp/con espacio"}},
+		{"mkusr -pass='p$N'", []string{"mkusr", "-pass=p$N"}},
+		{`mkusr -pass=p\$N`, []string{"mkusr", "-pass=p$N"}},
+		{`mkusr -pass="p\$N"`, []string{"mkusr", "-pass=p$N"}},
+		{"x $ a$ $-", []string{"x", "$", "a$", "$-"}},
+		{"x $N$N", []string{"x", "11"}},
+	}
+
+	for _, tt := range tests {
+		tokens, err := Expand(tt.line, lookup)
+		if err != nil {
+			t.Errorf("Expand(%q): %v", tt.line, err)
+			continue
+		}
+		if got := texts(tokens); !slices.Equal(got, tt.want) {
+			t.Errorf("Expand(%q) = %q, se esperaba %q", tt.line, got, tt.want)
+		}
+	}
+
+	_, err := Expand("mkusr -pass=$X", lookup)
+	if err == nil || err.Error() != "variable no definida: $X" {
+		t.Errorf("Expand con una variable no definida = %v", err)
+	}
+
+	_, err = Expand("x ${D", lookup)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Column != 3 {
+		t.Errorf("Expand con ${ sin cerrar = %v, se esperaba un ParseError en la columna 3", err)
+	}
+
+	// Solo una flecha escrita tal cual es Plain
+	tokens, err := Expand(`-> "->" $F \->`, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{true, false, false, true} {
+		if tokens[i].Plain != want {
+			t.Errorf("Plain del token %d = %v, se esperaba %v", i, tokens[i].Plain, want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	lines := []string{
+		`mkdisk -size=3 -path="/tmp/con espacio/a.mia"`,
+		`mkfile -path=/a -cont="-x" "-y" ''`,
+		`mkusr -pass='p$1 "a" \ #b' -user=x`,
+		`cat "-file1=/a" -"fi=le"=b`,
+		`mkdisk -path=C:\discos\a.mia -r`,
+		`mkdir -path=/año/ñandú`,
+	}
+
+	for _, line := range lines {
+		tokens, err := Tokenize(line)
+		if err != nil {
+			t.Fatalf("Tokenize(%q): %v", line, err)
+		}
+		joined := Join(tokens)
+
+		want, _ := ParseArgs(line)
+		got, err := ParseArgs(joined)
+		if err != nil {
+			t.Errorf("ParseArgs(Join(%q)) = %q: %v", line, joined, err)
+			continue
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Join(%q) = %q, que se lee como %q en lugar de %q", line, joined, got, want)
+		}
+	}
+}
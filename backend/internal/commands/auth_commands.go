@@ -9,160 +9,211 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var authRootCmd = &cobra.Command{Use: "auth"}
+func newLoginCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "login",
+		Short: "Login to the system",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, _ := cmd.Flags().GetString("user")
+			password, _ := cmd.Flags().GetString("pass")
+			id, _ := cmd.Flags().GetString("id")
 
-var loginCmd = &cobra.Command{
-	Use:   "login",
-	Short: "Login to the system",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		user, _ := cmd.Flags().GetString("user")
-		password, _ := cmd.Flags().GetString("pass")
-		id, _ := cmd.Flags().GetString("id")
+			if user == "" || password == "" || id == "" {
+				return fmt.Errorf("user, password and id are required")
+			}
 
-		if user == "" || password == "" || id == "" {
-			return fmt.Errorf("user, password and id are required")
-		}
+			output := fmt.Sprintf("Logging in with user %s and id %s", user, id)
 
-		output := fmt.Sprintf("Logging in with user %s and id %s", user, id)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Aquí iría la lógica para autenticar al usuario
+			err := auth.Login(getSession(cmd), user, password, id)
 
-		// Aquí iría la lógica para autenticar al usuario
-		err := auth.Login(user, password, id)
+			if err != nil {
+				return err
+			}
 
-		if err != nil {
-			return err
-		}
+			return nil
+		},
+	}
+}
+
+func newLogoutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Logout from the system",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Aquí iría la lógica para cerrar la sesión
+
+			err := auth.Logout(getSession(cmd))
+
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			output := "Logged out"
+
+			fmt.Fprintln(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
 }
 
-var logoutCmd = &cobra.Command{
-	Use:   "logout",
-	Short: "Logout from the system",
-	Args:  cobra.NoArgs,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Aquí iría la lógica para cerrar la sesión
+func newMkgrpCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mkgrp",
+		Short: "Create a new group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
 
-		err := auth.Logout()
+			err := auth.CreateGroup(getSession(cmd), name)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		output := "Logged out"
+			output := fmt.Sprintf("Group %s created", name)
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var mkgrpCmd = &cobra.Command{
-	Use:   "mkgrp",
-	Short: "Create a new group",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name, _ := cmd.Flags().GetString("name")
+func newMkusrCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mkusr",
+		Short: "Create a new user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("user")
+			password, _ := cmd.Flags().GetString("pass")
+			group, _ := cmd.Flags().GetString("grp")
 
-		err := auth.CreateGroup(name)
+			err := auth.CreateUser(getSession(cmd), name, password, group)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		output := fmt.Sprintf("Group %s created", name)
+			output := fmt.Sprintf("User %s created", name)
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var mkusrCmd = &cobra.Command{
-	Use:   "mkusr",
-	Short: "Create a new user",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name, _ := cmd.Flags().GetString("user")
-		password, _ := cmd.Flags().GetString("pass")
-		group, _ := cmd.Flags().GetString("grp")
+func newRmgrpCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rmgrp",
+		Short: "Remove a group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
 
-		err := auth.CreateUser(name, password, group)
+			err := auth.RemoveGroup(getSession(cmd), name)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		output := fmt.Sprintf("User %s created", name)
+			output := fmt.Sprintf("Group %s removed", name)
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var rmgrpCmd = &cobra.Command{
-	Use:   "rmgrp",
-	Short: "Remove a group",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name, _ := cmd.Flags().GetString("name")
+func newRmusrCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rmusr",
+		Short: "Remove a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("user")
 
-		err := auth.RemoveGroup(name)
+			err := auth.RemoveUser(getSession(cmd), name)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		output := fmt.Sprintf("Group %s removed", name)
+			output := fmt.Sprintf("User %s removed", name)
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var rmusrCmd = &cobra.Command{
-	Use:   "rmusr",
-	Short: "Remove a user",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name, _ := cmd.Flags().GetString("user")
+func newChgrpCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "chgrp",
+		Short: "Change the group of a user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("user")
+			group, _ := cmd.Flags().GetString("grp")
 
-		err := auth.RemoveUser(name)
+			err := auth.ChangeGroup(getSession(cmd), name, group)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		output := fmt.Sprintf("User %s removed", name)
+			output := fmt.Sprintf("User %s changed to group %s", name, group)
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var chgrp = &cobra.Command{
-	Use:   "chgrp",
-	Short: "Change the group of a user",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name, _ := cmd.Flags().GetString("user")
-		group, _ := cmd.Flags().GetString("grp")
+func newChpassCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "chpass",
+		Short: "Change a user's password",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("user")
+			oldPassword, _ := cmd.Flags().GetString("old")
+			newPassword, _ := cmd.Flags().GetString("new")
 
-		err := auth.ChangeGroup(name, group)
+			err := auth.ChangePassword(getSession(cmd), name, oldPassword, newPassword)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		output := fmt.Sprintf("User %s changed to group %s", name, group)
+			output := "Password changed"
+			if name != "" {
+				output = fmt.Sprintf("Password of user %s changed", name)
+			}
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-func init() {
+// newAuthRootCmd construye un árbol de comandos nuevo en cada ejecución para que los
+// flags de una línea no se mezclen con los de otra ni con peticiones concurrentes
+func newAuthRootCmd() *cobra.Command {
+	authRootCmd := &cobra.Command{Use: "auth"}
+	loginCmd := newLoginCmd()
+	logoutCmd := newLogoutCmd()
+	mkgrpCmd := newMkgrpCmd()
+	mkusrCmd := newMkusrCmd()
+	rmgrpCmd := newRmgrpCmd()
+	rmusrCmd := newRmusrCmd()
+	chgrpCmd := newChgrpCmd()
+	chpassCmd := newChpassCmd()
+
 	authRootCmd.AddCommand(loginCmd)
 	// Login
 	loginCmd.PersistentFlags().StringP("user", "u", "", "Username")
@@ -200,22 +251,37 @@ func init() {
 	rmusrCmd.MarkPersistentFlagRequired("user")
 
 	// Chgrp
-	authRootCmd.AddCommand(chgrp)
-	chgrp.PersistentFlags().StringP("user", "n", "", "Username")
-	chgrp.MarkPersistentFlagRequired("user")
-	chgrp.PersistentFlags().StringP("grp", "g", "", "Group")
-	chgrp.MarkPersistentFlagRequired("grp")
-
+	authRootCmd.AddCommand(chgrpCmd)
+	chgrpCmd.PersistentFlags().StringP("user", "n", "", "Username")
+	chgrpCmd.MarkPersistentFlagRequired("user")
+	chgrpCmd.PersistentFlags().StringP("grp", "g", "", "Group")
+	chgrpCmd.MarkPersistentFlagRequired("grp")
+
+	// Chpass
+	authRootCmd.AddCommand(chpassCmd)
+	chpassCmd.PersistentFlags().StringP("user", "n", "", "Username (root only, to reset another user's password)")
+	chpassCmd.PersistentFlags().StringP("old", "o", "", "Current password")
+	chpassCmd.PersistentFlags().StringP("new", "w", "", "New password")
+	chpassCmd.MarkPersistentFlagRequired("new")
+
+	return authRootCmd
 }
 
 func ParseAuthCommand(
+	session *auth.LoggedUser,
 	command string,
 	data string,
 ) (
 	string,
 	error,
 ) {
-	args := args.SplitArgs(data)
+	args, err := args.ParseArgs(data)
+	if err != nil {
+		return "", err
+	}
+
+	// Cada ejecución usa su propio árbol de comandos, con los flags en sus valores predeterminados
+	authRootCmd := newAuthRootCmd()
 
 	// Parsear los argumentos
 	authRootCmd.SetArgs(args)
@@ -225,7 +291,7 @@ func ParseAuthCommand(
 	authRootCmd.SetOut(output)
 
 	// Ejecutar el comando
-	err := authRootCmd.Execute()
+	err = authRootCmd.ExecuteContext(sessionContext(session))
 	if err != nil {
 		return "", err
 	}
@@ -1,11 +1,13 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"disk.simulator.com/m/v2/internal/args"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
 	disk_operations "disk.simulator.com/m/v2/internal/disk/operations/disk"
 	partition_operations "disk.simulator.com/m/v2/internal/disk/operations/partitions"
 	"disk.simulator.com/m/v2/internal/disk/operations/reports"
@@ -14,523 +16,695 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var rootCmd = &cobra.Command{Use: "disk"}
-
-var mkdiskCmd = &cobra.Command{
-	Use:   "mkdisk",
-	Short: "Create a new disk",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		fit, _ := cmd.Flags().GetString("fit")
-		unit, _ := cmd.Flags().GetString("unit")
-		size, _ := cmd.Flags().GetInt("size")
-
-		// si unit es nil o vacío, asignar valor predeterminado
-		if unit == "" {
-			unit = "M"
-		}
+func newMkdiskCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mkdisk",
+		Short: "Create a new disk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			fit, _ := cmd.Flags().GetString("fit")
+			unit, _ := cmd.Flags().GetString("unit")
+			size, _ := cmd.Flags().GetInt("size")
+			sparse, _ := cmd.Flags().GetBool("sparse")
+
+			// si unit es nil o vacío, asignar valor predeterminado
+			if unit == "" {
+				unit = "M"
+			}
 
-		// Convertir unit y fit a mayúsculas para mantener consistencia
-		unit = strings.ToUpper(unit)
-		fit = strings.ToUpper(fit)
+			// Convertir unit y fit a mayúsculas para mantener consistencia
+			unit = strings.ToUpper(unit)
+			fit = strings.ToUpper(fit)
 
-		// Validar el valor de fit
-		if fit != "WF" && fit != "FF" && fit != "BF" {
-			return fmt.Errorf("invalid fit type. Use WF, FF, or BF")
-		}
+			// Validar el valor de fit
+			if fit != "WF" && fit != "FF" && fit != "BF" {
+				return fmt.Errorf("invalid fit type. Use WF, FF, or BF")
+			}
 
-		// Validar el valor de unit
-		if unit != "K" && unit != "M" {
-			return fmt.Errorf("invalid unit type. Use K or M")
-		}
+			// Validar el valor de unit
+			if unit != "K" && unit != "M" {
+				return fmt.Errorf("invalid unit type. Use K or M")
+			}
 
-		// Crear el output formateado
-		output := fmt.Sprintf("Creating disk at %s with size %d%s, fit %s", path, size, unit, fit)
+			// Crear el output formateado
+			output := fmt.Sprintf("Creating disk at %s with size %d%s, fit %s", path, size, unit, fit)
+			if sparse {
+				output += " (sparse)"
+			}
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		// Crear el disco usando el nuevo struct
-		params := types.MkDisk{
-			Path: path,
-			Size: size,
-			Unit: unit,
-			Fit:  fit,
-		}
+			// Crear el disco usando el nuevo struct
+			params := types.MkDisk{
+				Path:   path,
+				Size:   size,
+				Unit:   unit,
+				Fit:    fit,
+				Sparse: sparse,
+			}
 
-		err := disk_operations.CreateDisk(params)
-		if err != nil {
-			return err
-		}
+			err := disk_operations.CreateDisk(params)
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var rmdiskCmd = &cobra.Command{
-	Use:   "rmdisk",
-	Short: "Remove an existing disk",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
+func newRmdiskCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rmdisk",
+		Short: "Remove an existing disk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
 
-		// Crear el output formateado
-		output := fmt.Sprintf("Removing disk at %s", path)
+			// Crear el output formateado
+			output := fmt.Sprintf("Removing disk at %s", path)
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		// Eliminar el disco
-		err := disk_operations.RemoveDisk(path)
-		if err != nil {
-			return err
-		}
+			// Eliminar el disco
+			err := disk_operations.RemoveDisk(path)
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var fdiskCmd = &cobra.Command{
-	Use:   "fdisk",
-	Short: "Manage disk partitions",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		name, _ := cmd.Flags().GetString("name")
-		size, _ := cmd.Flags().GetInt("size")
-		unit, _ := cmd.Flags().GetString("unit")
-		fit, _ := cmd.Flags().GetString("fit")
-		partitionType, _ := cmd.Flags().GetString("type")
-		del, _ := cmd.Flags().GetString("delete")
-		add, _ := cmd.Flags().GetString("add")
-
-		// Convertir unit y fit a mayúsculas para mantener consistencia
-		if unit == "" {
-			unit = "M" // Unidad predeterminada
-		} else {
-			unit = strings.ToUpper(unit)
-		}
-		fit = strings.ToUpper(fit)
-		partitionType = strings.ToUpper(partitionType)
+func newFdiskCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "fdisk",
+		Short: "Manage disk partitions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			name, _ := cmd.Flags().GetString("name")
+			size, _ := cmd.Flags().GetInt("size")
+			unit, _ := cmd.Flags().GetString("unit")
+			fit, _ := cmd.Flags().GetString("fit")
+			partitionType, _ := cmd.Flags().GetString("type")
+			del, _ := cmd.Flags().GetString("delete")
+			add, _ := cmd.Flags().GetString("add")
+
+			// Convertir unit y fit a mayúsculas para mantener consistencia
+			if unit == "" {
+				unit = "M" // Unidad predeterminada
+			} else {
+				unit = strings.ToUpper(unit)
+			}
+			fit = strings.ToUpper(fit)
+			partitionType = strings.ToUpper(partitionType)
 
-		// Validar el valor de unit
-		if unit != "B" && unit != "K" && unit != "M" {
-			return fmt.Errorf("invalid unit type. Use B, K or M")
-		}
+			// Validar el valor de unit
+			if unit != "B" && unit != "K" && unit != "M" {
+				return fmt.Errorf("invalid unit type. Use B, K or M")
+			}
 
-		// Validar el valor de type solo si no estamos eliminando o modificando espacio
-		if del == "" && add == "" && partitionType != "P" && partitionType != "E" && partitionType != "L" {
-			return fmt.Errorf("invalid partition type. Use P, E, or L")
-		}
+			// Validar el valor de type solo si no estamos eliminando o modificando espacio
+			if del == "" && add == "" && partitionType != "P" && partitionType != "E" && partitionType != "L" {
+				return fmt.Errorf("invalid partition type. Use P, E, or L")
+			}
 
-		// Validar el valor de fit
-		if fit == "" {
-			fit = "FF" // Valor predeterminado
-		}
+			// Validar el valor de fit
+			if fit == "" {
+				fit = "FF" // Valor predeterminado
+			}
 
-		// Crear la estructura FDisk con los parámetros
-		params := types.FDisk{
-			Path: path,
-			Size: size,
-			Unit: unit,
-			Fit:  fit,
-			Name: name,
-			Type: partitionType,
-			Del:  del,
-			Add: func() int {
-				if add != "" {
-					addInt, err := strconv.Atoi(add)
-					if err == nil {
-						return addInt
+			// Crear la estructura FDisk con los parámetros
+			params := types.FDisk{
+				Path: path,
+				Size: size,
+				Unit: unit,
+				Fit:  fit,
+				Name: name,
+				Type: partitionType,
+				Del:  del,
+				Add: func() int {
+					if add != "" {
+						addInt, err := strconv.Atoi(add)
+						if err == nil {
+							return addInt
+						}
 					}
+					return 0
+				}(),
+			}
+
+			// Si es una operación de eliminación
+			if del != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Deleting partition %s from %s\n", params.Name, params.Path)
+				err := partition_operations.DeletePartition(params)
+				if err != nil {
+					return err
 				}
-				return 0
-			}(),
-		}
+				fmt.Fprintln(cmd.OutOrStdout(), "Partition deleted successfully")
+				return nil
+			}
 
-		// Si es una operación de eliminación
-		if del != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Deleting partition %s from %s\n", params.Name, params.Path)
-			err := partition_operations.DeletePartition(params)
+			// Si es una operación de añadir o quitar espacio
+			if add != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Modifying partition %s at %s by %d%s\n",
+					params.Name, params.Path, params.Add, params.Unit)
+				err := partition_operations.AddSpacePartition(params)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "Space modified in partition successfully")
+				return nil
+			}
+
+			// Si es una operación de creación
+			fmt.Fprintf(cmd.OutOrStdout(), "Creating partition %s at %s with size %d%s, type %s\n",
+				params.Name, params.Path, params.Size, params.Unit, params.Type)
+			err := partition_operations.CreatePartition(params)
 			if err != nil {
 				return err
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), "Partition deleted successfully")
+
 			return nil
-		}
+		},
+	}
+}
+
+func newRepCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rep",
+		Short: "Print the MBR of a disk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			name, _ := cmd.Flags().GetString("name")
+			id, _ := cmd.Flags().GetString("id")
+			pathFileLs, _ := cmd.Flags().GetString("path_file_ls")
+			rendererName, _ := cmd.Flags().GetString("renderer")
+			format, _ := cmd.Flags().GetString("format")
+
+			fmt.Println("Generando reporte")
+			// pathFileLs, _ := cmd.Flags().GetString("path_file_ls")
+
+			if path == "" {
+				return fmt.Errorf("el path es requerido")
+			}
+
+			if name == "" {
+				return fmt.Errorf("el nombre es requerido")
+			}
+
+			if id == "" {
+				return fmt.Errorf("el ID es requerido")
+			}
 
-		// Si es una operación de añadir o quitar espacio
-		if add != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Modifying partition %s at %s by %d%s\n",
-				params.Name, params.Path, params.Add, params.Unit)
-			err := partition_operations.AddSpacePartition(params)
+			// Los reportes con forma de tabla se dibujan sin Graphviz salvo que se pida dot
+			renderer, err := reports.ParseRenderer(rendererName)
 			if err != nil {
 				return err
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), "Space modified in partition successfully")
-			return nil
-		}
 
-		// Si es una operación de creación
-		fmt.Fprintf(cmd.OutOrStdout(), "Creating partition %s at %s with size %d%s, type %s\n",
-			params.Name, params.Path, params.Size, params.Unit, params.Type)
-		err := partition_operations.CreatePartition(params)
-		if err != nil {
-			return err
-		}
+			// Normalizar el nombre para hacer la comparación insensible a mayúsculas/minúsculas y espacios
+			normalizedName := strings.ToLower(strings.TrimSpace(name))
+			reportProcessed := false
+
+			// Con -format=json se escriben los datos del reporte en lugar de dibujarlo
+			switch strings.ToLower(strings.TrimSpace(format)) {
+			case "", "default":
+			case "json":
+				data, err := reports.ReportData(normalizedName, id, pathFileLs)
+				if errors.Is(err, reports.ErrUnknownReport) {
+					fmt.Fprintf(cmd.OutOrStdout(), "Argumento name: %s desconocido\n", name)
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte %s: %v", normalizedName, err)
+				}
 
-		return nil
-	},
-}
+				err = reports.WriteReportJSON(path, data)
+				if err != nil {
+					return fmt.Errorf("error al escribir el reporte JSON: %v", err)
+				}
 
-var repCmd = &cobra.Command{
-	Use:   "rep",
-	Short: "Print the MBR of a disk",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		name, _ := cmd.Flags().GetString("name")
-		id, _ := cmd.Flags().GetString("id")
-		pathFileLs, _ := cmd.Flags().GetString("path_file_ls")
+				fmt.Fprintf(cmd.OutOrStdout(), "Reporte %s (JSON) generado en %s\n", normalizedName, path)
+				return nil
+			default:
+				return fmt.Errorf("formato '%s' no válido: use default o json", format)
+			}
 
-		fmt.Println("Generando reporte")
-		// pathFileLs, _ := cmd.Flags().GetString("path_file_ls")
+			if normalizedName == "mbr" {
+				err := reports.MbrReport(path, id, renderer)
+				if err != nil {
+					return fmt.Errorf("error al imprimir el MBR: %v", err)
+				}
 
-		if path == "" {
-			return fmt.Errorf("el path es requerido")
-		}
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte MBR generado en %s", path)
 
-		if name == "" {
-			return fmt.Errorf("el nombre es requerido")
-		}
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
+			}
 
-		if id == "" {
-			return fmt.Errorf("el ID es requerido")
-		}
+			if normalizedName == "inode" {
+				err := reports.InodeReport(path, id, renderer)
+				if err != nil {
+					return fmt.Errorf("error al imprimir el Inode: %v", err)
+				}
 
-		// Normalizar el nombre para hacer la comparación insensible a mayúsculas/minúsculas y espacios
-		normalizedName := strings.ToLower(strings.TrimSpace(name))
-		reportProcessed := false
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte Inode generado en %s", path)
 
-		if normalizedName == "mbr" {
-			err := reports.MbrReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al imprimir el MBR: %v", err)
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
 			}
 
-			// Crear el output formateado
-			output := fmt.Sprintf("Reporte MBR generado en %s", path)
+			if normalizedName == "disk" {
+				// fmt.Println("Generando reporte de Disco")
 
-			// Escribir el output en la salida del comando
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
+				err := reports.DiskReport(path, id, renderer)
+				if err != nil {
+					return fmt.Errorf("error al imprimir el Disco: %v", err)
+				}
 
-		if normalizedName == "inode" {
-			err := reports.InodeReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al imprimir el Inode: %v", err)
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte Disco generado en %s", path)
+
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
 			}
 
-			// Crear el output formateado
-			output := fmt.Sprintf("Reporte Inode generado en %s", path)
+			if normalizedName == "bm_inode" {
+				err := reports.BInodeReport(path, id, renderer)
+				if err != nil {
+					return fmt.Errorf("error al imprimir el Bitmap de Inode: %v", err)
+				}
 
-			// Escribir el output en la salida del comando
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte Bitmap de Inode generado en %s", path)
 
-		if normalizedName == "disk" {
-			// fmt.Println("Generando reporte de Disco")
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
+			}
 
-			err := reports.DiskReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al imprimir el Disco: %v", err)
+			if normalizedName == "bm_block" {
+				err := reports.BBlockReport(path, id, renderer)
+				if err != nil {
+					return fmt.Errorf("error al imprimir el Bitmap de Bloque: %v", err)
+				}
+
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte Bitmap de Bloque generado en %s", path)
+
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
 			}
 
-			// Crear el output formateado
-			output := fmt.Sprintf("Reporte Disco generado en %s", path)
+			if normalizedName == "sb" {
+				err := reports.SuperBlockReport(path, id, renderer)
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte de SuperBlock: %v", err)
+				}
 
-			// Escribir el output en la salida del comando
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte de SuperBlock generado en %s", path)
 
-		if normalizedName == "bm_inode" {
-			err := reports.BInodeReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al imprimir el Bitmap de Inode: %v", err)
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
 			}
 
-			// Crear el output formateado
-			output := fmt.Sprintf("Reporte Bitmap de Inode generado en %s", path)
+			if normalizedName == "block" {
+				err := reports.BlockReport(path, id)
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte de Bloque: %v", err)
+				}
 
-			// Escribir el output en la salida del comando
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
+				// Crear el output formateado
+				output := fmt.Sprintf("Reporte de Bloque generado en %s", path)
 
-		if normalizedName == "bm_block" {
-			err := reports.BBlockReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al imprimir el Bitmap de Bloque: %v", err)
+				// Escribir el output en la salida del comando
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
 			}
 
-			// Crear el output formateado
-			output := fmt.Sprintf("Reporte Bitmap de Bloque generado en %s", path)
+			if normalizedName == "file" {
+				err := reports.FileReport(pathFileLs, path, id)
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte de Archivo: %v", err)
+				}
 
-			// Escribir el output en la salida del comando
+				output := fmt.Sprintf("Reporte de Archivo generado en %s", pathFileLs)
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
+			}
+
+			if normalizedName == "ls" {
+				err := reports.LSReport(pathFileLs, path, id)
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte de LS: %v", err)
+				}
+
+				output := fmt.Sprintf("Reporte de LS generado en %s", path)
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
+			}
+
+			if normalizedName == "tree" {
+				err := reports.TreeReport(path, id)
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte de Tree: %v", err)
+				}
+
+				output := fmt.Sprintf("Reporte de Tree generado en %s", path)
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+				reportProcessed = true
+			}
+
+			if normalizedName == "journaling" {
+				reportText, err := reports.JournalingReport(path, id)
+				if err != nil {
+					return fmt.Errorf("error al generar el reporte de Journaling: %v", err)
+				}
+
+				// Mostrar el reporte de journaling directamente en la consola
+				fmt.Fprintln(cmd.OutOrStdout(), reportText)
+				reportProcessed = true
+			}
+
+			// Solo mostrar el mensaje de error si no se procesó ningún reporte
+			if !reportProcessed {
+				output := fmt.Sprintf("Argumento name: %s desconocido", name)
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newMountedCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mounted",
+		Short: "List all mounted partitions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := partition_operations.GetMountedPartitions()
 			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
+			return nil
+		},
+	}
+}
+
+// MountedIDPrefix precede al ID asignado en la salida de mount
+const MountedIDPrefix = "Partition mounted with ID: "
+
+// MountedID extrae el ID asignado de la salida del comando mount
+func MountedID(output string) (string, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		if id, found := strings.CutPrefix(strings.TrimSpace(line), MountedIDPrefix); found && id != "" {
+			return id, true
 		}
+	}
+	return "", false
+}
 
-		if normalizedName == "sb" {
-			err := reports.SuperBlockReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al generar el reporte de SuperBlock: %v", err)
-			}
+func newMountCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mount",
+		Short: "Mount a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			name, _ := cmd.Flags().GetString("name")
 
 			// Crear el output formateado
-			output := fmt.Sprintf("Reporte de SuperBlock generado en %s", path)
+			output := fmt.Sprintf("Mounting partition %s from disk at %s", name, path)
 
 			// Escribir el output en la salida del comando
 			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
 
-		if normalizedName == "block" {
-			err := reports.BlockReport(path, id)
+			// Montar la partición
+			id, err := partition_operations.MountPartition(name, path)
 			if err != nil {
-				return fmt.Errorf("error al generar el reporte de Bloque: %v", err)
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s%s\n", MountedIDPrefix, id)
+			return nil
+		},
+	}
+}
+
+func newUnmountCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unmount",
+		Short: "Unmount a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+
+			if id == "" {
+				return fmt.Errorf("el ID de la partición es requerido")
 			}
 
 			// Crear el output formateado
-			output := fmt.Sprintf("Reporte de Bloque generado en %s", path)
+			output := fmt.Sprintf("Unmounting partition with ID %s", id)
 
 			// Escribir el output en la salida del comando
 			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
 
-		if normalizedName == "file" {
-			err := reports.FileReport(pathFileLs, path, id)
+			// Desmontar la partición
+			err := partition_operations.UnmountPartition(id)
 			if err != nil {
-				return fmt.Errorf("error al generar el reporte de Archivo: %v", err)
+				return err
 			}
 
-			output := fmt.Sprintf("Reporte de Archivo generado en %s", pathFileLs)
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
+			fmt.Fprintln(cmd.OutOrStdout(), "Partition unmounted successfully")
+			return nil
+		},
+	}
+}
 
-		if normalizedName == "ls" {
-			err := reports.LSReport(pathFileLs, path, id)
-			if err != nil {
-				return fmt.Errorf("error al generar el reporte de LS: %v", err)
+func newJournalingCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "journaling",
+		Short: "Muestra información de todas las transacciones realizadas en una partición",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			policy, _ := cmd.Flags().GetString("policy")
+			checkpoint, _ := cmd.Flags().GetBool("checkpoint")
+
+			if id == "" {
+				return fmt.Errorf("el ID es requerido")
 			}
 
-			output := fmt.Sprintf("Reporte de LS generado en %s", path)
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
-
-		if normalizedName == "tree" {
-			err := reports.TreeReport(path, id)
-			if err != nil {
-				return fmt.Errorf("error al generar el reporte de Tree: %v", err)
+			// Cambiar la política de journal lleno si se indicó
+			if policy != "" {
+				err := partition_operations.SetJournalPolicy(id, policy)
+				if err != nil {
+					return fmt.Errorf("error al cambiar la política del journal: %v", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Journal full policy set to %s\n", policy)
 			}
 
-			output := fmt.Sprintf("Reporte de Tree generado en %s", path)
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-			reportProcessed = true
-		}
+			// Recuperar las entradas vivas del journal circular
+			if checkpoint {
+				reclaimed, err := partition_operations.CheckpointJournal(id)
+				if err != nil {
+					return fmt.Errorf("error al hacer checkpoint del journal: %v", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Journal checkpoint: %d entries reclaimed\n", reclaimed)
+			}
 
-		if normalizedName == "journaling" {
-			reportText, err := reports.JournalingReport(path, id)
+			// Generar el reporte de journaling directamente
+			reportText, err := reports.JournalingReport("", id)
 			if err != nil {
 				return fmt.Errorf("error al generar el reporte de Journaling: %v", err)
 			}
 
 			// Mostrar el reporte de journaling directamente en la consola
 			fmt.Fprintln(cmd.OutOrStdout(), reportText)
-			reportProcessed = true
-		}
 
-		// Solo mostrar el mensaje de error si no se procesó ningún reporte
-		if !reportProcessed {
-			output := fmt.Sprintf("Argumento name: %s desconocido", name)
-			fmt.Fprintln(cmd.OutOrStdout(), output)
-		}
-
-		return nil
-	},
-}
-
-var mountedCmd = &cobra.Command{
-	Use:   "mounted",
-	Short: "List all mounted partitions",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		output := partition_operations.GetMountedPartitions()
-		fmt.Fprintln(cmd.OutOrStdout(), output)
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var mountCmd = &cobra.Command{
-	Use:   "mount",
-	Short: "Mount a partition",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		name, _ := cmd.Flags().GetString("name")
+func newRecoveryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "recovery",
+		Short: "Recupera archivos y carpetas desde el journaling",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
 
-		// Crear el output formateado
-		output := fmt.Sprintf("Mounting partition %s from disk at %s", name, path)
+			if id == "" {
+				return fmt.Errorf("el ID es requerido")
+			}
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Ejecutar la recuperación desde el journaling
+			output, err := partition_operations.RecoverFromJournaling(getSession(cmd), id)
+			if err != nil {
+				return fmt.Errorf("error en la recuperación: %v", err)
+			}
 
-		// Montar la partición
-		err := partition_operations.MountPartition(name, path)
-		if err != nil {
-			return err
-		}
+			// Imprimir la salida formateada
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var unmountCmd = &cobra.Command{
-	Use:   "unmount",
-	Short: "Unmount a partition",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		id, _ := cmd.Flags().GetString("id")
+func newLossCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "loss",
+		Short: "Simula una pérdida de información en el sistema de archivos",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
 
-		if id == "" {
-			return fmt.Errorf("el ID de la partición es requerido")
-		}
-
-		// Crear el output formateado
-		output := fmt.Sprintf("Unmounting partition with ID %s", id)
+			if id == "" {
+				return fmt.Errorf("el ID es requerido")
+			}
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Ejecutar la simulación de pérdida
+			output, err := partition_operations.SimulateSystemLoss(id)
+			if err != nil {
+				return fmt.Errorf("error en la simulación de pérdida: %v", err)
+			}
 
-		// Desmontar la partición
-		err := partition_operations.UnmountPartition(id)
-		if err != nil {
-			return err
-		}
+			// Imprimir la salida formateada
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		fmt.Fprintln(cmd.OutOrStdout(), "Partition unmounted successfully")
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var journalingCmd = &cobra.Command{
-	Use:   "journaling",
-	Short: "Muestra información de todas las transacciones realizadas en una partición",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		id, _ := cmd.Flags().GetString("id")
-
-		if id == "" {
-			return fmt.Errorf("el ID es requerido")
-		}
-
-		// Generar el reporte de journaling directamente
-		reportText, err := reports.JournalingReport("", id)
-		if err != nil {
-			return fmt.Errorf("error al generar el reporte de Journaling: %v", err)
-		}
-
-		// Mostrar el reporte de journaling directamente en la consola
-		fmt.Fprintln(cmd.OutOrStdout(), reportText)
+func newDisklistCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disklist",
+		Short: "Listar todos los discos creados",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Obtener información de los discos
+			disksInfo, err := disk_operations.GetDisksInfo()
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			// Imprimir la información de los discos
+			fmt.Fprintln(cmd.OutOrStdout(), disksInfo)
+			return nil
+		},
+	}
 }
 
-var recoveryCmd = &cobra.Command{
-	Use:   "recovery",
-	Short: "Recupera archivos y carpetas desde el journaling",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		id, _ := cmd.Flags().GetString("id")
-
-		if id == "" {
-			return fmt.Errorf("el ID es requerido")
-		}
+func newPartlistCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "partlist",
+		Short: "Listar todas las particiones de un disco",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
 
-		// Ejecutar la recuperación desde el journaling
-		output, err := partition_operations.RecoverFromJournaling(id)
-		if err != nil {
-			return fmt.Errorf("error en la recuperación: %v", err)
-		}
+			if path == "" {
+				return fmt.Errorf("el parámetro path es requerido")
+			}
 
-		// Imprimir la salida formateada
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Obtener información de las particiones
+			partitionsInfo, err := partition_operations.GetPartitionsInfo(path)
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			// Imprimir la información de las particiones
+			fmt.Fprintln(cmd.OutOrStdout(), partitionsInfo)
+			return nil
+		},
+	}
 }
 
-var lossCmd = &cobra.Command{
-	Use:   "loss",
-	Short: "Simula una pérdida de información en el sistema de archivos",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		id, _ := cmd.Flags().GetString("id")
+func newSnapshotCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save a point-in-time copy of a disk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			name, _ := cmd.Flags().GetString("name")
 
-		if id == "" {
-			return fmt.Errorf("el ID es requerido")
-		}
+			fmt.Fprintf(cmd.OutOrStdout(), "Creating snapshot %s of disk %s\n", name, path)
 
-		// Ejecutar la simulación de pérdida
-		output, err := partition_operations.SimulateSystemLoss(id)
-		if err != nil {
-			return fmt.Errorf("error en la simulación de pérdida: %v", err)
-		}
-
-		// Imprimir la salida formateada
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			snapshot, err := disk_operations.CreateSnapshot(path, name)
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			fmt.Fprintf(cmd.OutOrStdout(), "Snapshot saved at %s\n", snapshot.Path)
+			return nil
+		},
+	}
 }
 
-var disklistCmd = &cobra.Command{
-	Use:   "disklist",
-	Short: "Listar todos los discos creados",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Obtener información de los discos
-		disksInfo, err := disk_operations.GetDisksInfo()
-		if err != nil {
-			return err
-		}
+func newRollbackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore a disk from one of its snapshots",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			name, _ := cmd.Flags().GetString("name")
 
-		// Imprimir la información de los discos
-		fmt.Fprintln(cmd.OutOrStdout(), disksInfo)
-		return nil
-	},
-}
+			fmt.Fprintf(cmd.OutOrStdout(), "Rolling back disk %s to snapshot %s\n", path, name)
 
-var partlistCmd = &cobra.Command{
-	Use:   "partlist",
-	Short: "Listar todas las particiones de un disco",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
+			err := disk_operations.RollbackSnapshot(path, name)
+			if err != nil {
+				return err
+			}
 
-		if path == "" {
-			return fmt.Errorf("el parámetro path es requerido")
-		}
+			fmt.Fprintln(cmd.OutOrStdout(), "Disk restored successfully")
+			return nil
+		},
+	}
+}
 
-		// Obtener información de las particiones
-		partitionsInfo, err := partition_operations.GetPartitionsInfo(path)
-		if err != nil {
-			return err
-		}
+func newSnapshotsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshots",
+		Short: "List the snapshots of a disk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+
+			snapshotsInfo, err := disk_operations.GetSnapshotsInfo(path)
+			if err != nil {
+				return err
+			}
 
-		// Imprimir la información de las particiones
-		fmt.Fprintln(cmd.OutOrStdout(), partitionsInfo)
-		return nil
-	},
+			fmt.Fprintln(cmd.OutOrStdout(), snapshotsInfo)
+			return nil
+		},
+	}
 }
 
-func init() {
+// newDiskRootCmd construye un árbol de comandos nuevo en cada ejecución para que los
+// flags de una línea no se mezclen con los de otra ni con peticiones concurrentes
+func newDiskRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "disk"}
+	mkdiskCmd := newMkdiskCmd()
+	rmdiskCmd := newRmdiskCmd()
+	fdiskCmd := newFdiskCmd()
+	repCmd := newRepCmd()
+	mountedCmd := newMountedCmd()
+	mountCmd := newMountCmd()
+	unmountCmd := newUnmountCmd()
+	journalingCmd := newJournalingCmd()
+	recoveryCmd := newRecoveryCmd()
+	lossCmd := newLossCmd()
+	disklistCmd := newDisklistCmd()
+	partlistCmd := newPartlistCmd()
+	snapshotCmd := newSnapshotCmd()
+	rollbackCmd := newRollbackCmd()
+	snapshotsCmd := newSnapshotsCmd()
+
 	rootCmd.AddCommand(mkdiskCmd)
 	rootCmd.AddCommand(rmdiskCmd)
 	rootCmd.AddCommand(repCmd)
@@ -543,6 +717,9 @@ func init() {
 	rootCmd.AddCommand(lossCmd)
 	rootCmd.AddCommand(disklistCmd)
 	rootCmd.AddCommand(partlistCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(snapshotsCmd)
 
 	// MKDISK
 	mkdiskCmd.PersistentFlags().IntP("size", "s", 0, "Size of the disk in MB or KB") // Agregar alias -s para --size
@@ -553,6 +730,21 @@ func init() {
 
 	mkdiskCmd.Flags().StringP("fit", "f", "FF", "Fit type (WF, FF, BF)") // Agregar alias -f para --fit
 	mkdiskCmd.Flags().StringP("unit", "u", "M", "Unit type (K, M)")      // Agregar alias -u para --unit
+	mkdiskCmd.Flags().Bool("sparse", false, "Create a sparse image instead of writing zeros")
+
+	// SNAPSHOT, ROLLBACK y SNAPSHOTS
+	snapshotCmd.PersistentFlags().StringP("path", "p", "", "Path to the disk")
+	snapshotCmd.MarkPersistentFlagRequired("path")
+	snapshotCmd.PersistentFlags().StringP("name", "n", "", "Name of the snapshot")
+	snapshotCmd.MarkPersistentFlagRequired("name")
+
+	rollbackCmd.PersistentFlags().StringP("path", "p", "", "Path to the disk")
+	rollbackCmd.MarkPersistentFlagRequired("path")
+	rollbackCmd.PersistentFlags().StringP("name", "n", "", "Name of the snapshot")
+	rollbackCmd.MarkPersistentFlagRequired("name")
+
+	snapshotsCmd.PersistentFlags().StringP("path", "p", "", "Path to the disk")
+	snapshotsCmd.MarkPersistentFlagRequired("path")
 
 	// RMDISK
 	rmdiskCmd.PersistentFlags().StringP("path", "p", "", "Path to the disk") // Agregar alias -p para --path
@@ -570,6 +762,10 @@ func init() {
 
 	repCmd.Flags().String("path_file_ls", "", "Path to the file to save the ls command") // Agregar alias -f para --path_file_ls
 
+	repCmd.Flags().String("renderer", "native", "Renderer for table reports (mbr, disk, sb, inode, bm_*): native or dot")
+
+	repCmd.Flags().String("format", "default", "Output format of the report: default or json")
+
 	// FDISK
 	fdiskCmd.PersistentFlags().StringP("path", "p", "", "Path to the disk") // Agregar alias -p para --path
 	fdiskCmd.MarkPersistentFlagRequired("path")
@@ -599,6 +795,8 @@ func init() {
 	// JOURNALING
 	journalingCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición para mostrar el journaling")
 	journalingCmd.MarkPersistentFlagRequired("id")
+	journalingCmd.PersistentFlags().StringP("policy", "p", "", "Política cuando el journal está lleno (overwrite o error)")
+	journalingCmd.PersistentFlags().BoolP("checkpoint", "c", false, "Recuperar las entradas vivas del journal")
 
 	// RECOVERY
 	recoveryCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición para recuperar archivos desde el journaling")
@@ -611,16 +809,20 @@ func init() {
 	// PARTLIST
 	partlistCmd.PersistentFlags().StringP("path", "p", "", "Ruta del disco")
 	partlistCmd.MarkPersistentFlagRequired("path")
+
+	return rootCmd
 }
 
 // ParseDiskCommand analiza y ejecuta un comando de disco
-func ParseDiskCommand(command string, data string) (string, error) {
+func ParseDiskCommand(session *auth.LoggedUser, command string, data string) (string, error) {
 	// Divide los argumentos respetando las comillas y los flags con valores unidos por "="
-	args := args.SplitArgs(data)
+	args, err := args.ParseArgs(data)
+	if err != nil {
+		return "", err
+	}
 
-	// Reiniciar los flags a sus valores predeterminados antes de ejecutar un nuevo comando
-	// Esto evita que los valores del comando anterior se mantengan
-	resetFlags()
+	// Cada ejecución usa su propio árbol de comandos, con los flags en sus valores predeterminados
+	rootCmd := newDiskRootCmd()
 
 	// Configura los argumentos para cobra
 	rootCmd.SetArgs(args)
@@ -630,7 +832,7 @@ func ParseDiskCommand(command string, data string) (string, error) {
 	rootCmd.SetOut(output)
 
 	// Ejecuta el comando
-	err := rootCmd.Execute()
+	err = rootCmd.ExecuteContext(sessionContext(session))
 	if err != nil {
 		return "", err
 	}
@@ -643,98 +845,3 @@ func ParseDiskCommand(command string, data string) (string, error) {
 	// Devolver la salida capturada
 	return output.String(), nil
 }
-
-// resetFlags reinicia los valores de todos los flags a sus valores predeterminados
-func resetFlags() {
-	// Reiniciar flags de mkdisk
-	if mkdiskCmd.Flags().Lookup("size") != nil {
-		mkdiskCmd.Flags().Set("size", "0")
-	}
-	if mkdiskCmd.Flags().Lookup("path") != nil {
-		mkdiskCmd.Flags().Set("path", "")
-	}
-	if mkdiskCmd.Flags().Lookup("fit") != nil {
-		mkdiskCmd.Flags().Set("fit", "FF")
-	}
-	if mkdiskCmd.Flags().Lookup("unit") != nil {
-		mkdiskCmd.Flags().Set("unit", "M")
-	}
-
-	// Reiniciar flags de rmdisk
-	if rmdiskCmd.Flags().Lookup("path") != nil {
-		rmdiskCmd.Flags().Set("path", "")
-	}
-
-	// Reiniciar flags de fdisk
-	if fdiskCmd.Flags().Lookup("path") != nil {
-		fdiskCmd.Flags().Set("path", "")
-	}
-	if fdiskCmd.Flags().Lookup("name") != nil {
-		fdiskCmd.Flags().Set("name", "")
-	}
-	if fdiskCmd.Flags().Lookup("size") != nil {
-		fdiskCmd.Flags().Set("size", "0")
-	}
-	if fdiskCmd.Flags().Lookup("unit") != nil {
-		fdiskCmd.Flags().Set("unit", "M")
-	}
-	if fdiskCmd.Flags().Lookup("fit") != nil {
-		fdiskCmd.Flags().Set("fit", "FF")
-	}
-	if fdiskCmd.Flags().Lookup("type") != nil {
-		fdiskCmd.Flags().Set("type", "P")
-	}
-	if fdiskCmd.Flags().Lookup("delete") != nil {
-		fdiskCmd.Flags().Set("delete", "")
-	}
-	if fdiskCmd.Flags().Lookup("add") != nil {
-		fdiskCmd.Flags().Set("add", "")
-	}
-
-	// Reiniciar flags de rep
-	if repCmd.Flags().Lookup("path") != nil {
-		repCmd.Flags().Set("path", "")
-	}
-	if repCmd.Flags().Lookup("name") != nil {
-		repCmd.Flags().Set("name", "")
-	}
-	if repCmd.Flags().Lookup("id") != nil {
-		repCmd.Flags().Set("id", "")
-	}
-	if repCmd.Flags().Lookup("path_file_ls") != nil {
-		repCmd.Flags().Set("path_file_ls", "")
-	}
-
-	// Reiniciar flags de mount
-	if mountCmd.Flags().Lookup("path") != nil {
-		mountCmd.Flags().Set("path", "")
-	}
-	if mountCmd.Flags().Lookup("name") != nil {
-		mountCmd.Flags().Set("name", "")
-	}
-
-	// Reiniciar flags de unmount
-	if unmountCmd.Flags().Lookup("id") != nil {
-		unmountCmd.Flags().Set("id", "")
-	}
-
-	// Reiniciar flags de journaling
-	if journalingCmd.Flags().Lookup("id") != nil {
-		journalingCmd.Flags().Set("id", "")
-	}
-
-	// Reiniciar flags de recovery
-	if recoveryCmd.Flags().Lookup("id") != nil {
-		recoveryCmd.Flags().Set("id", "")
-	}
-
-	// Reiniciar flags de loss
-	if lossCmd.Flags().Lookup("id") != nil {
-		lossCmd.Flags().Set("id", "")
-	}
-
-	// Reiniciar flags de partlist
-	if partlistCmd.Flags().Lookup("path") != nil {
-		partlistCmd.Flags().Set("path", "")
-	}
-}
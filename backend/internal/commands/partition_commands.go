@@ -2,377 +2,637 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"disk.simulator.com/m/v2/internal/args"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
 
 	partition_operations "disk.simulator.com/m/v2/internal/disk/operations/partitions"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var partitionRootCmd = &cobra.Command{Use: "partition"} // Renombrar rootCmd a partitionRootCmd
+func newMkfsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mkfs",
+		Short: "Format a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			fsType, _ := cmd.Flags().GetString("type")
+			fs, _ := cmd.Flags().GetString("fs")
+
+			if id == "" {
+				return fmt.Errorf("el id es requerido")
+			}
 
-var mkfsCmd = &cobra.Command{
-	Use:   "mkfs",
-	Short: "Format a partition",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		id, _ := cmd.Flags().GetString("id")
-		fsType, _ := cmd.Flags().GetString("type")
-		fs, _ := cmd.Flags().GetString("fs")
+			// Sin -fs se formatea como ext3
+			var ext3 bool
+			switch strings.ToLower(fs) {
+			case "", "3fs":
+				ext3 = true
+			case "2fs":
+				ext3 = false
+			default:
+				return fmt.Errorf("sistema de archivos inválido '%s' (use 2fs o 3fs)", fs)
+			}
 
-		ext3 := true // Cambiado a true por defecto (ext3)
+			if fsType == "" {
+				fsType = "full" // Valor predeterminado
+			}
 
-		// Solo cambia a false si explícitamente se indica "2fs" (ext2)
-		if fs == "2fs" {
-			ext3 = false
-		}
+			// Normalizar el tipo para comparaciones
+			fsType = strings.ToLower(fsType)
 
-		if id == "" {
-			return fmt.Errorf("el id es requerido")
-		}
+			// Crear el output formateado
+			output := fmt.Sprintf("Formatting partition %s with filesystem type %s", id, fsType)
 
-		if fsType == "" {
-			fsType = "full" // Valor predeterminado
-		}
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		// Normalizar el tipo para comparaciones
-		fsType = strings.ToLower(fsType)
+			// Aquí iría la lógica para formatear la partición
+			err := partition_operations.FormatPartition(id, fsType, ext3)
 
-		// Crear el output formateado
-		output := fmt.Sprintf("Formatting partition %s with filesystem type %s", id, fsType)
+			if err != nil {
+				return err
+			}
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			return nil
+		},
+	}
+}
 
-		// Aquí iría la lógica para formatear la partición
-		err := partition_operations.FormatPartition(id, fsType, ext3)
+func newMkdirCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mkdir",
+		Short: "Create a directory in a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			p, _ := cmd.Flags().GetBool("p")
+
+			if p {
+				fmt.Println("Flag -p es true")
+			} else {
+				fmt.Println("Flag -p es false")
+			}
 
-		if err != nil {
-			return err
-		}
+			if path == "" {
+				return fmt.Errorf("el path es requerido")
+			}
 
-		return nil
-	},
-}
+			// Crear el output formateado
+			output := fmt.Sprintf("Creating directory in partition %s", path)
 
-var mkdirCmd = &cobra.Command{
-	Use:   "mkdir",
-	Short: "Create a directory in a partition",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		p, _ := cmd.Flags().GetBool("p")
-
-		if p {
-			fmt.Println("Flag -p es true")
-		} else {
-			fmt.Println("Flag -p es false")
-		}
-
-		if path == "" {
-			return fmt.Errorf("el path es requerido")
-		}
-
-		// Crear el output formateado
-		output := fmt.Sprintf("Creating directory in partition %s", path)
-
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
-
-		// Aquí iría la lógica para crear el directorio
-		err := partition_operations.CreateDirectory(path, p)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	},
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
+
+			// Aquí iría la lógica para crear el directorio
+			err := partition_operations.CreateDirectory(getSession(cmd), path, p)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
 }
 
-var mkfileCmd = &cobra.Command{
-	Use:   "mkfile",
-	Short: "Create a file in a partition",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get size flag
-		size, _ := cmd.Flags().GetInt("size")
+func newMkfileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mkfile",
+		Short: "Create a file in a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Get size flag
+			size, _ := cmd.Flags().GetInt("size")
+
+			// Get path flag
+			path, _ := cmd.Flags().GetString("path")
+
+			if path == "" {
+				return fmt.Errorf("path is required")
+			}
 
-		// Get path flag
-		path, _ := cmd.Flags().GetString("path")
+			// Verificar que size no sea negativo
+			if size < 0 {
+				return fmt.Errorf("el tamaño (size) no puede ser negativo")
+			}
 
-		if path == "" {
-			return fmt.Errorf("path is required")
-		}
+			// Get content flag
+			content, _ := cmd.Flags().GetString("cont")
 
-		// Verificar que size no sea negativo
-		if size < 0 {
-			return fmt.Errorf("el tamaño (size) no puede ser negativo")
-		}
+			fmt.Println("Content:", content)
+			fmt.Println("Size:", size)
 
-		// Get content flag
-		content, _ := cmd.Flags().GetString("cont")
+			// Eliminamos la validación que exige size>0 o content
+			// Si ambos están vacíos, se creará un archivo vacío con size=0
 
-		fmt.Println("Content:", content)
-		fmt.Println("Size:", size)
+			// Get r bool flag
+			r, _ := cmd.Flags().GetBool("r")
 
-		// Eliminamos la validación que exige size>0 o content
-		// Si ambos están vacíos, se creará un archivo vacío con size=0
+			// Get src flag
+			src, _ := cmd.Flags().GetString("src")
 
-		// Get r bool flag
-		r, _ := cmd.Flags().GetBool("r")
+			if src != "" && (content != "" || size > 0) {
+				return fmt.Errorf("-src no se puede usar junto con -cont o -size")
+			}
 
-		// Create the formatted output
-		output := fmt.Sprintf("Creating file in partition %s", path)
+			// Create the formatted output
+			output := fmt.Sprintf("Creating file in partition %s", path)
 
-		// Write the output to the command output
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Write the output to the command output
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		err := partition_operations.CreateFile(path, size, content, r)
+			// Con -src el archivo del host se copia por bloques, sin cargarlo en memoria
+			if src != "" {
+				file, err := os.Open(src)
+				if err != nil {
+					return fmt.Errorf("error al abrir el archivo %s: %v", src, err)
+				}
+				defer file.Close()
 
-		if err != nil {
-			return err
-		}
+				info, err := file.Stat()
+				if err != nil {
+					return fmt.Errorf("error al leer el archivo %s: %v", src, err)
+				}
 
-		return nil
-	},
+				err = partition_operations.UploadFile(getSession(cmd), path, file, info.Size(), r)
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Copied %d bytes from %s\n", info.Size(), src)
+				return nil
+			}
+
+			err := partition_operations.CreateFile(getSession(cmd), path, size, content, r)
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
 }
 
-var catCmd = &cobra.Command{
-	Use:   "cat",
-	Short: "Display content of one or more files",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Obtener todos los flags desde el comando
-		flags := cmd.Flags()
-		var fileContents []string
-		filesFound := false
-
-		// Buscar todos los flags y procesar los que empiezan con "file"
-		flags.VisitAll(func(flag *pflag.Flag) {
-			if strings.HasPrefix(flag.Name, "file") && flag.Changed {
-				filesFound = true
-				filePath := flag.Value.String()
-				if filePath != "" {
-					content, err := partition_operations.CatFile(filePath)
-					if err != nil {
-						fmt.Fprintf(cmd.OutOrStderr(), "Error leyendo archivo %s: %v\n", filePath, err)
-					} else {
-						fileContents = append(fileContents,
-							fmt.Sprintf("=== %s ===\n%s", filePath, content))
+func newCatCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cat",
+		Short: "Display content of one or more files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Obtener todos los flags desde el comando
+			flags := cmd.Flags()
+			var fileContents []string
+			filesFound := false
+
+			// Buscar todos los flags y procesar los que empiezan con "file"
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if strings.HasPrefix(flag.Name, "file") && flag.Changed {
+					filesFound = true
+					filePath := flag.Value.String()
+					if filePath != "" {
+						content, err := partition_operations.CatFile(getSession(cmd), filePath)
+						if err != nil {
+							fmt.Fprintf(cmd.OutOrStderr(), "Error leyendo archivo %s: %v\n", filePath, err)
+						} else {
+							fileContents = append(fileContents,
+								fmt.Sprintf("=== %s ===\n%s", filePath, content))
+						}
 					}
 				}
+			})
+
+			if !filesFound {
+				return fmt.Errorf("debe especificar al menos un archivo (ej: --file1=ruta)")
+			}
+
+			// Mostrar contenido de cada archivo
+			output := strings.Join(fileContents, "\n\n")
+			fmt.Fprintln(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+}
+
+func newRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+
+			if path == "" {
+				return fmt.Errorf("path is required")
 			}
-		})
 
-		if !filesFound {
-			return fmt.Errorf("debe especificar al menos un archivo (ej: --file1=ruta)")
-		}
+			// Crear el output formateado
+			output := fmt.Sprintf("Removing file or directory in partition %s", path)
 
-		// Mostrar contenido de cada archivo
-		output := strings.Join(fileContents, "\n\n")
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			err := partition_operations.RemoveFileOrDirectory(getSession(cmd), path)
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
 }
 
-var removeCmd = &cobra.Command{
-	Use:   "remove",
-	Short: "Remove a file or directory",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
+func newEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit a file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			contenido, _ := cmd.Flags().GetString("contenido")
+
+			if path == "" {
+				return fmt.Errorf("path is required")
+			}
 
-		if path == "" {
-			return fmt.Errorf("path is required")
-		}
+			if contenido == "" {
+				return fmt.Errorf("contenido is required")
+			}
 
-		// Crear el output formateado
-		output := fmt.Sprintf("Removing file or directory in partition %s", path)
+			// Crear el output formateado
+			output := fmt.Sprintf("Editing file in partition %s", path)
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		err := partition_operations.RemoveFileOrDirectory(path)
+			err := partition_operations.EditFile(getSession(cmd), path, contenido)
 
-		if err != nil {
-			return err
-		}
+			if err != nil {
+				return err
+			}
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var editCmd = &cobra.Command{
-	Use:   "edit",
-	Short: "Edit a file",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		contenido, _ := cmd.Flags().GetString("contenido")
+func newRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename",
+		Short: "Rename a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldPath, _ := cmd.Flags().GetString("path")
+			newName, _ := cmd.Flags().GetString("name")
 
-		if path == "" {
-			return fmt.Errorf("path is required")
-		}
+			if oldPath == "" || newName == "" {
+				return fmt.Errorf("se requieren tanto el path como el nuevo nombre")
+			}
 
-		if contenido == "" {
-			return fmt.Errorf("contenido is required")
-		}
+			output := fmt.Sprintf("Renombrando %s a %s", oldPath, newName)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		// Crear el output formateado
-		output := fmt.Sprintf("Editing file in partition %s", path)
+			return partition_operations.RenameFile(getSession(cmd), oldPath, newName)
+		},
+	}
+}
 
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+func newCopyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "copy",
+		Short: "Copy a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, _ := cmd.Flags().GetString("path")
+			dest, _ := cmd.Flags().GetString("destino")
 
-		err := partition_operations.EditFile(path, contenido)
+			if source == "" || dest == "" {
+				return fmt.Errorf("se requieren tanto el source como el dest")
+			}
 
-		if err != nil {
-			return err
-		}
+			output := fmt.Sprintf("Copiando %s a %s", source, dest)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return partition_operations.CopyFileOrDirectory(getSession(cmd), source, dest)
+		},
+	}
 }
 
-var renameCmd = &cobra.Command{
-	Use:   "rename",
-	Short: "Rename a file or directory",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		oldPath, _ := cmd.Flags().GetString("path")
-		newName, _ := cmd.Flags().GetString("name")
+func newMoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "move",
+		Short: "Move a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, _ := cmd.Flags().GetString("path")
+			dest, _ := cmd.Flags().GetString("destino")
 
-		if oldPath == "" || newName == "" {
-			return fmt.Errorf("se requieren tanto el path como el nuevo nombre")
-		}
+			if source == "" || dest == "" {
+				return fmt.Errorf("se requieren tanto el source como el dest")
+			}
 
-		output := fmt.Sprintf("Renombrando %s a %s", oldPath, newName)
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			output := fmt.Sprintf("Moviendo %s a %s", source, dest)
+			fmt.Fprintln(cmd.OutOrStdout(), output)
 
-		return partition_operations.RenameFile(oldPath, newName)
-	},
+			return partition_operations.MoveFileOrDirectory(getSession(cmd), source, dest)
+		},
+	}
 }
 
-var copyCmd = &cobra.Command{
-	Use:   "copy",
-	Short: "Copy a file or directory",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		source, _ := cmd.Flags().GetString("path")
-		dest, _ := cmd.Flags().GetString("destino")
+func newFindCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "find",
+		Short: "Find a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			name, _ := cmd.Flags().GetString("name")
 
-		if source == "" || dest == "" {
-			return fmt.Errorf("se requieren tanto el source como el dest")
-		}
+			if path == "" || name == "" {
+				return fmt.Errorf("se requieren tanto el path como el nombre")
+			}
+
+			output, err := partition_operations.FindFileOrFolderTree(getSession(cmd), path, name)
 
-		output := fmt.Sprintf("Copiando %s a %s", source, dest)
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			if err != nil {
+				return fmt.Errorf("error al buscar: %v", err)
+			}
 
-		return partition_operations.CopyFileOrDirectory(source, dest)
-	},
+			fmt.Fprintln(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
 }
 
-var moveCmd = &cobra.Command{
-	Use:   "move",
-	Short: "Move a file or directory",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		source, _ := cmd.Flags().GetString("path")
-		dest, _ := cmd.Flags().GetString("destino")
+func newChownCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "chown",
+		Short: "Cambiar propietario de un archivo o directorio",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			usuario, _ := cmd.Flags().GetString("usuario")
+			r, _ := cmd.Flags().GetBool("r")
+
+			if path == "" {
+				return fmt.Errorf("error: se requiere la ruta del archivo o directorio (--path)")
+			}
 
-		if source == "" || dest == "" {
-			return fmt.Errorf("se requieren tanto el source como el dest")
-		}
+			if usuario == "" {
+				return fmt.Errorf("error: se requiere el nombre de usuario (--usuario)")
+			}
 
-		output := fmt.Sprintf("Moviendo %s a %s", source, dest)
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			// Crear el output formateado
+			output := fmt.Sprintf("Cambiando propietario de %s al usuario %s", path, usuario)
+			if r {
+				output += " (recursivamente)"
+			}
 
-		return partition_operations.MoveFileOrDirectory(source, dest)
-	},
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
+
+			return partition_operations.ChangeOwner(getSession(cmd), path, usuario, r)
+		},
+	}
 }
 
-var findCmd = &cobra.Command{
-	Use:   "find",
-	Short: "Find a file or directory",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		name, _ := cmd.Flags().GetString("name")
+func newChmodCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "chmod",
+		Short: "Cambiar permisos de un archivo o directorio",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			ugo, _ := cmd.Flags().GetString("ugo")
+			r, _ := cmd.Flags().GetBool("r")
+
+			if path == "" {
+				return fmt.Errorf("error: se requiere la ruta del archivo o directorio (--path)")
+			}
+
+			if ugo == "" {
+				return fmt.Errorf("error: se requieren los permisos en formato [0-7][0-7][0-7] (--ugo)")
+			}
+
+			// Crear el output formateado
+			output := fmt.Sprintf("Cambiando permisos de %s a %s", path, ugo)
+			if r {
+				output += " (recursivamente)"
+			}
+
+			// Escribir el output en la salida del comando
+			fmt.Fprintln(cmd.OutOrStdout(), output)
+
+			return partition_operations.ChangePermissions(getSession(cmd), path, ugo, r)
+		},
+	}
+}
+
+func newDfCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "df",
+		Short: "Show free and used inodes and blocks of a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
 
-		if path == "" || name == "" {
-			return fmt.Errorf("se requieren tanto el path como el nombre")
-		}
+			// Si no se indica el id se usa la partición de la sesión
+			if id == "" {
+				id = getSession(cmd).ID
+			}
 
-		output, err := partition_operations.FindFileOrFolderTree(path, name)
+			if id == "" {
+				return fmt.Errorf("el id es requerido")
+			}
 
-		if err != nil {
-			return fmt.Errorf("error al buscar: %v", err)
-		}
+			output, err := partition_operations.DiskFree(id)
+			if err != nil {
+				return err
+			}
 
-		fmt.Fprintln(cmd.OutOrStdout(), output)
+			fmt.Fprint(cmd.OutOrStdout(), output)
 
-		return nil
-	},
+			return nil
+		},
+	}
 }
 
-var chownCmd = &cobra.Command{
-	Use:   "chown",
-	Short: "Cambiar propietario de un archivo o directorio",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		usuario, _ := cmd.Flags().GetString("usuario")
-		r, _ := cmd.Flags().GetBool("r")
-
-		if path == "" {
-			return fmt.Errorf("error: se requiere la ruta del archivo o directorio (--path)")
-		}
-
-		if usuario == "" {
-			return fmt.Errorf("error: se requiere el nombre de usuario (--usuario)")
-		}
-
-		// Crear el output formateado
-		output := fmt.Sprintf("Cambiando propietario de %s al usuario %s", path, usuario)
-		if r {
-			output += " (recursivamente)"
-		}
-
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
-
-		return partition_operations.ChangeOwner(path, usuario, r)
-	},
+func newDuCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "du",
+		Short: "Show disk usage of a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			r, _ := cmd.Flags().GetBool("r")
+
+			if path == "" {
+				return fmt.Errorf("el path es requerido")
+			}
+
+			output, err := partition_operations.DiskUsage(getSession(cmd), path, r)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
 }
 
-var chmodCmd = &cobra.Command{
-	Use:   "chmod",
-	Short: "Cambiar permisos de un archivo o directorio",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		path, _ := cmd.Flags().GetString("path")
-		ugo, _ := cmd.Flags().GetString("ugo")
-		r, _ := cmd.Flags().GetBool("r")
-
-		if path == "" {
-			return fmt.Errorf("error: se requiere la ruta del archivo o directorio (--path)")
-		}
-
-		if ugo == "" {
-			return fmt.Errorf("error: se requieren los permisos en formato [0-7][0-7][0-7] (--ugo)")
-		}
-
-		// Crear el output formateado
-		output := fmt.Sprintf("Cambiando permisos de %s a %s", path, ugo)
-		if r {
-			output += " (recursivamente)"
-		}
-
-		// Escribir el output en la salida del comando
-		fmt.Fprintln(cmd.OutOrStdout(), output)
-
-		return partition_operations.ChangePermissions(path, ugo, r)
-	},
+func newStatCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stat",
+		Short: "Show the inode of a file or directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+
+			if path == "" {
+				return fmt.Errorf("el path es requerido")
+			}
+
+			output, err := partition_operations.StatFile(getSession(cmd), path)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
 }
 
-func init() {
+func newFsckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "fsck",
+		Short: "Check and repair the filesystem of a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			fix, _ := cmd.Flags().GetBool("fix")
+
+			if id == "" {
+				return fmt.Errorf("el id es requerido")
+			}
+
+			output, err := partition_operations.CheckFilesystem(id, fix)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+}
+
+func newTuneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tune",
+		Short: "Change filesystem parameters of a partition without formatting it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			journal, _ := cmd.Flags().GetBool("journal")
+
+			if id == "" {
+				return fmt.Errorf("el id es requerido")
+			}
+
+			output, err := partition_operations.TunePartition(id, journal)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+}
+
+func newExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "Export the files of a partition to a tar archive on the host",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			dest, _ := cmd.Flags().GetString("dest")
+
+			if id == "" {
+				return fmt.Errorf("el id es requerido")
+			}
+
+			if dest == "" {
+				return fmt.Errorf("el destino es requerido")
+			}
+
+			output, err := partition_operations.ExportPartition(getSession(cmd), id, dest)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+}
+
+func newImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import",
+		Short: "Import the files of a tar archive from the host into a partition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := cmd.Flags().GetString("id")
+			src, _ := cmd.Flags().GetString("src")
+			path, _ := cmd.Flags().GetString("path")
+
+			if id == "" {
+				return fmt.Errorf("el id es requerido")
+			}
+
+			if src == "" {
+				return fmt.Errorf("el archivo de origen es requerido")
+			}
+
+			output, err := partition_operations.ImportPartition(getSession(cmd), id, src, path)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), output)
+
+			return nil
+		},
+	}
+}
+
+// newPartitionRootCmd construye un árbol de comandos nuevo en cada ejecución para que los
+// flags de una línea no se mezclen con los de otra ni con peticiones concurrentes
+func newPartitionRootCmd() *cobra.Command {
+	partitionRootCmd := &cobra.Command{Use: "partition"}
+	mkfsCmd := newMkfsCmd()
+	mkdirCmd := newMkdirCmd()
+	mkfileCmd := newMkfileCmd()
+	catCmd := newCatCmd()
+	removeCmd := newRemoveCmd()
+	editCmd := newEditCmd()
+	renameCmd := newRenameCmd()
+	copyCmd := newCopyCmd()
+	moveCmd := newMoveCmd()
+	findCmd := newFindCmd()
+	chownCmd := newChownCmd()
+	chmodCmd := newChmodCmd()
+	dfCmd := newDfCmd()
+	duCmd := newDuCmd()
+	statCmd := newStatCmd()
+	fsckCmd := newFsckCmd()
+	tuneCmd := newTuneCmd()
+	exportCmd := newExportCmd()
+	importCmd := newImportCmd()
+
 	// MKFS
 	partitionRootCmd.AddCommand(mkfsCmd)
 	mkfsCmd.PersistentFlags().StringP("id", "i", "", "ID of the partition") // Agregar alias -i para --id
 	mkfsCmd.MarkPersistentFlagRequired("id")
 	mkfsCmd.Flags().StringP("type", "t", "full", "Filesystem type (ext4, ntfs, etc.)") // Agregar alias -t para --type
-	mkfsCmd.Flags().StringP("fs", "e", "", "Use ext3 filesystem")                      // Agregar alias -e para --ext3
+	mkfsCmd.Flags().StringP("fs", "e", "", "Filesystem: 2fs (ext2) or 3fs (ext3)")     // Agregar alias -e para --fs
 	// MKDIR
 	partitionRootCmd.AddCommand(mkdirCmd)
 	mkdirCmd.PersistentFlags().StringP("path", "a", "", "Path of the directory") // Agregar alias -p para --path
@@ -385,6 +645,7 @@ func init() {
 	mkfileCmd.MarkPersistentFlagRequired("path")
 	mkfileCmd.PersistentFlags().IntP("size", "s", 0, "Size of the file in bytes (opcional si se proporciona content)")
 	mkfileCmd.PersistentFlags().StringP("cont", "c", "", "Content of the file or path to file using @/path/to/file format (opcional si se proporciona size)")
+	mkfileCmd.PersistentFlags().String("src", "", "Path of a host file to copy into the partition (binary or large files)")
 	mkfileCmd.Flags().BoolP("r", "r", false, "Create parent directories automatically")
 
 	// REMOVE
@@ -449,15 +710,62 @@ func init() {
 	chmodCmd.PersistentFlags().BoolP("r", "r", false, "Cambiar permisos recursivamente")
 	chmodCmd.MarkPersistentFlagRequired("path")
 	chmodCmd.MarkPersistentFlagRequired("ugo")
+
+	// DF
+	partitionRootCmd.AddCommand(dfCmd)
+	dfCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición")
+
+	// DU
+	partitionRootCmd.AddCommand(duCmd)
+	duCmd.PersistentFlags().StringP("path", "p", "", "Ruta del archivo/directorio")
+	duCmd.PersistentFlags().BoolP("r", "r", false, "Mostrar el uso de cada entrada recursivamente")
+	duCmd.MarkPersistentFlagRequired("path")
+
+	// STAT
+	partitionRootCmd.AddCommand(statCmd)
+	statCmd.PersistentFlags().StringP("path", "p", "", "Ruta del archivo/directorio")
+	statCmd.MarkPersistentFlagRequired("path")
+
+	// FSCK
+	partitionRootCmd.AddCommand(fsckCmd)
+	fsckCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición")
+	fsckCmd.PersistentFlags().BoolP("fix", "f", false, "Reparar los problemas encontrados")
+	fsckCmd.MarkPersistentFlagRequired("id")
+
+	// TUNE
+	partitionRootCmd.AddCommand(tuneCmd)
+	tuneCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición")
+	tuneCmd.PersistentFlags().BoolP("journal", "j", false, "Agregar journaling (convertir ext2 a ext3)")
+	tuneCmd.MarkPersistentFlagRequired("id")
+
+	// EXPORT
+	partitionRootCmd.AddCommand(exportCmd)
+	exportCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición")
+	exportCmd.PersistentFlags().StringP("dest", "d", "", "Ruta del archivo tar en el host")
+	exportCmd.MarkPersistentFlagRequired("id")
+	exportCmd.MarkPersistentFlagRequired("dest")
+
+	// IMPORT
+	partitionRootCmd.AddCommand(importCmd)
+	importCmd.PersistentFlags().StringP("id", "i", "", "ID de la partición")
+	importCmd.PersistentFlags().StringP("src", "s", "", "Ruta del archivo tar en el host")
+	importCmd.PersistentFlags().StringP("path", "p", "/", "Carpeta de la partición donde se extrae el archivo")
+	importCmd.MarkPersistentFlagRequired("id")
+	importCmd.MarkPersistentFlagRequired("src")
+
+	return partitionRootCmd
 }
 
 // ParsePartitionCommand analiza y ejecuta un comando de partición
-func ParsePartitionCommand(command string, data string) (string, error) {
+func ParsePartitionCommand(session *auth.LoggedUser, command string, data string) (string, error) {
 	// Divide los argumentos respetando las comillas y los flags con valores unidos por "="
-	args := args.SplitArgs(data)
+	args, err := args.ParseArgs(data)
+	if err != nil {
+		return "", err
+	}
 
-	// Reiniciar los flags a sus valores predeterminados antes de ejecutar un nuevo comando
-	resetPartitionFlags()
+	// Cada ejecución usa su propio árbol de comandos, con los flags en sus valores predeterminados
+	partitionRootCmd := newPartitionRootCmd()
 
 	// Configura los argumentos para cobra
 	partitionRootCmd.SetArgs(args)
@@ -467,7 +775,7 @@ func ParsePartitionCommand(command string, data string) (string, error) {
 	partitionRootCmd.SetOut(output)
 
 	// Ejecuta el comando
-	err := partitionRootCmd.Execute()
+	err = partitionRootCmd.ExecuteContext(sessionContext(session))
 	if err != nil {
 		return "", err
 	}
@@ -480,82 +788,3 @@ func ParsePartitionCommand(command string, data string) (string, error) {
 	// Devolver la salida capturada
 	return output.String(), nil
 }
-
-// resetPartitionFlags reinicia los valores de todos los flags a sus valores predeterminados
-func resetPartitionFlags() {
-	// Reiniciar flags de mkfs
-	if mkfsCmd.Flags().Lookup("id") != nil {
-		mkfsCmd.Flags().Set("id", "")
-	}
-	if mkfsCmd.Flags().Lookup("type") != nil {
-		mkfsCmd.Flags().Set("type", "full")
-	}
-
-	// Reiniciar flags de mkdir
-	if mkdirCmd.Flags().Lookup("path") != nil {
-		mkdirCmd.Flags().Set("path", "")
-	}
-	if mkdirCmd.Flags().Lookup("p") != nil {
-		mkdirCmd.Flags().Set("p", "false")
-	}
-
-	// Reiniciar flags de mkfile
-	if mkfileCmd.Flags().Lookup("path") != nil {
-		mkfileCmd.Flags().Set("path", "")
-	}
-	if mkfileCmd.Flags().Lookup("size") != nil {
-		mkfileCmd.Flags().Set("size", "0")
-	}
-	if mkfileCmd.Flags().Lookup("cont") != nil {
-		mkfileCmd.Flags().Set("cont", "")
-	}
-	if mkfileCmd.Flags().Lookup("r") != nil {
-		mkfileCmd.Flags().Set("r", "false")
-	}
-
-	// Reiniciar flags de cat (reiniciar todos los posibles file1...file10)
-	for i := 1; i <= 10; i++ {
-		flagName := fmt.Sprintf("file%d", i)
-		if catCmd.Flags().Lookup(flagName) != nil {
-			catCmd.Flags().Set(flagName, "")
-		}
-	}
-
-	// Reiniciar flags de copy
-	if copyCmd.Flags().Lookup("path") != nil {
-		copyCmd.Flags().Set("path", "")
-	}
-	if copyCmd.Flags().Lookup("destino") != nil {
-		copyCmd.Flags().Set("destino", "")
-	}
-
-	// Reiniciar flags de move
-	if moveCmd.Flags().Lookup("path") != nil {
-		moveCmd.Flags().Set("path", "")
-	}
-	if moveCmd.Flags().Lookup("destino") != nil {
-		moveCmd.Flags().Set("destino", "")
-	}
-
-	// Reiniciar flags de chown
-	if chownCmd.Flags().Lookup("path") != nil {
-		chownCmd.Flags().Set("path", "")
-	}
-	if chownCmd.Flags().Lookup("usuario") != nil {
-		chownCmd.Flags().Set("usuario", "")
-	}
-	if chownCmd.Flags().Lookup("r") != nil {
-		chownCmd.Flags().Set("r", "false")
-	}
-
-	// Reiniciar flags de chmod
-	if chmodCmd.Flags().Lookup("path") != nil {
-		chmodCmd.Flags().Set("path", "")
-	}
-	if chmodCmd.Flags().Lookup("ugo") != nil {
-		chmodCmd.Flags().Set("ugo", "")
-	}
-	if chmodCmd.Flags().Lookup("r") != nil {
-		chmodCmd.Flags().Set("r", "false")
-	}
-}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"context"
+
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	"github.com/spf13/cobra"
+)
+
+// sessionKey es la llave con la que se guarda la sesión del cliente en el contexto de cobra
+type sessionKey struct{}
+
+// sessionContext crea el contexto con la sesión del cliente para ejecutar un árbol de
+// comandos; cobra lo hereda a los subcomandos porque el árbol se construye nuevo cada vez
+func sessionContext(session *auth.LoggedUser) context.Context {
+	return context.WithValue(context.Background(), sessionKey{}, session)
+}
+
+// getSession obtiene la sesión del cliente que ejecuta el comando
+func getSession(cmd *cobra.Command) *auth.LoggedUser {
+	if ctx := cmd.Context(); ctx != nil {
+		if session, ok := ctx.Value(sessionKey{}).(*auth.LoggedUser); ok && session != nil {
+			return session
+		}
+	}
+	return &auth.LoggedUser{}
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+)
+
+// diskLocks guarda un candado de lectura/escritura por archivo de disco. Las
+// operaciones que solo leen (cat, ls, reportes) pueden ejecutarse en paralelo,
+// mientras que las que modifican el disco se ejecutan de una en una
+var diskLocks sync.Map
+
+// diskLock retorna el candado del disco, creándolo si todavía no existe
+func diskLock(diskPath string) *sync.RWMutex {
+	key := filepath.Clean(diskPath)
+	if abs, err := filepath.Abs(key); err == nil {
+		key = abs
+	}
+
+	lock, _ := diskLocks.LoadOrStore(key, &sync.RWMutex{})
+	return lock.(*sync.RWMutex)
+}
+
+// LockDisk bloquea el disco para escritura y retorna la función que lo libera:
+//
+//	defer memory.LockDisk(diskPath)()
+//
+// Al liberarlo se escriben en el archivo los cambios que quedaron en la caché
+// del handle de la partición
+func LockDisk(diskPath string) func() {
+	lock := diskLock(diskPath)
+	lock.Lock()
+	ext2.RefreshPartition(diskPath)
+
+	return func() {
+		if err := ext2.SyncPartition(diskPath); err != nil {
+			fmt.Printf("Advertencia: no se pudo sincronizar el disco %s: %v\n", diskPath, err)
+		}
+		lock.Unlock()
+	}
+}
+
+// RLockDisk bloquea el disco para lectura y retorna la función que lo libera
+func RLockDisk(diskPath string) func() {
+	lock := diskLock(diskPath)
+	lock.RLock()
+	ext2.RefreshPartition(diskPath)
+
+	return lock.RUnlock
+}
@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/types/structures"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+)
+
+// Variable de entorno que permite indicar dónde se guarda el estado de montaje.
+// Su directorio es también el de los demás archivos de estado del servidor
+const mountStateEnv = "MOUNT_STATE_PATH"
+
+// Nombre del archivo de estado de montaje cuando no se indica una ruta
+const mountStateFileName = "mount_state.json"
+
+// StateFilePath devuelve la ruta de un archivo de estado del servidor, como el
+// registro de discos o la tabla de montaje. Todos se guardan juntos: en el
+// directorio de MOUNT_STATE_PATH si está definida o, si no, en el directorio de
+// trabajo, donde siempre se guardó disk_registry.json
+func StateFilePath(name string) string {
+	dir := "."
+	if statePath := os.Getenv(mountStateEnv); statePath != "" {
+		dir = filepath.Dir(statePath)
+	}
+	return filepath.Join(dir, name)
+}
+
+// mountStateFilePath devuelve la ruta del archivo de estado de montaje
+func mountStateFilePath() string {
+	if statePath := os.Getenv(mountStateEnv); statePath != "" {
+		return statePath
+	}
+	return StateFilePath(mountStateFileName)
+}
+
+// mountState es la representación persistida de la tabla de montaje
+type mountState struct {
+	MountedPartitions []MountedPartition `json:"mounted_partitions"`
+	DiskLetters       map[string]byte    `json:"disk_letters"`
+	PartitionCounts   map[string]int     `json:"partition_counts"`
+}
+
+// saveStateToFile guarda la tabla de montaje en un archivo JSON.
+// Se debe llamar con el mutex del Storage tomado.
+func (s *Storage) saveStateToFile() {
+	statePath := mountStateFilePath()
+
+	// Asegurar que exista el directorio
+	dir := filepath.Dir(statePath)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return
+	}
+
+	state := mountState{
+		MountedPartitions: s.mountedPartitions,
+		DiskLetters:       s.diskLetters,
+		PartitionCounts:   s.partitionCounts,
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+
+	// Si hay un error al escribir, simplemente continuamos con el estado en memoria
+	_ = os.WriteFile(statePath, data, 0644)
+}
+
+// loadStateFromFile restaura la tabla de montaje desde el archivo JSON,
+// descartando las entradas que ya no coinciden con el MBR/EBR del disco
+func (s *Storage) loadStateFromFile() {
+	statePath := mountStateFilePath()
+	if _, err := os.Stat(statePath); os.IsNotExist(err) {
+		return
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return
+	}
+
+	var state mountState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return
+	}
+
+	// Las letras y contadores se conservan aunque se descarten entradas,
+	// de lo contrario un disco nuevo podría recibir una letra ya asignada
+	for path, letter := range state.DiskLetters {
+		s.diskLetters[path] = letter
+	}
+	for path, count := range state.PartitionCounts {
+		s.partitionCounts[path] = count
+	}
+
+	for _, mounted := range state.MountedPartitions {
+		if !isMountStillValid(mounted) {
+			continue
+		}
+		s.mountedPartitions = append(s.mountedPartitions, mounted)
+
+		if mounted.UnmountTime.Before(mounted.MountTime) {
+			if _, err := ext2.OpenPartition(mounted.Path); err != nil {
+				fmt.Printf("Advertencia: %v\n", err)
+			}
+		}
+
+		// Reaplicar las transacciones confirmadas que no llegaron a escribirse antes del reinicio
+		replayed, discarded, err := ext2.ReplayJournal(mounted.Path, mounted.Partition.Part_start)
+		if err != nil {
+			fmt.Printf("Advertencia: no se pudo recuperar el journal de %s: %v\n", mounted.ID, err)
+		} else if replayed > 0 || discarded > 0 {
+			fmt.Printf("Journal de %s: %d transacciones reaplicadas, %d incompletas descartadas\n", mounted.ID, replayed, discarded)
+		}
+	}
+}
+
+// isMountStillValid verifica que una entrada persistida siga correspondiendo
+// a una partición existente en el disco
+func isMountStillValid(mounted MountedPartition) bool {
+	if _, err := os.Stat(mounted.Path); err != nil {
+		return false
+	}
+
+	mbr := structures.MBR{}
+	if err := mbr.DeserializeMBR(mounted.Path); err != nil {
+		return false
+	}
+
+	// Las particiones lógicas no tienen Part_id en el EBR, así que se valida que la
+	// cadena de EBRs de alguna extendida tenga uno con el mismo nombre y tamaño
+	// justo antes del inicio de la partición
+	if mounted.Partition.Part_type == 'L' {
+		for _, part := range mbr.Mbr_partitions {
+			if part.Part_status == '1' && part.Part_type == 'E' && hasLogicalPartition(mounted, part) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var id [4]byte
+	copy(id[:], mounted.ID)
+
+	for _, part := range mbr.Mbr_partitions {
+		if part.Part_status != '1' || part.Part_start != mounted.Partition.Part_start {
+			continue
+		}
+
+		partName := string(bytes.Trim(part.Part_name[:], "\x00"))
+		if partName != mounted.Name {
+			continue
+		}
+
+		return part.Part_id == id && part.Part_correlative == mounted.Partition.Part_correlative
+	}
+
+	return false
+}
+
+// hasLogicalPartition recorre la cadena de EBRs de una partición extendida y
+// verifica que la partición lógica montada siga en la misma posición
+func hasLogicalPartition(mounted MountedPartition, extended structures.Partition) bool {
+	extendedEnd := extended.Part_start + extended.Part_size
+	position := extended.Part_start
+
+	// Cada EBR está más adelante que el anterior; si no, la cadena está dañada
+	for position >= extended.Part_start && position+structures.EBRSize <= extendedEnd {
+		ebr := structures.EBR{}
+		if err := ebr.DeserializeEBR(mounted.Path, position); err != nil {
+			return false
+		}
+
+		if position+structures.EBRSize == mounted.Partition.Part_start {
+			ebrName := strings.TrimSpace(string(bytes.Trim(ebr.Part_name[:], "\x00")))
+			return ebr.Part_size != -1 &&
+				ebrName == mounted.Name &&
+				ebr.Part_size-structures.EBRSize == mounted.Partition.Part_size
+		}
+
+		if ebr.Part_next <= position {
+			return false
+		}
+		position = ebr.Part_next
+	}
+
+	return false
+}
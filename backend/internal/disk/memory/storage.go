@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"disk.simulator.com/m/v2/internal/disk/types/structures"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
 )
 
 // MountedPartition representa una partición montada en memoria
@@ -41,6 +42,8 @@ func GetInstance() *Storage {
 			diskLetters:       make(map[string]byte),
 			partitionCounts:   make(map[string]int),
 		}
+		// Restaurar las particiones montadas antes del último reinicio
+		instance.loadStateFromFile()
 	})
 	return instance
 }
@@ -70,6 +73,7 @@ func (s *Storage) MountPartition(name string, path string, partition structures.
 		// Actualizar la fecha de montaje y el contador
 		s.mountedPartitions[index].MountTime = time.Now()
 		s.mountedPartitions[index].MountCount++
+		s.saveStateToFile()
 		return s.mountedPartitions[index].ID, nil
 	}
 
@@ -92,6 +96,10 @@ func (s *Storage) MountPartition(name string, path string, partition structures.
 	// Crear el ID con el formato número+letra
 	id := fmt.Sprintf("76%d%c", partitionNumber, diskLetter)
 
+	// Registrar el correlativo y el ID para poder validarlos contra el MBR al reiniciar
+	partition.Part_correlative = int32(partitionNumber)
+	copy(partition.Part_id[:], id)
+
 	newMounted := MountedPartition{
 		ID:         id,
 		Name:       name,
@@ -102,9 +110,36 @@ func (s *Storage) MountPartition(name string, path string, partition structures.
 	}
 
 	s.mountedPartitions = append(s.mountedPartitions, newMounted)
+	s.saveStateToFile()
 	return id, nil
 }
 
+// RevertMount deshace un MountPartition cuyo montaje no se pudo completar. Si la
+// partición ya estaba montada se restaura la entrada anterior; si no, se quita
+// de la tabla y se libera su correlativo cuando fue el último asignado
+func (s *Storage) RevertMount(id string, previous *MountedPartition) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, partition := range s.mountedPartitions {
+		if partition.ID != id {
+			continue
+		}
+
+		if previous != nil {
+			s.mountedPartitions[i] = *previous
+		} else {
+			diskPath := filepath.Clean(partition.Path)
+			if s.partitionCounts[diskPath] == int(partition.Partition.Part_correlative) {
+				s.partitionCounts[diskPath]--
+			}
+			s.mountedPartitions = append(s.mountedPartitions[:i], s.mountedPartitions[i+1:]...)
+		}
+		s.saveStateToFile()
+		return
+	}
+}
+
 // Agregamos una función para desmontar la partición
 func (s *Storage) UnmountPartition(id string) error {
 	s.mutex.Lock()
@@ -113,6 +148,7 @@ func (s *Storage) UnmountPartition(id string) error {
 	for i, partition := range s.mountedPartitions {
 		if partition.ID == id {
 			s.mountedPartitions[i].UnmountTime = time.Now()
+			s.saveStateToFile()
 			return nil
 		}
 	}
@@ -141,3 +177,33 @@ func (s *Storage) GetMountedPartitions() []MountedPartition {
 
 	return s.mountedPartitions
 }
+
+// HasMountedPartitions indica si alguna partición del disco sigue montada
+func (s *Storage) HasMountedPartitions(path string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, mounted := range s.mountedPartitions {
+		if filepath.Clean(mounted.Path) == filepath.Clean(path) && mounted.UnmountTime.Before(mounted.MountTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset olvida todas las particiones montadas y las letras asignadas a los
+// discos, cerrando sus handles. Las pruebas lo usan para que cada script
+// empiece con la tabla de montaje vacía
+func (s *Storage) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, mounted := range s.mountedPartitions {
+		ext2.ClosePartition(mounted.Path)
+	}
+
+	s.mountedPartitions = make([]MountedPartition, 0)
+	s.diskLetters = make(map[string]byte)
+	s.partitionCounts = make(map[string]int)
+	s.saveStateToFile()
+}
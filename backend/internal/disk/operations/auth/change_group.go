@@ -8,8 +8,7 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func ChangeGroup(username string, groupname string) error {
-	userData := GetInstance()
+func ChangeGroup(userData *LoggedUser, username string, groupname string) error {
 
 	if userData.User == nil {
 		return fmt.Errorf("error al cambiar grupo: no hay un usuario loggeado")
@@ -24,6 +23,15 @@ func ChangeGroup(username string, groupname string) error {
 		return err
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := &ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 
@@ -64,5 +72,11 @@ func ChangeGroup(username string, groupname string) error {
 		return fmt.Errorf("error al guardar SuperBlock: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	return nil
 }
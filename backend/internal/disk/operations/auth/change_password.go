@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"fmt"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// ChangePassword cambia la contraseña de un usuario. Sin username (o con el propio)
+// se cambia la contraseña del usuario loggeado y se exige la contraseña actual;
+// para otro usuario solo root puede restablecerla sin conocer la anterior.
+func ChangePassword(userData *LoggedUser, username string, oldPassword string, newPassword string) error {
+
+	if userData.User == nil {
+		return fmt.Errorf("error al cambiar contraseña: no hay un usuario loggeado")
+	}
+
+	if newPassword == "" {
+		return fmt.Errorf("error al cambiar contraseña: la nueva contraseña no puede estar vacía")
+	}
+
+	if username == "" {
+		username = userData.User.Username
+	}
+
+	isOwnPassword := username == userData.User.Username
+
+	if !isOwnPassword && userData.User.Group != "root" {
+		return fmt.Errorf("error al cambiar contraseña: solo root puede restablecer la contraseña de otro usuario")
+	}
+
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(userData.ID)
+	if err != nil {
+		return err
+	}
+
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	superBlock := &ext2.SuperBlock{}
+	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
+
+	content, err := superBlock.ReadFile(partitionPath, []string{}, "users.txt")
+	if err != nil {
+		return err
+	}
+
+	userFound, index := utils.FindUserInFile(content, username)
+	if userFound == nil {
+		return fmt.Errorf("error al cambiar contraseña: el usuario %s no existe", username)
+	}
+
+	if isOwnPassword && !VerifyPassword(userFound.Password, oldPassword) {
+		return fmt.Errorf("error al cambiar contraseña: la contraseña actual es incorrecta")
+	}
+
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("error al cambiar contraseña: no se pudo generar el hash: %v", err)
+	}
+
+	newLine := fmt.Sprintf("%s,U,%s,%s,%s", userFound.UID, userFound.Group, userFound.Username, hash)
+	content = utils.ReplaceLine(content, index, newLine)
+
+	err = superBlock.UpdateFile(partitionPath, []string{}, "users.txt", content)
+	if err != nil {
+		return err
+	}
+
+	// Mantener sincronizado el usuario de la sesión si cambió su propia contraseña
+	if isOwnPassword {
+		userData.User.Password = hash
+	}
+
+	err = superBlock.SerializeSuperBlock(partition.Path, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al guardar SuperBlock: %v", err)
+	}
+
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
+	return nil
+}
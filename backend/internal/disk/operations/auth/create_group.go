@@ -10,11 +10,10 @@ import (
 )
 
 func CreateGroup(
+	userData *LoggedUser,
 	name string,
 ) error {
 
-	userData := GetInstance()
-
 	if userData.User == nil {
 		return fmt.Errorf("error al crear grupo: no hay un usuario loggeado")
 	}
@@ -29,6 +28,15 @@ func CreateGroup(
 		return err
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := &ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 
@@ -66,5 +74,11 @@ func CreateGroup(
 		return fmt.Errorf("error al guardar SuperBlock: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	return nil
 }
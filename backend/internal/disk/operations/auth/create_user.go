@@ -10,11 +10,11 @@ import (
 )
 
 func CreateUser(
+	userData *LoggedUser,
 	username string,
 	password string,
 	group string,
 ) error {
-	userData := GetInstance()
 
 	if userData.User == nil {
 		return fmt.Errorf("error al crear usuario no hay usuario loggeado")
@@ -30,6 +30,15 @@ func CreateUser(
 		return err
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := &ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 
@@ -59,7 +68,14 @@ func CreateUser(
 		return fmt.Errorf("error al convertir UID a entero: %v", err)
 	}
 
-	content += fmt.Sprintf("%d,U,%s,%s,%s\n", uid+1, group, username, password)
+	// La contraseña se guarda como hash con sal, nunca en texto plano
+	hash, err := HashPassword(password)
+
+	if err != nil {
+		return fmt.Errorf("error al crear usuario: no se pudo generar el hash de la contraseña: %v", err)
+	}
+
+	content += fmt.Sprintf("%d,U,%s,%s,%s\n", uid+1, group, username, hash)
 
 	err = superBlock.UpdateFile(partitionPath, []string{}, "users.txt", content)
 
@@ -73,5 +89,11 @@ func CreateUser(
 		return fmt.Errorf("error al guardar SuperBlock: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	return nil
 }
@@ -1,25 +1,16 @@
 package auth
 
 import (
-	"sync"
-
 	"disk.simulator.com/m/v2/internal/disk/types/structures/authentication"
 )
 
-var instance *LoggedUser
-var once sync.Once
-
+// LoggedUser representa la sesión de un cliente. Cada cliente tiene su propia
+// sesión, identificada por Token, en lugar de compartir un usuario global.
 type LoggedUser struct {
-	ID   string
-	GID  string
-	User *authentication.User
-}
-
-func GetInstance() *LoggedUser {
-	once.Do(func() {
-		instance = &LoggedUser{}
-	})
-	return instance
+	Token string
+	ID    string
+	GID   string
+	User  *authentication.User
 }
 
 func (l *LoggedUser) SetLoggedUser(id string, gid string, user *authentication.User) {
@@ -4,15 +4,19 @@ import (
 	"fmt"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/types/structures/authentication"
 	"disk.simulator.com/m/v2/internal/disk/types/structures/ext"
 	"disk.simulator.com/m/v2/utils"
 )
 
-func Login(user, password, id string) error {
+func Login(loggedUser *LoggedUser, user, password, id string) error {
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 	if err != nil {
 		return err
 	}
+
+	defer memory.LockDisk(partitionPath)()
+
 	sb := &ext2.SuperBlock{}
 	sb.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 	content, err := sb.ReadFile(partitionPath, []string{}, "users.txt")
@@ -21,27 +25,94 @@ func Login(user, password, id string) error {
 	}
 
 	userData, _ := utils.FindUserInFile(content, user)
-	groupData, _ := utils.FindGroupInFile(content, userData.Group)
-	fmt.Println("Usuario encontrado:", userData)
 
 	if userData == nil {
 		return fmt.Errorf("error al iniciar sesión: usuario %s no encontrado", user)
 	}
 
-	if userData.Password != password {
-		return fmt.Errorf("error al iniciar sesión: contraseña incorrecta")
+	groupData, _ := utils.FindGroupInFile(content, userData.Group)
+	if groupData == nil {
+		return fmt.Errorf("error al iniciar sesión: grupo %s no encontrado", userData.Group)
 	}
 
-	loggedUser := GetInstance()
+	if !VerifyPassword(userData.Password, password) {
+		return fmt.Errorf("error al iniciar sesión: contraseña incorrecta")
+	}
 
 	// check if user is already logged in
 	if loggedUser.User != nil {
 		return fmt.Errorf("error al iniciar sesión: ya hay un usuario loggeado")
 	}
 
+	// Los registros en texto plano se actualizan a hash en el primer login exitoso
+	if !IsHashedPassword(userData.Password) {
+		err = upgradePassword(sb, partition.Partition.Part_start, partitionPath, content, userData, password)
+		if err != nil {
+			fmt.Printf("Advertencia: no se pudo actualizar la contraseña a formato hash: %v\n", err)
+		}
+	}
+
 	loggedUser.SetLoggedUser(id, groupData.GID, userData)
 
+	// Registrar la sesión para que el cliente pueda reutilizarla con su token
+	if loggedUser.Token == "" {
+		if err := GetSessionStore().register(loggedUser); err != nil {
+			loggedUser.User = nil
+			return err
+		}
+	}
+
 	fmt.Println("Sesión iniciada correctamente en el grupo", userData.Group)
 
 	return nil
 }
+
+// upgradePassword reemplaza la contraseña en texto plano de un usuario por su hash
+func upgradePassword(
+	sb *ext2.SuperBlock,
+	partitionStart int32,
+	partitionPath string,
+	content string,
+	userData *authentication.User,
+	password string,
+) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, index := utils.FindUserInFile(content, userData.Username)
+	if index == -1 {
+		return fmt.Errorf("usuario %s no encontrado", userData.Username)
+	}
+
+	newLine := fmt.Sprintf("%s,U,%s,%s,%s", userData.UID, userData.Group, userData.Username, hash)
+	content = utils.ReplaceLine(content, index, newLine)
+
+	// Agrupar las escrituras en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partitionStart)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = sb.UpdateFile(partitionPath, []string{}, "users.txt", content)
+	if err != nil {
+		return err
+	}
+
+	err = sb.SerializeSuperBlock(partitionPath, partitionStart)
+	if err != nil {
+		return err
+	}
+
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
+	userData.Password = hash
+
+	return nil
+}
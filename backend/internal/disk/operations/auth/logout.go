@@ -4,14 +4,16 @@ import (
 	"fmt"
 )
 
-func Logout() error {
-	loggedUser := GetInstance()
-
+func Logout(loggedUser *LoggedUser) error {
 	if loggedUser.User == nil {
 		return fmt.Errorf("error al cerrar sesión: no hay un usuario loggeado")
 	}
 
 	loggedUser.User = nil
 
+	// La sesión deja de ser válida para futuras peticiones
+	GetSessionStore().remove(loggedUser.Token)
+	loggedUser.Token = ""
+
 	return nil
 }
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Los hashes bcrypt empiezan con "$2a$", "$2b$" o "$2y$". Los registros de users.txt
+// que no tienen este prefijo se consideran contraseñas en texto plano (formato anterior)
+const hashedPasswordPrefix = "$2"
+
+// HashPassword genera el hash con sal de una contraseña para guardarlo en users.txt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// IsHashedPassword indica si el campo de contraseña de users.txt ya está hasheado
+func IsHashedPassword(stored string) bool {
+	return strings.HasPrefix(stored, hashedPasswordPrefix)
+}
+
+// VerifyPassword compara una contraseña con el valor guardado en users.txt,
+// aceptando tanto hashes como registros antiguos en texto plano
+func VerifyPassword(stored string, password string) bool {
+	if IsHashedPassword(stored) {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}
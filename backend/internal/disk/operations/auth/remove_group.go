@@ -9,11 +9,10 @@ import (
 )
 
 func RemoveGroup(
+	userData *LoggedUser,
 	name string,
 ) error {
 
-	userData := GetInstance()
-
 	if userData.User == nil {
 		return fmt.Errorf("error al eliminar grupo: no hay un usuario loggeado")
 	}
@@ -28,6 +27,15 @@ func RemoveGroup(
 		return err
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := &ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 
@@ -60,5 +68,11 @@ func RemoveGroup(
 		return fmt.Errorf("error al guardar SuperBlock: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	return nil
 }
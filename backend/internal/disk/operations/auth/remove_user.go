@@ -8,8 +8,7 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func RemoveUser(name string) error {
-	userData := GetInstance()
+func RemoveUser(userData *LoggedUser, name string) error {
 
 	if userData.User == nil {
 		return fmt.Errorf("error al eliminar usuario: no hay un usuario loggeado")
@@ -24,6 +23,15 @@ func RemoveUser(name string) error {
 		return err
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := &ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 
@@ -51,5 +59,11 @@ func RemoveUser(name string) error {
 		return fmt.Errorf("error al guardar SuperBlock: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// SessionStore es el singleton que mantiene las sesiones activas indexadas por token
+type SessionStore struct {
+	sessions map[string]*LoggedUser
+	mutex    sync.RWMutex
+}
+
+var (
+	store     *SessionStore
+	storeOnce sync.Once
+)
+
+// GetSessionStore retorna la única instancia del almacén de sesiones
+func GetSessionStore() *SessionStore {
+	storeOnce.Do(func() {
+		store = &SessionStore{
+			sessions: make(map[string]*LoggedUser),
+		}
+	})
+	return store
+}
+
+// GetSession obtiene la sesión asociada a un token
+func (s *SessionStore) GetSession(token string) (*LoggedUser, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	session, exists := s.sessions[token]
+	if !exists {
+		return nil, fmt.Errorf("sesión no encontrada o expirada")
+	}
+	return session, nil
+}
+
+// register asigna un token nuevo a la sesión y la agrega al almacén
+func (s *SessionStore) register(session *LoggedUser) error {
+	token, err := generateToken()
+	if err != nil {
+		return fmt.Errorf("error al generar el token de sesión: %v", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session.Token = token
+	s.sessions[token] = session
+	return nil
+}
+
+// remove elimina la sesión asociada a un token
+func (s *SessionStore) remove(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.sessions, token)
+}
+
+// generateToken genera un token aleatorio de 32 caracteres hexadecimales
+func generateToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
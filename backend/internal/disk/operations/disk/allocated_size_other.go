@@ -0,0 +1,11 @@
+//go:build !unix
+
+package disk_operations
+
+import "os"
+
+// allocatedSize devuelve el tamaño del archivo: sin la cantidad de bloques del
+// host no se distingue una imagen dispersa
+func allocatedSize(info os.FileInfo) int64 {
+	return info.Size()
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package disk_operations
+
+import (
+	"os"
+	"syscall"
+)
+
+// allocatedSize devuelve los bytes que el archivo ocupa realmente en el
+// sistema de archivos del host; en una imagen dispersa es menor que su tamaño
+func allocatedSize(info os.FileInfo) int64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(stat.Blocks) * 512
+	}
+	return info.Size()
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"disk.simulator.com/m/v2/internal/disk/memory"
 	mbr_operations "disk.simulator.com/m/v2/internal/disk/operations/mbr"
 	"disk.simulator.com/m/v2/internal/disk/types"
 	"disk.simulator.com/m/v2/utils"
@@ -18,6 +19,8 @@ func CreateDisk(params types.MkDisk) error {
 		return fmt.Errorf("error al crear el directorio: %v", err)
 	}
 
+	defer memory.LockDisk(params.Path)()
+
 	// Crear el archivo
 	file, err := os.Create(params.Path)
 	if err != nil {
@@ -28,23 +31,32 @@ func CreateDisk(params types.MkDisk) error {
 	// Convertir el tamaño a bytes
 	sizeInBytes, _ := utils.ConvertToBytes(params.Size, params.Unit)
 
-	// Crear buffer de 1MB para escribir más eficientemente
-	buffer := make([]byte, 1024*1024) // 1MB
-
-	// Escribir ceros en el archivo hasta alcanzar el tamaño deseado
-	var remaining int64 = sizeInBytes
-	for remaining > 0 {
-		writeSize := int64(len(buffer))
-		if remaining < writeSize {
-			writeSize = remaining
-		}
-
-		_, err := file.Write(buffer[:writeSize])
+	if params.Sparse {
+		// Imagen dispersa: el archivo tiene el tamaño completo pero sin bloques
+		// asignados; las partes que no se escriben se leen como ceros
+		err = file.Truncate(sizeInBytes)
 		if err != nil {
-			return fmt.Errorf("error al escribir en el disco: %v", err)
+			return fmt.Errorf("error al crear el disco disperso: %v", err)
 		}
+	} else {
+		// Crear buffer de 1MB para escribir más eficientemente
+		buffer := make([]byte, 1024*1024) // 1MB
 
-		remaining -= writeSize
+		// Escribir ceros en el archivo hasta alcanzar el tamaño deseado
+		var remaining int64 = sizeInBytes
+		for remaining > 0 {
+			writeSize := int64(len(buffer))
+			if remaining < writeSize {
+				writeSize = remaining
+			}
+
+			_, err := file.Write(buffer[:writeSize])
+			if err != nil {
+				return fmt.Errorf("error al escribir en el disco: %v", err)
+			}
+
+			remaining -= writeSize
+		}
 	}
 
 	// Crear el MBR del disco
@@ -55,11 +67,15 @@ func CreateDisk(params types.MkDisk) error {
 
 	// Registrar el disco en el registro
 	diskInfo := DiskInfo{
-		Name:     filepath.Base(params.Path),
-		Path:     params.Path,
-		Size:     sizeInBytes,
-		Created:  time.Now(),
-		Modified: time.Now(),
+		Name:          filepath.Base(params.Path),
+		Path:          params.Path,
+		Size:          sizeInBytes,
+		AllocatedSize: sizeInBytes,
+		Created:       time.Now(),
+		Modified:      time.Now(),
+	}
+	if info, err := os.Stat(params.Path); err == nil {
+		diskInfo.AllocatedSize = allocatedSize(info)
 	}
 
 	// Obtener el registro y añadir el disco
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
 )
 
 // DiskRegistry es un singleton que mantiene un registro de todos los discos creados
@@ -18,8 +21,8 @@ var (
 	once     sync.Once
 )
 
-// Ruta del archivo para persistencia
-const registryFilePath = "./disk_registry.json"
+// Nombre del archivo para persistencia, que se guarda junto al estado de montaje
+const registryFileName = "disk_registry.json"
 
 // GetDiskRegistry devuelve la instancia única del registro de discos
 func GetDiskRegistry() *DiskRegistry {
@@ -63,6 +66,36 @@ func (r *DiskRegistry) GetDisks() []DiskInfo {
 	return disks
 }
 
+// SetSnapshot agrega o reemplaza un snapshot del disco y guarda el registro.
+// Si el disco no estaba registrado se registra con disk
+func (r *DiskRegistry) SetSnapshot(disk DiskInfo, snapshot SnapshotInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if registered, ok := r.Disks[disk.Path]; ok {
+		disk = registered
+	}
+
+	snapshots := make([]SnapshotInfo, 0, len(disk.Snapshots)+1)
+	for _, existing := range disk.Snapshots {
+		if existing.Name != snapshot.Name {
+			snapshots = append(snapshots, existing)
+		}
+	}
+	disk.Snapshots = append(snapshots, snapshot)
+
+	r.Disks[disk.Path] = disk
+	saveRegistryToFile()
+}
+
+// GetSnapshots devuelve los snapshots registrados de un disco
+func (r *DiskRegistry) GetSnapshots(path string) []SnapshotInfo {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return slices.Clone(r.Disks[path].Snapshots)
+}
+
 // DiskExists verifica si un disco ya está registrado
 func (r *DiskRegistry) DiskExists(path string) bool {
 	r.mutex.RLock()
@@ -73,6 +106,8 @@ func (r *DiskRegistry) DiskExists(path string) bool {
 
 // saveRegistryToFile guarda el registro de discos en un archivo JSON
 func saveRegistryToFile() {
+	registryFilePath := memory.StateFilePath(registryFileName)
+
 	// Asegurar que exista el directorio
 	dir := filepath.Dir(registryFilePath)
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
@@ -97,6 +132,8 @@ func saveRegistryToFile() {
 
 // loadRegistryFromFile carga el registro de discos desde un archivo JSON
 func loadRegistryFromFile() {
+	registryFilePath := memory.StateFilePath(registryFileName)
+
 	// Verificar si el archivo existe
 	if _, err := os.Stat(registryFilePath); os.IsNotExist(err) {
 		// Si no existe, no hacemos nada más
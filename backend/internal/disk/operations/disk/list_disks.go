@@ -12,11 +12,13 @@ import (
 
 // DiskInfo contiene información sobre un disco
 type DiskInfo struct {
-	Name     string    `json:"name"`
-	Path     string    `json:"path"`
-	Size     int64     `json:"size"`
-	Created  time.Time `json:"created"`
-	Modified time.Time `json:"modified"`
+	Name          string         `json:"name"`
+	Path          string         `json:"path"`
+	Size          int64          `json:"size"`          // Tamaño aparente del archivo
+	AllocatedSize int64          `json:"allocatedSize"` // Espacio que ocupa en el host (menor si es disperso)
+	Created       time.Time      `json:"created"`
+	Modified      time.Time      `json:"modified"`
+	Snapshots     []SnapshotInfo `json:"snapshots,omitempty"` // Copias del disco creadas con snapshot
 }
 
 // ListDisks retorna una lista de los discos creados con mkdisk
@@ -38,6 +40,7 @@ func ListDisks() ([]DiskInfo, error) {
 			// El disco existe, actualizamos la fecha de modificación
 			registeredDisks[i].Modified = fileInfo.ModTime()
 			registeredDisks[i].Size = fileInfo.Size()
+			registeredDisks[i].AllocatedSize = allocatedSize(fileInfo)
 			disks = append(disks, registeredDisks[i])
 		} else {
 			// El disco ya no existe, lo eliminamos del registro
@@ -92,11 +95,12 @@ func ListDisks() ([]DiskInfo, error) {
 								if _, exists := uniqueDisks[info.Name()]; !exists {
 									// Crear información del disco solo si es un nombre único
 									disk := DiskInfo{
-										Name:     info.Name(),
-										Path:     path,
-										Size:     info.Size(),
-										Created:  time.Now(), // No podemos obtener la fecha de creación directamente
-										Modified: info.ModTime(),
+										Name:          info.Name(),
+										Path:          path,
+										Size:          info.Size(),
+										AllocatedSize: allocatedSize(info),
+										Created:       time.Now(), // No podemos obtener la fecha de creación directamente
+										Modified:      info.ModTime(),
 									}
 
 									// Registrar este disco para futuros usos
@@ -147,14 +151,15 @@ func GetDisksInfo() (string, error) {
 
 	// Formatear la salida
 	output := "LISTADO DE DISCOS DISPONIBLES:\n\n"
-	output += fmt.Sprintf("%-20s | %-30s | %-15s | %-20s\n", "NOMBRE", "RUTA", "TAMAÑO (bytes)", "ÚLTIMA MODIFICACIÓN")
-	output += fmt.Sprintf("%s\n", "-----------------------------------------------------------------------------------------------")
+	output += fmt.Sprintf("%-20s | %-30s | %-15s | %-15s | %-20s\n", "NOMBRE", "RUTA", "TAMAÑO (bytes)", "ASIGNADO (bytes)", "ÚLTIMA MODIFICACIÓN")
+	output += fmt.Sprintf("%s\n", "-----------------------------------------------------------------------------------------------------------------")
 
 	for _, disk := range disks {
-		output += fmt.Sprintf("%-20s | %-30s | %-15d | %-20s\n",
+		output += fmt.Sprintf("%-20s | %-30s | %-15d | %-15d | %-20s\n",
 			disk.Name,
 			disk.Path,
 			disk.Size,
+			disk.AllocatedSize,
 			disk.Modified.Format("2006-01-02 15:04:05"))
 	}
 
@@ -3,6 +3,9 @@ package disk_operations
 import (
 	"fmt"
 	"os"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
 )
 
 func RemoveDisk(path string) error {
@@ -11,12 +14,24 @@ func RemoveDisk(path string) error {
 		return fmt.Errorf("el disco en la ruta %s no existe", path)
 	}
 
+	// Esperar a que terminen las operaciones pendientes sobre el disco
+	defer memory.LockDisk(path)()
+
+	// Cerrar el handle del disco si tiene particiones montadas
+	ext2.ClosePartition(path)
+
 	// Eliminar el archivo
 	err := os.Remove(path)
 	if err != nil {
 		return fmt.Errorf("error al eliminar el disco: %v", err)
 	}
 
+	// Los snapshots no sirven sin el disco
+	err = removeSnapshots(path)
+	if err != nil {
+		return fmt.Errorf("error al eliminar los snapshots del disco: %v", err)
+	}
+
 	// Eliminar el disco del registro
 	registry := GetDiskRegistry()
 	registry.UnregisterDisk(path)
@@ -0,0 +1,221 @@
+package disk_operations
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+)
+
+// SnapshotInfo describe una copia completa de un disco en un momento dado
+type SnapshotInfo struct {
+	Name          string    `json:"name"`
+	Path          string    `json:"path"`          // Archivo con la copia del disco
+	Size          int64     `json:"size"`          // Tamaño aparente de la copia
+	AllocatedSize int64     `json:"allocatedSize"` // Espacio que ocupa en el host
+	Created       time.Time `json:"created"`
+}
+
+// snapshotDir es la carpeta donde se guardan los snapshots de un disco
+func snapshotDir(diskPath string) string {
+	return diskPath + ".snapshots"
+}
+
+// CreateSnapshot guarda una copia del disco con el nombre indicado. El disco no
+// puede tener particiones montadas, para que la copia no quede a medio escribir
+func CreateSnapshot(path string, name string) (SnapshotInfo, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return SnapshotInfo{}, err
+	}
+
+	diskInfo, err := os.Stat(path)
+	if err != nil {
+		return SnapshotInfo{}, fmt.Errorf("el disco en la ruta %s no existe", path)
+	}
+
+	defer memory.LockDisk(path)()
+
+	if memory.GetInstance().HasMountedPartitions(path) {
+		return SnapshotInfo{}, fmt.Errorf("el disco tiene particiones montadas, desmóntelas antes de crear el snapshot")
+	}
+
+	for _, snapshot := range GetDiskRegistry().GetSnapshots(path) {
+		if snapshot.Name == name {
+			return SnapshotInfo{}, fmt.Errorf("ya existe un snapshot '%s' del disco", name)
+		}
+	}
+
+	err = os.MkdirAll(snapshotDir(path), os.ModePerm)
+	if err != nil {
+		return SnapshotInfo{}, fmt.Errorf("error al crear el directorio de snapshots: %v", err)
+	}
+
+	// La copia conserva los huecos del disco, así que solo ocupa lo que se ha escrito
+	snapshotPath := filepath.Join(snapshotDir(path), name+".snap")
+	err = copyDiskImage(snapshotPath, path, true)
+	if err != nil {
+		return SnapshotInfo{}, fmt.Errorf("error al copiar el disco: %v", err)
+	}
+
+	snapshot := SnapshotInfo{
+		Name:          name,
+		Path:          snapshotPath,
+		Size:          diskInfo.Size(),
+		AllocatedSize: diskInfo.Size(),
+		Created:       time.Now(),
+	}
+	if info, err := os.Stat(snapshotPath); err == nil {
+		snapshot.AllocatedSize = allocatedSize(info)
+	}
+
+	disk := DiskInfo{
+		Name:          filepath.Base(path),
+		Path:          path,
+		Size:          diskInfo.Size(),
+		AllocatedSize: allocatedSize(diskInfo),
+		Created:       diskInfo.ModTime(),
+		Modified:      diskInfo.ModTime(),
+	}
+	GetDiskRegistry().SetSnapshot(disk, snapshot)
+
+	return snapshot, nil
+}
+
+// RollbackSnapshot reemplaza el contenido del disco por el del snapshot indicado.
+// Igual que al crearlo, el disco no puede tener particiones montadas
+func RollbackSnapshot(path string, name string) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+
+	defer memory.LockDisk(path)()
+
+	if memory.GetInstance().HasMountedPartitions(path) {
+		return fmt.Errorf("el disco tiene particiones montadas, desmóntelas antes de restaurar el snapshot")
+	}
+
+	var snapshot *SnapshotInfo
+	for _, registered := range GetDiskRegistry().GetSnapshots(path) {
+		if registered.Name == name {
+			snapshot = &registered
+			break
+		}
+	}
+	if snapshot == nil {
+		return fmt.Errorf("el disco no tiene un snapshot '%s'", name)
+	}
+
+	// Un disco disperso sigue siéndolo; uno lleno de ceros vuelve a escribirse completo
+	sparse := false
+	if info, err := os.Stat(path); err == nil {
+		sparse = allocatedSize(info) < info.Size()
+	}
+
+	err := copyDiskImage(path, snapshot.Path, sparse)
+	if err != nil {
+		return fmt.Errorf("error al restaurar el snapshot '%s': %v", name, err)
+	}
+
+	return nil
+}
+
+// GetSnapshotsInfo retorna un string formateado con los snapshots de un disco
+func GetSnapshotsInfo(path string) (string, error) {
+	snapshots := GetDiskRegistry().GetSnapshots(path)
+	if len(snapshots) == 0 {
+		return "", fmt.Errorf("el disco %s no tiene snapshots", path)
+	}
+
+	output := fmt.Sprintf("SNAPSHOTS DEL DISCO %s:\n\n", path)
+	output += fmt.Sprintf("%-20s | %-15s | %-16s | %-20s\n", "NOMBRE", "TAMAÑO (bytes)", "ASIGNADO (bytes)", "CREADO")
+	output += fmt.Sprintf("%s\n", "-----------------------------------------------------------------------------")
+
+	for _, snapshot := range snapshots {
+		output += fmt.Sprintf("%-20s | %-15d | %-16d | %-20s\n",
+			snapshot.Name,
+			snapshot.Size,
+			snapshot.AllocatedSize,
+			snapshot.Created.Format("2006-01-02 15:04:05"))
+	}
+
+	return output, nil
+}
+
+// removeSnapshots elimina los snapshots guardados de un disco
+func removeSnapshots(path string) error {
+	return os.RemoveAll(snapshotDir(path))
+}
+
+// validateSnapshotName verifica que el nombre sirva como nombre de archivo
+func validateSnapshotName(name string) error {
+	if name == "" {
+		return fmt.Errorf("el nombre del snapshot es requerido")
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("nombre de snapshot inválido '%s'", name)
+	}
+	return nil
+}
+
+// copyDiskImage copia src en dst a través de un archivo temporal que reemplaza
+// a dst al terminar, para que un error no deje una copia a medias. Si sparse es
+// true los bloques de ceros no se escriben y quedan como huecos del archivo
+func copyDiskImage(dst string, src string, sparse bool) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := dst + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	defer out.Close()
+
+	buffer := make([]byte, 1024*1024) // 1MB
+	zeros := make([]byte, len(buffer))
+
+	var offset int64
+	for {
+		n, err := in.Read(buffer)
+		if n > 0 && !(sparse && bytes.Equal(buffer[:n], zeros[:n])) {
+			if _, err := out.WriteAt(buffer[:n], offset); err != nil {
+				return err
+			}
+		}
+		offset += int64(n)
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Los huecos del final no se escribieron: fijar el tamaño completo
+	if err := out.Truncate(info.Size()); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, dst)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"disk.simulator.com/m/v2/internal/disk/memory"
 	"disk.simulator.com/m/v2/internal/disk/types"
 	"disk.simulator.com/m/v2/internal/disk/types/structures"
 )
@@ -12,6 +13,8 @@ import (
 func AddSpacePartition(
 	params types.FDisk,
 ) error {
+	defer memory.LockDisk(params.Path)()
+
 	// Leer el MBR del disco
 	var mbr structures.MBR
 	err := mbr.DeserializeMBR(params.Path)
@@ -0,0 +1,546 @@
+package partition_operations
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	pathpkg "path"
+	"strconv"
+	"strings"
+	"time"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// Propietario de las carpetas que import crea para llegar al destino y que no
+// vienen en el archivo
+const (
+	rootUID = 1
+	rootGID = 1
+)
+
+// archiveStats cuenta lo que se exportó o importó
+type archiveStats struct {
+	dirs    int
+	files   int
+	bytes   int64
+	skipped int
+}
+
+// checkArchiveSession verifica que la sesión sea de root en la partición id.
+// Export lee todos los archivos sin importar sus permisos e import decide los
+// dueños de lo que crea, así que ambos se reservan para root
+func checkArchiveSession(session *auth.LoggedUser, id string, operation string) error {
+	if session.User == nil {
+		return fmt.Errorf("error al %s: no hay un usuario loggeado", operation)
+	}
+	if session.User.Group != "root" {
+		return fmt.Errorf("error al %s: solo el usuario root puede hacerlo", operation)
+	}
+	if !strings.EqualFold(session.ID, id) {
+		return fmt.Errorf("error al %s: la sesión es de la partición %s, no de %s", operation, session.ID, id)
+	}
+	return nil
+}
+
+// ExportPartition recorre el árbol de la partición montada y lo guarda en un
+// archivo tar del host, conservando propietario, grupo, permisos y fechas de
+// cada inodo. La raíz no se incluye: sus entradas quedan en el primer nivel.
+// Requiere una sesión de root en la partición
+func ExportPartition(session *auth.LoggedUser, id string, dest string) (string, error) {
+	err := checkArchiveSession(session, id, "exportar")
+	if err != nil {
+		return "", err
+	}
+
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.RLockDisk(partitionPath)()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	// Los nombres del dueño y del grupo viajan en el tar para que import los
+	// busque por nombre en la partición de destino
+	users, err := superBlock.FileContent(partitionPath, []string{}, "users.txt")
+	if err != nil {
+		return "", fmt.Errorf("error al leer users.txt: %v", err)
+	}
+
+	root := &ext2.INode{}
+	err = root.Deserialize(partitionPath, int64(superBlock.SInodeStart))
+	if err != nil {
+		return "", fmt.Errorf("error al leer el inodo raíz: %v", err)
+	}
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return "", fmt.Errorf("error al crear el archivo %s: %v", dest, err)
+	}
+	defer file.Close()
+
+	writer := tar.NewWriter(file)
+	stats := &archiveStats{}
+
+	err = exportDirectory(&superBlock, partitionPath, users, root, "", writer, stats)
+	if err != nil {
+		os.Remove(dest)
+		return "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		os.Remove(dest)
+		return "", fmt.Errorf("error al escribir el archivo %s: %v", dest, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(dest)
+		return "", fmt.Errorf("error al escribir el archivo %s: %v", dest, err)
+	}
+
+	output := &strings.Builder{}
+	fmt.Fprintf(output, "Partition %s (%s) exported to %s\n", id, partition.Name, dest)
+	fmt.Fprintf(output, "Directories: %d, Files: %d, Bytes: %d\n", stats.dirs, stats.files, stats.bytes)
+
+	return output.String(), nil
+}
+
+// exportDirectory agrega al tar las entradas del directorio y, recursivamente,
+// las de sus subdirectorios. prefix es la ruta del directorio dentro del tar
+func exportDirectory(
+	superBlock *ext2.SuperBlock,
+	partitionPath string,
+	users string,
+	dirInode *ext2.INode,
+	prefix string,
+	writer *tar.Writer,
+	stats *archiveStats,
+) error {
+	entries, err := superBlock.DirectoryEntries(partitionPath, dirInode)
+	if err != nil {
+		return fmt.Errorf("error al leer el directorio '/%s': %v", prefix, err)
+	}
+
+	for _, entry := range entries {
+		name := prefix + entry.Name
+
+		inode := &ext2.INode{}
+		err = inode.Deserialize(partitionPath, int64(superBlock.SInodeStart+(entry.Inode*superBlock.SInodeS)))
+		if err != nil {
+			return fmt.Errorf("error al leer el inodo de '/%s': %v", name, err)
+		}
+
+		header := inodeHeader(inode, users)
+
+		if inode.IType[0] == '0' {
+			header.Typeflag = tar.TypeDir
+			header.Name = name + "/"
+			if err := writer.WriteHeader(header); err != nil {
+				return fmt.Errorf("error al escribir '/%s' en el archivo: %v", name, err)
+			}
+			stats.dirs++
+
+			err = exportDirectory(superBlock, partitionPath, users, inode, name+"/", writer, stats)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := superBlock.InodeContent(partitionPath, inode)
+		if err != nil {
+			return fmt.Errorf("error al leer '/%s': %v", name, err)
+		}
+
+		header.Typeflag = tar.TypeReg
+		header.Name = name
+		header.Size = int64(len(content))
+		if err := writer.WriteHeader(header); err != nil {
+			return fmt.Errorf("error al escribir '/%s' en el archivo: %v", name, err)
+		}
+		if _, err := writer.Write(content); err != nil {
+			return fmt.Errorf("error al escribir '/%s' en el archivo: %v", name, err)
+		}
+		stats.files++
+		stats.bytes += int64(len(content))
+	}
+
+	return nil
+}
+
+// inodeHeader crea la cabecera tar con los metadatos del inodo y los nombres
+// de su dueño y grupo según users. Se usa el formato PAX porque es el que
+// guarda las fechas de acceso y de creación
+func inodeHeader(inode *ext2.INode, users string) *tar.Header {
+	header := &tar.Header{
+		Mode:       permToMode(inode.IPerm),
+		Uid:        int(inode.IUid),
+		Gid:        int(inode.IGid),
+		ModTime:    time.Unix(int64(inode.IMtime), 0),
+		AccessTime: time.Unix(int64(inode.IAtime), 0),
+		ChangeTime: time.Unix(int64(inode.ICtime), 0),
+		Format:     tar.FormatPAX,
+	}
+
+	if user, _ := utils.FindUserByUID(users, strconv.Itoa(header.Uid)); user != nil {
+		header.Uname = user.Username
+	}
+	if group, _ := utils.FindGroupByGID(users, strconv.Itoa(header.Gid)); group != nil {
+		header.Gname = group.Name
+	}
+
+	return header
+}
+
+// ImportPartition crea en la partición montada, debajo de destPath, las
+// carpetas y archivos de un tar del host con el propietario, grupo, permisos y
+// fechas de cada entrada. El dueño y el grupo se buscan en users.txt por nombre
+// (o por número si el tar no trae nombres); si no existen en la partición quedan
+// a nombre del usuario que importa. En ext3 todo el import es una sola
+// transacción: si una entrada falla no queda nada a medias.
+// Requiere una sesión de root en la partición
+func ImportPartition(session *auth.LoggedUser, id string, src string, destPath string) (string, error) {
+	err := checkArchiveSession(session, id, "importar")
+	if err != nil {
+		return "", err
+	}
+
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("error al abrir el archivo %s: %v", src, err)
+	}
+	defer file.Close()
+
+	// Los nombres se revisan antes de escribir nada, porque en ext2 no hay
+	// transacción que deshaga las entradas ya creadas
+	err = checkArchiveNames(file, src)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("error al leer el archivo %s: %v", src, err)
+	}
+
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	users, err := superBlock.ReadFile(partitionPath, []string{}, "users.txt")
+	if err != nil {
+		return "", fmt.Errorf("error al leer users.txt: %v", err)
+	}
+
+	uid, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gid, _ := strconv.ParseInt(session.GID, 10, 32)
+
+	destPath = normalizePath(destPath)
+	importer := &archiveImporter{
+		superBlock:     &superBlock,
+		partitionPath:  partitionPath,
+		partitionStart: partition.Partition.Part_start,
+		users:          users,
+		uid:            int32(uid),
+		gid:            int32(gid),
+		stats:          &archiveStats{},
+	}
+
+	// El destino se crea si no existe, como con mkdir -p
+	err = importer.ensureFolder(destPath)
+	if err != nil {
+		return "", err
+	}
+
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error al leer el archivo %s: %v", src, err)
+		}
+
+		name, err := archiveEntryName(header.Name)
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			continue
+		}
+		target := pathpkg.Join(destPath, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = importer.importFolder(target, header)
+		case tar.TypeReg:
+			err = importer.importFile(target, header, reader)
+		default:
+			// Enlaces, dispositivos, etc. no existen en EXT2 simulado
+			importer.stats.skipped++
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = superBlock.SerializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al actualizar el superbloque: %v", err)
+	}
+
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return "", fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
+	stats := importer.stats
+	output := &strings.Builder{}
+	fmt.Fprintf(output, "Archive %s imported into %s of partition %s (%s)\n", src, destPath, id, partition.Name)
+	fmt.Fprintf(output, "Directories: %d, Files: %d, Bytes: %d\n", stats.dirs, stats.files, stats.bytes)
+	if stats.skipped > 0 {
+		fmt.Fprintf(output, "Skipped: %d (unsupported entry types)\n", stats.skipped)
+	}
+
+	return output.String(), nil
+}
+
+// archiveImporter crea las entradas de un tar dentro de la transacción de ImportPartition
+type archiveImporter struct {
+	superBlock     *ext2.SuperBlock
+	partitionPath  string
+	partitionStart int32
+	users          string // Contenido de users.txt de la partición
+	uid            int32  // Usuario que importa, dueño de las entradas sin un dueño conocido
+	gid            int32
+	stats          *archiveStats
+}
+
+// owner retorna el dueño y el grupo que tendrá en la partición una entrada del tar
+func (im *archiveImporter) owner(header *tar.Header) (int32, int32) {
+	uid, gid := im.uid, im.gid
+
+	user, _ := utils.FindUserByUID(im.users, strconv.Itoa(header.Uid))
+	if header.Uname != "" {
+		user, _ = utils.FindUserInFile(im.users, header.Uname)
+	}
+	if user != nil {
+		if value, err := strconv.ParseInt(user.UID, 10, 32); err == nil {
+			uid = int32(value)
+		}
+	}
+
+	group, _ := utils.FindGroupByGID(im.users, strconv.Itoa(header.Gid))
+	if header.Gname != "" {
+		group, _ = utils.FindGroupInFile(im.users, header.Gname)
+	}
+	if group != nil {
+		if value, err := strconv.ParseInt(group.GID, 10, 32); err == nil {
+			gid = int32(value)
+		}
+	}
+
+	return uid, gid
+}
+
+// ensureFolder crea la carpeta y las que le falten por encima, a nombre de root
+func (im *archiveImporter) ensureFolder(path string) error {
+	if utils.IsRoot(path) {
+		return nil
+	}
+
+	parentDirs, name := utils.GetParentDirectories(path)
+	exists, err := im.superBlock.FolderExists(im.partitionPath, parentDirs, name)
+	if err != nil {
+		return fmt.Errorf("error al verificar la carpeta '%s': %v", path, err)
+	}
+	if exists {
+		return nil
+	}
+
+	err = im.superBlock.CreateFolder(im.partitionPath, parentDirs, name, true, rootUID, rootGID)
+	if err != nil {
+		return fmt.Errorf("error al crear la carpeta '%s': %v", path, err)
+	}
+
+	return im.journal("mkdir", path, "")
+}
+
+// importFolder crea la carpeta si no existe y le aplica los metadatos de la
+// entrada, igual que tar al extraer sobre una carpeta existente
+func (im *archiveImporter) importFolder(target string, header *tar.Header) error {
+	err := im.ensureFolder(target)
+	if err != nil {
+		return err
+	}
+
+	im.stats.dirs++
+	return im.applyHeader(target, header)
+}
+
+// importFile crea el archivo con el contenido de la entrada. Si ya existe un
+// archivo o carpeta con ese nombre el import falla
+func (im *archiveImporter) importFile(target string, header *tar.Header, reader io.Reader) error {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("error al leer '%s' del archivo: %v", header.Name, err)
+	}
+
+	parentDirs, name := utils.GetParentDirectories(target)
+	if _, err := im.superBlock.FindFileInode(im.partitionPath, parentDirs, name); err == nil {
+		return fmt.Errorf("'%s' ya existe en la partición", target)
+	}
+
+	// Un tar puede no traer las carpetas de sus archivos
+	err = im.ensureFolder(pathpkg.Dir(target))
+	if err != nil {
+		return err
+	}
+
+	uid, gid := im.owner(header)
+	err = im.superBlock.CreateFile(im.partitionPath, parentDirs, name, 0, string(content), false, uid, gid)
+	if err != nil {
+		return fmt.Errorf("error al crear el archivo '%s': %v", target, err)
+	}
+
+	err = im.journal("mkfile", target, string(content))
+	if err != nil {
+		return err
+	}
+
+	im.stats.files++
+	im.stats.bytes += int64(len(content))
+	return im.applyHeader(target, header)
+}
+
+// applyHeader copia al inodo el propietario, grupo, permisos y fechas de la entrada
+func (im *archiveImporter) applyHeader(target string, header *tar.Header) error {
+	sb := im.superBlock
+
+	inodeIndex, err := findPathInode(sb, im.partitionPath, target)
+	if err != nil {
+		return err
+	}
+
+	offset := int64(sb.SInodeStart + (inodeIndex * sb.SInodeS))
+	inode := &ext2.INode{}
+	err = inode.Deserialize(im.partitionPath, offset)
+	if err != nil {
+		return fmt.Errorf("error al leer el inodo de '%s': %v", target, err)
+	}
+
+	inode.IUid, inode.IGid = im.owner(header)
+	inode.IPerm = modeToPerm(header.Mode)
+	inode.IMtime = float32(header.ModTime.Unix())
+	inode.IAtime = inode.IMtime
+	if !header.AccessTime.IsZero() {
+		inode.IAtime = float32(header.AccessTime.Unix())
+	}
+	inode.ICtime = inode.IMtime
+	if !header.ChangeTime.IsZero() {
+		inode.ICtime = float32(header.ChangeTime.Unix())
+	}
+
+	err = inode.Serialize(im.partitionPath, offset)
+	if err != nil {
+		return fmt.Errorf("error al actualizar el inodo de '%s': %v", target, err)
+	}
+
+	return nil
+}
+
+// journal registra la entrada creada como lo harían mkdir y mkfile, para que
+// recovery pueda volver a crearla
+func (im *archiveImporter) journal(operation string, path string, content string) error {
+	if !im.superBlock.HasJournal() {
+		return nil
+	}
+
+	err := ext2.AddJournal(im.partitionPath, int64(im.partitionStart), 0, operation, path, content)
+	if err != nil {
+		// Con la política "error" el journal lleno impide aplicar la operación
+		if errors.Is(err, ext2.ErrJournalFull) {
+			return err
+		}
+		fmt.Printf("Advertencia: No se pudo registrar la operación en el journaling: %v\n", err)
+	}
+
+	return nil
+}
+
+// checkArchiveNames recorre las cabeceras del tar y falla si alguna entrada
+// tiene un nombre inválido
+func checkArchiveNames(file io.Reader, src string) error {
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error al leer el archivo %s: %v", src, err)
+		}
+
+		if _, err := archiveEntryName(header.Name); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveEntryName limpia el nombre de una entrada del tar. Los nombres
+// absolutos o que salen del destino con ".." se rechazan
+func archiveEntryName(name string) (string, error) {
+	cleaned := pathpkg.Clean(strings.TrimSuffix(name, "/"))
+	if pathpkg.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("entrada inválida '%s' en el archivo", name)
+	}
+	if cleaned == "." {
+		return "", nil
+	}
+	return cleaned, nil
+}
+
+// permToMode convierte los permisos UGO del inodo ("664") en el modo del tar
+func permToMode(perm [3]byte) int64 {
+	var mode int64
+	for _, digit := range perm {
+		mode = mode*8 + int64(min(max(digit, '0'), '7')-'0')
+	}
+	return mode
+}
+
+// modeToPerm convierte el modo del tar en los permisos UGO del inodo
+func modeToPerm(mode int64) [3]byte {
+	return [3]byte{
+		byte('0' + (mode>>6)&7),
+		byte('0' + (mode>>3)&7),
+		byte('0' + mode&7),
+	}
+}
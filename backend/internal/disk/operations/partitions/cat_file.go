@@ -2,20 +2,28 @@ package partition_operations
 
 import (
 	"fmt"
+	"strings"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
 	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
 	"disk.simulator.com/m/v2/utils"
 )
 
-func CatFile(filePath string) (string, error) {
-	id := "761A"
+func CatFile(session *auth.LoggedUser, filePath string) (string, error) {
+	if session.User == nil {
+		return "", fmt.Errorf("error al leer archivo: no hay un usuario loggeado")
+	}
+
+	id := session.ID
 	// Obtener la partición montada
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 	if err != nil {
 		return "", fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.RLockDisk(partitionPath)()
+
 	// Extraer directorios padre y nombre del archivo
 	parentDirs, destFile := utils.GetParentDirectories(filePath)
 
@@ -30,8 +38,8 @@ func CatFile(filePath string) (string, error) {
 		return "", fmt.Errorf("error al leer el superbloque: %v", err)
 	}
 
-	// Leer el contenido del archivo usando el superbloque
-	content, err := superBlock.ReadFile(partitionPath, parentDirs, destFile)
+	// Con el bloqueo de lectura no se escribe en el disco, así que no se actualiza la fecha de acceso
+	content, err := superBlock.FileContent(partitionPath, parentDirs, destFile)
 	if err != nil {
 		return "", fmt.Errorf("error al leer el archivo: %v", err)
 	}
@@ -40,10 +48,12 @@ func CatFile(filePath string) (string, error) {
 }
 
 // ReadFileContent lee el contenido de un archivo especificado por su ruta,
-// recibiendo el disco y la partición directamente.
-// Esta función es utilizada por el endpoint directory_ls para leer el contenido
-// de un archivo cuando se intenta listar un archivo en lugar de un directorio.
-func ReadFileContent(diskPath, partitionName string, parentDirs []string, fileName string) (string, error) {
+// recibiendo el disco y la partición directamente. El usuario de la sesión
+// necesita permiso de lectura sobre el archivo.
+// Esta función es utilizada por el endpoint /read-file.
+func ReadFileContent(session *auth.LoggedUser, diskPath, partitionName string, parentDirs []string, fileName string) (string, error) {
+	defer memory.RLockDisk(diskPath)()
+
 	// Encontrar la partición por nombre
 	partition, _, err := FindPartition(partitionName, diskPath)
 	if err != nil {
@@ -57,11 +67,29 @@ func ReadFileContent(diskPath, partitionName string, parentDirs []string, fileNa
 		return "", fmt.Errorf("error al leer el superbloque: %v", err)
 	}
 
-	// Intentar leer como archivo
-	content, err := superBlock.ReadFile(diskPath, parentDirs, fileName)
+	// Verificar los permisos antes de leer el contenido
+	inodeIndex, err := superBlock.FindFileInode(diskPath, parentDirs, fileName)
 	if err != nil {
 		return "", fmt.Errorf("error al leer el archivo: %v", err)
 	}
+	inode, err := superBlock.GetInodeByNumber(diskPath, inodeIndex)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el inodo del archivo: %v", err)
+	}
+	err = checkReadPermission(&superBlock, session, inode, "/"+strings.Join(append(parentDirs, fileName), "/"))
+	if err != nil {
+		return "", err
+	}
 
-	return content, nil
+	if inode.IType[0] != '1' {
+		return "", fmt.Errorf("error al leer el archivo: '%s' no es un archivo", fileName)
+	}
+
+	// Con el bloqueo de lectura no se escribe en el disco, así que no se actualiza la fecha de acceso
+	content, err := superBlock.InodeContent(diskPath, inode)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el archivo: %v", err)
+	}
+
+	return string(content), nil
 }
@@ -3,7 +3,6 @@ package partition_operations
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
@@ -13,19 +12,18 @@ import (
 )
 
 // ChangePermissions cambia los permisos de un archivo o directorio especificado por path
-func ChangePermissions(path string, ugo string, r bool) error {
-	instance := auth.GetInstance()
+func ChangePermissions(session *auth.LoggedUser, path string, ugo string, r bool) error {
 
-	if instance.User == nil {
+	if session.User == nil {
 		return fmt.Errorf("error: no hay un usuario loggeado")
 	}
 
 	// Verificar que el usuario sea root
-	if instance.User.Group != "root" {
+	if session.User.Group != "root" {
 		return fmt.Errorf("error: solo el usuario root puede cambiar permisos")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	// Obtener la partición montada
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
@@ -33,6 +31,15 @@ func ChangePermissions(path string, ugo string, r bool) error {
 		return fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	// Leer el superbloque de la partición
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
@@ -59,7 +66,7 @@ func ChangePermissions(path string, ugo string, r bool) error {
 	}
 
 	// Obtener el UID del usuario loggeado
-	currentUIDInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
+	currentUIDInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
 
 	// Verificar que el archivo existe
 	targetInodeIndex, err := superBlock.FindFileInode(partitionPath, parentDirs, targetName)
@@ -100,6 +107,12 @@ func ChangePermissions(path string, ugo string, r bool) error {
 		return fmt.Errorf("error al actualizar el superbloque: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	fmt.Printf("Se han cambiado los permisos de '%s' a %s\n", path, ugo)
 	return nil
 }
@@ -149,7 +162,7 @@ func changePermissionsRecursive(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := superBlock.EntryName(partitionPath, entry)
 			// Ignorar entradas "." y ".."
 			if entryName == "." || entryName == ".." {
 				continue
@@ -3,7 +3,6 @@ package partition_operations
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
@@ -13,14 +12,13 @@ import (
 )
 
 // ChangeOwner cambia el propietario de un archivo o directorio especificado por path
-func ChangeOwner(path string, usuario string, r bool) error {
-	instance := auth.GetInstance()
+func ChangeOwner(session *auth.LoggedUser, path string, usuario string, r bool) error {
 
-	if instance.User == nil {
+	if session.User == nil {
 		return fmt.Errorf("error: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	// Obtener la partición montada
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
@@ -28,6 +26,15 @@ func ChangeOwner(path string, usuario string, r bool) error {
 		return fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	// Leer el superbloque de la partición
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
@@ -56,8 +63,8 @@ func ChangeOwner(path string, usuario string, r bool) error {
 	}
 
 	// Obtener el UID y GID del usuario loggeado
-	currentUIDInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
-	currentGIDInt, _ := strconv.ParseInt(instance.GID, 10, 32)
+	currentUIDInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	currentGIDInt, _ := strconv.ParseInt(session.GID, 10, 32)
 
 	// Verificar que el archivo existe
 	targetInodeIndex, err := superBlock.FindFileInode(partitionPath, parentDirs, targetName)
@@ -73,7 +80,7 @@ func ChangeOwner(path string, usuario string, r bool) error {
 	}
 
 	// Verificar permisos: solo el root o el propietario pueden cambiar el dueño
-	if instance.User.Group != "root" && targetInode.IUid != int32(currentUIDInt) {
+	if session.User.Group != "root" && targetInode.IUid != int32(currentUIDInt) {
 		return fmt.Errorf("error: no tienes permisos para cambiar el propietario de este archivo o carpeta")
 	}
 
@@ -102,6 +109,12 @@ func ChangeOwner(path string, usuario string, r bool) error {
 		return fmt.Errorf("error al actualizar el superbloque: %v", err)
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	fmt.Printf("Se ha cambiado el propietario de '%s' al usuario '%s'\n", path, usuario)
 	return nil
 }
@@ -151,7 +164,7 @@ func changeOwnerRecursive(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := superBlock.EntryName(partitionPath, entry)
 			// Ignorar entradas "." y ".."
 			if entryName == "." || entryName == ".." {
 				continue
@@ -1,8 +1,8 @@
 package partition_operations
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"strconv"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
@@ -11,15 +11,20 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func CreateDirectory(dirPath string, p bool) error {
+func CreateDirectory(session *auth.LoggedUser, dirPath string, p bool) error {
+	defer lockMountedDisk(session.ID)()
+	return createDirectory(session, dirPath, p)
+}
 
-	instance := auth.GetInstance()
+// createDirectory hace el trabajo de CreateDirectory sin tomar el candado del
+// disco; la usa RecoverFromJournaling, que ya lo tiene
+func createDirectory(session *auth.LoggedUser, dirPath string, p bool) error {
 
-	if instance.User == nil {
+	if session.User == nil {
 		return fmt.Errorf("error al crear directorio: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	// Aquí iría la lógica para crear el directorio
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
@@ -29,14 +34,21 @@ func CreateDirectory(dirPath string, p bool) error {
 		return err
 	}
 
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	parentDirs, destDir := utils.GetParentDirectories(dirPath)
 
 	superBlock := ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(partition.Path, partition.Partition.Part_start)
 
 	// Convertir uid y gid de string a int32
-	uidInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
-	gidInt, _ := strconv.ParseInt(instance.GID, 10, 32)
+	uidInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gidInt, _ := strconv.ParseInt(session.GID, 10, 32)
 
 	err = superBlock.CreateFolder(partitionPath, parentDirs, destDir, p, int32(uidInt), int32(gidInt))
 
@@ -51,13 +63,10 @@ func CreateDirectory(dirPath string, p bool) error {
 	}
 
 	// Forzar sincronización después de crear directorio
-	if file, err := os.OpenFile(partitionPath, os.O_WRONLY, 0666); err == nil {
-		file.Sync()
-		file.Close()
-	}
+	ext2.SyncPartition(partitionPath)
 
 	// Si el sistema de archivos es ext3, registrar la operación en el journaling
-	if superBlock.SFilesystemType == 3 {
+	if superBlock.HasJournal() {
 		// Registrar la operación en el journal
 		err = ext2.AddJournal(
 			partitionPath,
@@ -69,6 +78,10 @@ func CreateDirectory(dirPath string, p bool) error {
 		)
 
 		if err != nil {
+			// Con la política "error" el journal lleno impide aplicar la operación
+			if errors.Is(err, ext2.ErrJournalFull) {
+				return err
+			}
 			fmt.Printf("Advertencia: No se pudo registrar la operación en el journaling: %v\n", err)
 			// No retornar error, ya que el directorio fue creado exitosamente
 		} else {
@@ -76,6 +89,12 @@ func CreateDirectory(dirPath string, p bool) error {
 		}
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	fmt.Printf("Directory %s created\n", dirPath)
 
 	return nil
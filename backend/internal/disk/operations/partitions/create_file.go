@@ -1,6 +1,7 @@
 package partition_operations
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -11,19 +12,25 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func CreateFile(
+func CreateFile(session *auth.LoggedUser, dirPath string, size int, contentPath string, r bool) error {
+	defer lockMountedDisk(session.ID)()
+	return createFile(session, dirPath, size, contentPath, r)
+}
+
+// createFile es CreateFile sin el candado del disco
+func createFile(
+	session *auth.LoggedUser,
 	dirPath string,
 	size int,
 	contentPath string,
 	r bool,
 ) error {
-	instance := auth.GetInstance()
 
-	if instance.User == nil {
+	if session.User == nil {
 		return fmt.Errorf("error al crear directorio: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 	// Obtener la partición montada
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 
@@ -31,6 +38,13 @@ func CreateFile(
 		return fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	// Extraer directorios padre y nombre del archivo
 	parentDirs, destFile := utils.GetParentDirectories(dirPath)
 
@@ -45,8 +59,8 @@ func CreateFile(
 	}
 
 	// Convertir uid y gid de string a int32
-	uidInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
-	gidInt, _ := strconv.ParseInt(instance.GID, 10, 32)
+	uidInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gidInt, _ := strconv.ParseInt(session.GID, 10, 32)
 
 	// Leer el contenido del archivo de la ruta especificada en mi computadora
 	// Read the content from the specified file path
@@ -78,26 +92,23 @@ func CreateFile(
 	}
 
 	// Si el sistema de archivos es ext3, registrar la operación en el journaling
-	if superBlock.SFilesystemType == 3 {
-		// Preparar el contenido para el journal (contenido truncado si es muy largo)
-		journalContent := ""
-		if len(content) > 60 { // El campo I_content tiene un tamaño limitado
-			journalContent = string(content[:60]) + "..."
-		} else {
-			journalContent = string(content)
-		}
-
-		// Registrar la operación en el journal
+	if superBlock.HasJournal() {
+		// Registrar la operación en el journal con el contenido completo; lo que no
+		// cabe en el slot principal se guarda en slots de continuación
 		err = ext2.AddJournal(
 			partitionPath,
 			int64(partition.Partition.Part_start),
 			0, // Este parámetro es ignorado ahora
 			"mkfile",
 			dirPath,
-			journalContent,
+			string(content),
 		)
 
 		if err != nil {
+			// Con la política "error" el journal lleno impide aplicar la operación
+			if errors.Is(err, ext2.ErrJournalFull) {
+				return err
+			}
 			fmt.Printf("Advertencia: No se pudo registrar la operación en el journaling: %v\n", err)
 			// No retornar error, ya que el archivo fue creado exitosamente
 		} else {
@@ -105,6 +116,12 @@ func CreateFile(
 		}
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	fmt.Printf("Archivo '%s' creado exitosamente en '%s'\n", destFile, dirPath)
 	return nil
 }
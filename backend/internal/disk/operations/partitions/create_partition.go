@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"disk.simulator.com/m/v2/internal/disk/memory"
 	mbr_operations "disk.simulator.com/m/v2/internal/disk/operations/mbr"
 	"disk.simulator.com/m/v2/internal/disk/types"
 	"disk.simulator.com/m/v2/internal/disk/types/structures"
@@ -19,6 +20,8 @@ import (
 //
 // Retorna un error si hay problemas durante la creación de la partición
 func CreatePartition(params types.FDisk) error {
+	defer memory.LockDisk(params.Path)()
+
 	// Obtener el tamaño del disco en bytes
 	fileInfo, err := os.Stat(params.Path)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"disk.simulator.com/m/v2/internal/disk/memory"
 	"disk.simulator.com/m/v2/internal/disk/types"
 	"disk.simulator.com/m/v2/internal/disk/types/structures"
 )
@@ -17,6 +18,8 @@ func DeletePartition(
 		return nil
 	}
 
+	defer memory.LockDisk(params.Path)()
+
 	// Leer el MBR del disco
 	var mbr structures.MBR
 
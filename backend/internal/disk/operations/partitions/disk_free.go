@@ -0,0 +1,64 @@
+package partition_operations
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+)
+
+// DiskFree muestra los inodos y bloques libres y usados de una partición montada
+func DiskFree(id string) (string, error) {
+	// Obtener la partición montada
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.RLockDisk(partitionPath)()
+
+	// Leer el superbloque de la partición
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	if superBlock.SMagic != 0xEF53 {
+		return "", fmt.Errorf("la partición '%s' no tiene un sistema de archivos", id)
+	}
+
+	// El total sale de la disposición de la partición; los usados se calculan a partir
+	// de los libres porque SInodesCount y SBlocksCount indican el siguiente índice a asignar
+	totalInodes := superBlock.InodesTotal()
+	totalBlocks := superBlock.BlocksTotal()
+	usedInodes := totalInodes - superBlock.SFreeInodesCount
+	usedBlocks := totalBlocks - superBlock.SFreeBlocksCount
+	blockSize := int64(superBlock.SBlockS)
+
+	output := &strings.Builder{}
+	fmt.Fprintf(output, "Filesystem: %s (ext%d)\n", partition.Name, superBlock.SFilesystemType)
+
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "\tTotal\tUsed\tFree\tUse%")
+	fmt.Fprintf(writer, "Inodes\t%d\t%d\t%d\t%s\n",
+		totalInodes, usedInodes, superBlock.SFreeInodesCount, usagePercent(usedInodes, totalInodes))
+	fmt.Fprintf(writer, "Blocks\t%d\t%d\t%d\t%s\n",
+		totalBlocks, usedBlocks, superBlock.SFreeBlocksCount, usagePercent(usedBlocks, totalBlocks))
+	fmt.Fprintf(writer, "Bytes\t%d\t%d\t%d\t%s\n",
+		int64(totalBlocks)*blockSize, int64(usedBlocks)*blockSize, int64(superBlock.SFreeBlocksCount)*blockSize,
+		usagePercent(usedBlocks, totalBlocks))
+	writer.Flush()
+
+	return output.String(), nil
+}
+
+// usagePercent calcula el porcentaje de uso redondeado hacia arriba
+func usagePercent(used int32, total int32) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", (int64(used)*100+int64(total)-1)/int64(total))
+}
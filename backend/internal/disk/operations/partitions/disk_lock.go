@@ -0,0 +1,14 @@
+package partition_operations
+
+import "disk.simulator.com/m/v2/internal/disk/memory"
+
+// lockMountedDisk bloquea para escritura el disco de la partición montada id.
+// Si la partición no está montada no bloquea nada: la operación se encarga de
+// reportar el error
+func lockMountedDisk(id string) func() {
+	_, diskPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return func() {}
+	}
+	return memory.LockDisk(diskPath)
+}
@@ -0,0 +1,80 @@
+package partition_operations
+
+import (
+	"fmt"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// DiskUsage calcula los bytes y bloques usados por un archivo o carpeta.
+// Si r es true, se muestra también el uso de cada entrada contenida.
+func DiskUsage(session *auth.LoggedUser, path string, r bool) (string, error) {
+	if session.User == nil {
+		return "", fmt.Errorf("error: no hay un usuario loggeado")
+	}
+
+	// Obtener la partición montada
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(session.ID)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.RLockDisk(partitionPath)()
+
+	// Leer el superbloque de la partición
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	inodeIndex, err := findPathInode(&superBlock, partitionPath, path)
+	if err != nil {
+		return "", err
+	}
+
+	output := &strings.Builder{}
+
+	// Cada entrada se imprime después de su contenido, igual que du
+	var visit func(string, int64, int32)
+	if r {
+		visit = func(entryPath string, bytes int64, blocks int32) {
+			fmt.Fprintf(output, "%d\t%d\t%s\n", bytes, blocks, entryPath)
+		}
+	}
+
+	bytes, blocks, err := superBlock.DiskUsage(partitionPath, inodeIndex, normalizePath(path), visit)
+	if err != nil {
+		return "", fmt.Errorf("error al calcular el uso: %v", err)
+	}
+
+	if !r {
+		fmt.Fprintf(output, "%d\t%d\t%s\n", bytes, blocks, normalizePath(path))
+	}
+
+	return output.String(), nil
+}
+
+// findPathInode devuelve el índice del inodo de una ruta absoluta, incluyendo la raíz
+func findPathInode(superBlock *ext2.SuperBlock, partitionPath string, path string) (int32, error) {
+	if utils.IsRoot(path) {
+		return 0, nil
+	}
+
+	parentDirs, name := utils.GetParentDirectories(path)
+	inodeIndex, err := superBlock.FindFileInode(partitionPath, parentDirs, name)
+	if err != nil {
+		return -1, fmt.Errorf("error: el archivo o carpeta '%s' no existe: %v", path, err)
+	}
+
+	return inodeIndex, nil
+}
+
+// normalizePath asegura que la ruta empiece con "/" y no termine con "/"
+func normalizePath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
@@ -1,7 +1,9 @@
 package partition_operations
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
@@ -10,48 +12,78 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func EditFile(path string, contentPath string) error {
-	instance := auth.GetInstance()
+func EditFile(session *auth.LoggedUser, path string, contentPath string) error {
 
-	if instance.User == nil {
+	// Leer el nuevo contenido desde el archivo local para poder registrarlo completo en el journal
+	content, err := os.ReadFile(contentPath)
+	if err != nil {
+		return fmt.Errorf("error al leer el archivo de contenido '%s': %v", contentPath, err)
+	}
+
+	return EditFileContent(session, path, string(content))
+}
+
+// EditFileContent reemplaza el contenido de un archivo de la partición por content
+func EditFileContent(session *auth.LoggedUser, path string, content string) error {
+	defer lockMountedDisk(session.ID)()
+	return editFileContent(session, path, content)
+}
+
+// editFileContent hace la edición sin tomar el candado del disco
+func editFileContent(session *auth.LoggedUser, path string, content string) error {
+
+	if session.User == nil {
 		return fmt.Errorf("error al editar archivo: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 	if err != nil {
 		return fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
 	if err != nil {
 		return fmt.Errorf("error al leer el superbloque: %v", err)
 	}
 
-	uidInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
-	gidInt, _ := strconv.ParseInt(instance.GID, 10, 32)
+	uidInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gidInt, _ := strconv.ParseInt(session.GID, 10, 32)
 
 	// Obtener directorios padre y nombre de archivo
 	parentDirs, fileName := utils.GetParentDirectories(path)
 
-	// Llamar a la función EditFile con la ruta del archivo que contiene el contenido
-	err = superBlock.EditFile(
-		partitionPath, // Ruta física de la partición
-		parentDirs,    // Directorios padre
-		fileName,      // Nombre del archivo
-		contentPath,   // Ruta al archivo que contiene el nuevo contenido
-		int32(uidInt), // UID
-		int32(gidInt), // GID
+	// Llamar a la función EditFileContent con el nuevo contenido
+	err = superBlock.EditFileContent(
+		partitionPath,   // Ruta física de la partición
+		parentDirs,      // Directorios padre
+		fileName,        // Nombre del archivo
+		[]byte(content), // Nuevo contenido
+		int32(uidInt),   // UID
+		int32(gidInt),   // GID
 	)
 
 	if err != nil {
 		return fmt.Errorf("error al editar archivo: %v", err)
 	}
 
+	// Actualizar el superbloque con los cambios antes de registrar el journal, que también lo modifica
+	err = superBlock.SerializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al actualizar el superbloque: %v", err)
+	}
+
 	// Si el sistema de archivos es ext3, registrar la operación en el journaling
-	if superBlock.SFilesystemType == 3 {
+	if superBlock.HasJournal() {
 		// Registrar la operación en el journal
 		err = ext2.AddJournal(
 			partitionPath,
@@ -59,10 +91,14 @@ func EditFile(path string, contentPath string) error {
 			0, // Este parámetro es ignorado ahora
 			"edit",
 			path,
-			"(contenido de archivo actualizado)",
+			content,
 		)
 
 		if err != nil {
+			// Con la política "error" el journal lleno impide aplicar la operación
+			if errors.Is(err, ext2.ErrJournalFull) {
+				return err
+			}
 			fmt.Printf("Advertencia: No se pudo registrar la operación en el journaling: %v\n", err)
 			// No retornar error, ya que el archivo fue editado exitosamente
 		} else {
@@ -70,10 +106,10 @@ func EditFile(path string, contentPath string) error {
 		}
 	}
 
-	// Actualizar el superbloque con los cambios
-	err = superBlock.SerializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
 	if err != nil {
-		return fmt.Errorf("error al actualizar el superbloque: %v", err)
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
 	}
 
 	fmt.Printf("Archivo '%s' editado exitosamente\n", path)
@@ -10,22 +10,24 @@ import (
 )
 
 func FindFileOrFolderTree(
+	session *auth.LoggedUser,
 	path string,
 	name string,
 ) (string, error) {
-	instance := auth.GetInstance()
 
-	if instance.User == nil {
+	if session.User == nil {
 		return "", fmt.Errorf("error al buscar: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 	if err != nil {
 		return "", fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.RLockDisk(partitionPath)()
+
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
 
@@ -60,13 +60,14 @@ func FindPartition(name string, path string) (structures.Partition, int, error)
 				if currentEBR.Part_size != -1 { // Si es una partición válida
 					ebrName := strings.TrimSpace(string(bytes.Trim(currentEBR.Part_name[:], "\x00")))
 					if ebrName == name {
-						// Convertir EBR a Partition para mantener la consistencia
+						// Convertir EBR a Partition para mantener la consistencia. Los datos
+						// empiezan después del EBR para que mkfs no lo sobrescriba
 						logicalPart := structures.Partition{
 							Part_status: '1',
 							Part_type:   'L',
 							Part_fit:    currentEBR.Part_fit,
-							Part_start:  currentEBR.Part_start,
-							Part_size:   currentEBR.Part_size,
+							Part_start:  currentEBR.Part_start + structures.EBRSize,
+							Part_size:   currentEBR.Part_size - structures.EBRSize,
 							Part_name:   currentEBR.Part_name,
 						}
 						return logicalPart, i, nil
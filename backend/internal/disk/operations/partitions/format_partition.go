@@ -21,6 +21,8 @@ func FormatPartition(id string, formatType string, ext3 bool) error {
 		return err
 	}
 
+	defer memory.LockDisk(path)()
+
 	fmt.Printf("Partition %s formatted with filesystem type %s\n", partition.Name, formatType)
 	fmt.Printf("Path: %s\n", path)
 
@@ -28,26 +30,21 @@ func FormatPartition(id string, formatType string, ext3 bool) error {
 	// Crear un buffer de bytes con el tamaño de la partición
 	buf := make([]byte, partition.Partition.Part_size)
 
-	// Crear un archivo en modo escritura
-	file, err := os.OpenFile(path, os.O_WRONLY, 0666)
+	// Abrir el disco en modo escritura
+	file, err := ext2.OpenDisk(path, os.O_WRONLY)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
-	// Posicionarse en el inicio de la partición
-	_, err = file.Seek(int64(partition.Partition.Part_start), 0)
-	if err != nil {
-		return err
-	}
-
-	// Escribir los 0s en la partición
-	_, err = file.Write(buf)
+	// Escribir los 0s desde el inicio de la partición
+	_, err = file.WriteAt(buf, int64(partition.Partition.Part_start))
 
 	if err != nil {
 		return err
 	}
 
-	n := CalculateN(&partition.Partition)
+	n := CalculateN(&partition.Partition, ext3)
 
 	fmt.Println("N: ", n)
 	journalSize := int32(binary.Size(ext2.Journal{}))
@@ -55,10 +52,14 @@ func FormatPartition(id string, formatType string, ext3 bool) error {
 	JournalStart := partition.Partition.Part_start + int32(binary.Size(ext2.SuperBlock{}))
 
 	var SBmInodeStart int32
+	var filesystemType, journalSlots int32
 
 	if ext3 {
+		filesystemType = 3
+		journalSlots = n
 		SBmInodeStart = JournalStart + (journalSize * n)
 	} else {
+		filesystemType = 2
 		SBmInodeStart = partition.Partition.Part_start + int32(binary.Size(ext2.SuperBlock{}))
 	}
 
@@ -68,7 +69,7 @@ func FormatPartition(id string, formatType string, ext3 bool) error {
 
 	// Crear el SuperBloque del sistema de archivos
 	superBlock := ext2.SuperBlock{
-		SFilesystemType:  3, // Cambiado de 2 (ext2) a 3 (ext3)
+		SFilesystemType:  filesystemType,
 		SInodesCount:     0,
 		SBlocksCount:     0,
 		SFreeBlocksCount: int32(n * 3),
@@ -85,6 +86,7 @@ func FormatPartition(id string, formatType string, ext3 bool) error {
 		SBmBlockStart:    SBmBlockStart,
 		SInodeStart:      SInodeStart,
 		SBlockStart:      SBlockStart,
+		SJournalSize:     journalSlots,
 	}
 
 	// Serializar el SuperBloque
@@ -152,7 +154,9 @@ func FormatPartition(id string, formatType string, ext3 bool) error {
 	return nil
 }
 
-func CalculateN(partition *structures.Partition) int32 {
+// CalculateN calcula la cantidad de inodos de la partición. En ext3 cada inodo
+// también reserva un slot del journal
+func CalculateN(partition *structures.Partition, ext3 bool) int32 {
 	// Calcular el tamaño del SuperBlock
 	superBlockSize := binary.Size(ext2.SuperBlock{})
 
@@ -168,6 +172,9 @@ func CalculateN(partition *structures.Partition) int32 {
 	inodeSize := binary.Size(ext2.INode{})
 	fileBlockSize := binary.Size(ext2.FileBlock{})
 	denominator := 4 + inodeSize + 3*fileBlockSize
+	if ext3 {
+		denominator += binary.Size(ext2.Journal{})
+	}
 
 	// Calcular n
 	n := math.Floor(float64(numerator) / float64(denominator))
@@ -0,0 +1,77 @@
+package partition_operations
+
+import (
+	"fmt"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+)
+
+// CheckFilesystem verifica la consistencia del sistema de archivos de una partición
+// montada y, si fix es true, repara los problemas encontrados
+func CheckFilesystem(id string, fix bool) (string, error) {
+	// Obtener la partición montada
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	// Aun sin -fix la revisión corre dentro de una transacción, así que toma el disco completo
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Leer el superbloque de la partición
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	result, err := superBlock.Check(partitionPath, fix)
+	if err != nil {
+		return "", fmt.Errorf("error al verificar el sistema de archivos: %v", err)
+	}
+
+	// Guardar los contadores reparados
+	if fix && result.Repaired > 0 {
+		err = superBlock.SerializeSuperBlock(partitionPath, partition.Partition.Part_start)
+		if err != nil {
+			return "", fmt.Errorf("error al actualizar el superbloque: %v", err)
+		}
+	}
+
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return "", fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
+	output := &strings.Builder{}
+	fmt.Fprintf(output, "fsck %s (%s)\n", id, partition.Name)
+	fmt.Fprintf(output, "Inodes: %d/%d used\n", result.InodesUsed, result.InodesTotal)
+	fmt.Fprintf(output, "Blocks: %d/%d used\n", result.BlocksUsed, result.BlocksTotal)
+
+	if len(result.Problems) == 0 {
+		fmt.Fprintln(output, "Filesystem is clean")
+		return output.String(), nil
+	}
+
+	for _, problem := range result.Problems {
+		fmt.Fprintf(output, "  - %s\n", problem)
+	}
+
+	fmt.Fprintf(output, "%d problems found", len(result.Problems))
+	if fix {
+		fmt.Fprintf(output, ", %d repaired", result.Repaired)
+	}
+	fmt.Fprintln(output)
+
+	return output.String(), nil
+}
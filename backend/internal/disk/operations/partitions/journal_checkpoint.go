@@ -0,0 +1,58 @@
+package partition_operations
+
+import (
+	"fmt"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+)
+
+// CheckpointJournal recupera las entradas vivas del journal circular de una partición
+// y devuelve cuántas se recuperaron
+func CheckpointJournal(id string) (int32, error) {
+	partition, path, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return 0, fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.LockDisk(path)()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
+	if err != nil {
+		return 0, fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	if !superBlock.HasJournal() {
+		return 0, fmt.Errorf("la partición no tiene journaling (no es ext3)")
+	}
+
+	return ext2.CheckpointJournal(path, partition.Partition.Part_start)
+}
+
+// SetJournalPolicy cambia la política que se aplica cuando el journal de una partición está lleno
+func SetJournalPolicy(id string, policy string) error {
+	partition, path, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.LockDisk(path)()
+
+	value, err := ext2.ParseJournalPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	if !superBlock.HasJournal() {
+		return fmt.Errorf("la partición no tiene journaling (no es ext3)")
+	}
+
+	return ext2.SetJournalPolicy(path, partition.Partition.Part_start, value)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
 	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
 	"disk.simulator.com/m/v2/utils"
 )
@@ -31,8 +32,9 @@ type DirectoryContent struct {
 	ErrorMsg string     `json:"errorMsg"` // Mensaje de error si ocurre alguno
 }
 
-// ListDirectory lista el contenido de un directorio en formato JSON
-func ListDirectory(diskPath string, partitionName string, dirPath string) (string, error) {
+// ListDirectory lista el contenido de un directorio en formato JSON. El usuario
+// de la sesión necesita permiso de lectura sobre el directorio
+func ListDirectory(session *auth.LoggedUser, diskPath string, partitionName string, dirPath string) (string, error) {
 	// Verificar que la partición existe
 	partition, partitionIndex, err := FindPartition(partitionName, diskPath)
 	if err != nil {
@@ -77,6 +79,8 @@ func ListDirectory(diskPath string, partitionName string, dirPath string) (strin
 		return "", fmt.Errorf("error al obtener partición: %v", err)
 	}
 
+	defer memory.RLockDisk(partitionPath)()
+
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(partitionPath, mountedPartition.Partition.Part_start)
 	if err != nil {
@@ -116,6 +120,11 @@ func ListDirectory(diskPath string, partitionName string, dirPath string) (strin
 		return "", fmt.Errorf("'%s' no es un directorio", dirPath)
 	}
 
+	err = checkReadPermission(&superBlock, session, dirInode, dirPath)
+	if err != nil {
+		return "", err
+	}
+
 	// Preparar respuesta
 	response := DirectoryContent{
 		Path:    dirPath,
@@ -141,7 +150,7 @@ func ListDirectory(diskPath string, partitionName string, dirPath string) (strin
 				continue
 			}
 
-			name := strings.TrimRight(string(entry.BName[:]), "\x00")
+			name := superBlock.EntryName(partitionPath, entry)
 			if name != "." && name != ".." {
 				// Leer el inodo de la entrada
 				entryInode := &ext2.INode{}
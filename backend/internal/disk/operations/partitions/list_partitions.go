@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"disk.simulator.com/m/v2/internal/disk/memory"
 	"disk.simulator.com/m/v2/internal/disk/types/structures"
 )
 
@@ -41,6 +42,8 @@ func ListPartitions(path string) ([]PartitionInfo, []LogicalPartitionInfo, error
 		return nil, nil, fmt.Errorf("el disco no existe en la ruta: %s", path)
 	}
 
+	defer memory.RLockDisk(path)()
+
 	// Leer el MBR del disco
 	var mbr structures.MBR
 	err = mbr.DeserializeMBR(path)
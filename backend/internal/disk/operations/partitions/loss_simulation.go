@@ -19,6 +19,8 @@ func SimulateSystemLoss(id string) (string, error) {
 		return "", fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.LockDisk(path)()
+
 	// Leer el superbloque
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
@@ -26,8 +28,13 @@ func SimulateSystemLoss(id string) (string, error) {
 		return "", fmt.Errorf("error al leer el superbloque: %v", err)
 	}
 
+	// Sin journaling no habría desde dónde recuperar la partición después
+	if !superBlock.HasJournal() {
+		return "", fmt.Errorf("la partición no tiene journaling (no es ext3)")
+	}
+
 	// Abrir el archivo en modo escritura
-	file, err := os.OpenFile(path, os.O_WRONLY, 0666)
+	file, err := ext2.OpenDisk(path, os.O_WRONLY)
 	if err != nil {
 		return "", fmt.Errorf("error al abrir el archivo: %v", err)
 	}
@@ -74,17 +81,11 @@ func SimulateSystemLoss(id string) (string, error) {
 }
 
 // cleanArea sobrescribe un área del archivo con caracteres nulos
-func cleanArea(file *os.File, offset int64, size int64) error {
+func cleanArea(file ext2.DiskFile, offset int64, size int64) error {
 	// Si el tamaño es muy grande, podría dividirse en chunks para evitar problemas de memoria
 	bufferSize := 8192 // 8KB chunks
 	buffer := make([]byte, bufferSize)
 
-	// Posicionar el cursor en el offset indicado
-	_, err := file.Seek(offset, 0)
-	if err != nil {
-		return fmt.Errorf("error al posicionarse en offset %d: %v", offset, err)
-	}
-
 	// Escribir bytes nulos en bloques
 	remaining := size
 	for remaining > 0 {
@@ -96,7 +97,7 @@ func cleanArea(file *os.File, offset int64, size int64) error {
 		}
 
 		// Escribir el buffer lleno de ceros
-		_, err = file.Write(buffer)
+		_, err := file.WriteAt(buffer, offset+size-remaining)
 		if err != nil {
 			return fmt.Errorf("error al escribir bytes nulos: %v", err)
 		}
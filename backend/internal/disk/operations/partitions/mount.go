@@ -5,6 +5,7 @@ import (
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
 	"disk.simulator.com/m/v2/internal/disk/types/structures"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
 )
 
 // MountPartition monta una partición en el sistema y le asigna un identificador único.
@@ -14,25 +15,28 @@ import (
 //   - name: nombre de la partición a montar
 //   - path: ruta del archivo de disco
 //
-// Retorna un error si la partición no existe o si hay problemas durante el montaje
-func MountPartition(name string, path string) error {
+// Retorna el ID asignado, o un error si la partición no existe o si hay problemas durante el montaje
+func MountPartition(name string, path string) (string, error) {
+	// Montar reaplica el journal y actualiza el MBR
+	defer memory.LockDisk(path)()
+
 	// Leer el MBR del disco
 	mbr := structures.MBR{}
 	err := mbr.DeserializeMBR(path)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Buscar la partición con el nombre especificado
 	partition, index, err := FindPartition(name, path)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if index == -1 {
-		return fmt.Errorf("partition not found")
+		return "", fmt.Errorf("partition not found")
 	}
 
 	// Montar la partición
@@ -41,15 +45,67 @@ func MountPartition(name string, path string) error {
 	// Agregar la partición al almacenamiento en memoria o actualizar fecha
 	storage := memory.GetInstance()
 
+	// Guardar la entrada actual para poder restaurarla si el montaje falla
+	var previous *memory.MountedPartition
+	if mounted, index := storage.IsPartitionMounted(name, path); mounted {
+		entry := storage.GetMountedPartitions()[index]
+		previous = &entry
+	}
+
 	// No necesitamos verificar si ya está montada aquí, Storage.MountPartition lo maneja
 	id, err := storage.MountPartition(name, path, partition)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	// Mantener el disco abierto mientras la partición esté montada
+	if _, err := ext2.OpenPartition(path); err != nil {
+		fmt.Printf("Advertencia: %v\n", err)
+	}
+
+	// Reaplicar las transacciones confirmadas que no llegaron a escribirse en el disco
+	replayed, discarded, err := ext2.ReplayJournal(path, partition.Part_start)
+	if err != nil {
+		fmt.Printf("Advertencia: no se pudo recuperar el journal: %v\n", err)
+	} else if replayed > 0 || discarded > 0 {
+		fmt.Printf("Journal: %d transacciones reaplicadas, %d incompletas descartadas\n", replayed, discarded)
+	}
+
+	// Escribir el estado de montaje en el MBR para poder validar el montaje al reiniciar.
+	// Las particiones lógicas no tienen Part_id en el EBR, por lo que solo se registran en memoria
+	if partition.Part_type != 'L' {
+		mounted, _, err := storage.GetMountedPartition(id)
+		if err != nil {
+			revertMount(storage, id, path, previous)
+			return "", err
+		}
+
+		mbr.Mbr_partitions[index].Part_mount = '1'
+		mbr.Mbr_partitions[index].Part_correlative = mounted.Partition.Part_correlative
+		mbr.Mbr_partitions[index].Part_id = mounted.Partition.Part_id
+
+		err = mbr.SerializeMBR(path)
+		if err != nil {
+			revertMount(storage, id, path, previous)
+			return "", fmt.Errorf("error al actualizar el MBR: %v", err)
+		}
 	}
 
 	fmt.Printf("Partition mounted successfully with ID: %s\n", id)
 
-	return nil
+	return id, nil
+}
+
+// revertMount deshace el montaje en memoria cuando no se pudo registrar en el
+// MBR y cierra el handle del disco si ninguna otra partición lo usa
+func revertMount(storage *memory.Storage, id string, path string, previous *memory.MountedPartition) {
+	storage.RevertMount(id, previous)
+
+	if !storage.HasMountedPartitions(path) {
+		if err := ext2.ClosePartition(path); err != nil {
+			fmt.Printf("Advertencia: %v\n", err)
+		}
+	}
 }
 
 // UnmountPartition desmonta una partición montada identificada por su ID.
@@ -68,6 +124,32 @@ func UnmountPartition(id string) error {
 		return fmt.Errorf("error al desmontar la partición con ID %s: %v", id, err)
 	}
 
+	// Marcar la partición como desmontada en el MBR (se conservan Part_id y Part_correlative)
+	mounted, path, err := storage.GetMountedPartition(id)
+	if err == nil && mounted.Partition.Part_type != 'L' {
+		defer memory.LockDisk(path)()
+
+		mbr := structures.MBR{}
+		if err := mbr.DeserializeMBR(path); err == nil {
+			for i, part := range mbr.Mbr_partitions {
+				if part.Part_start == mounted.Partition.Part_start && part.Part_id == mounted.Partition.Part_id {
+					mbr.Mbr_partitions[i].Part_mount = '0'
+					if err := mbr.SerializeMBR(path); err != nil {
+						return fmt.Errorf("error al actualizar el MBR: %v", err)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	// Cerrar el handle del disco si ya no tiene particiones montadas
+	if err == nil && !memory.GetInstance().HasMountedPartitions(path) {
+		if err := ext2.ClosePartition(path); err != nil {
+			return fmt.Errorf("error al escribir los cambios del disco: %v", err)
+		}
+	}
+
 	fmt.Printf("Partition with ID %s unmounted successfully\n", id)
 	return nil
 }
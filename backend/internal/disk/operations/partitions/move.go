@@ -1,6 +1,7 @@
 package partition_operations
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -11,20 +12,28 @@ import (
 )
 
 // MoveFileOrDirectory mueve un archivo o directorio a una ubicación de destino
-func MoveFileOrDirectory(sourcePath string, destPath string) error {
-	instance := auth.GetInstance()
+func MoveFileOrDirectory(session *auth.LoggedUser, sourcePath string, destPath string) error {
 
-	if instance.User == nil {
+	if session.User == nil {
 		return fmt.Errorf("error al mover: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 	if err != nil {
 		return fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
 	if err != nil {
@@ -54,8 +63,8 @@ func MoveFileOrDirectory(sourcePath string, destPath string) error {
 	}
 
 	// Convertir uid y gid de string a int32
-	uidInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
-	gidInt, _ := strconv.ParseInt(instance.GID, 10, 32)
+	uidInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gidInt, _ := strconv.ParseInt(session.GID, 10, 32)
 
 	// Ejecutar la operación de movimiento
 	err = superBlock.Move(
@@ -78,7 +87,7 @@ func MoveFileOrDirectory(sourcePath string, destPath string) error {
 	}
 
 	// Si el sistema de archivos es ext3, registrar la operación en el journaling
-	if superBlock.SFilesystemType == 3 {
+	if superBlock.HasJournal() {
 		// Registrar la operación en el journal
 		err = ext2.AddJournal(
 			partitionPath,
@@ -90,6 +99,10 @@ func MoveFileOrDirectory(sourcePath string, destPath string) error {
 		)
 
 		if err != nil {
+			// Con la política "error" el journal lleno impide aplicar la operación
+			if errors.Is(err, ext2.ErrJournalFull) {
+				return err
+			}
 			fmt.Printf("Advertencia: No se pudo registrar la operación en el journaling: %v\n", err)
 			// No retornar error, ya que el movimiento fue exitoso
 		} else {
@@ -97,6 +110,12 @@ func MoveFileOrDirectory(sourcePath string, destPath string) error {
 		}
 	}
 
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
 	fmt.Printf("'%s' fue movido exitosamente a '%s'\n", sourcePath, destPath)
 	return nil
 }
@@ -0,0 +1,81 @@
+package partition_operations
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// ErrPermissionDenied se devuelve cuando el usuario de la sesión no tiene
+// permiso de lectura o de escritura sobre el archivo o la carpeta
+var ErrPermissionDenied = errors.New("permiso denegado")
+
+// checkReadPermission verifica que el usuario de la sesión pueda leer el inodo
+func checkReadPermission(superBlock *ext2.SuperBlock, session *auth.LoggedUser, inode *ext2.INode, path string) error {
+	if session.User == nil {
+		return fmt.Errorf("no hay un usuario loggeado")
+	}
+
+	uid, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gid, _ := strconv.ParseInt(session.GID, 10, 32)
+
+	if !superBlock.CanRead(inode, int32(uid), int32(gid)) {
+		return fmt.Errorf("%w: no tienes permisos de lectura en '%s'", ErrPermissionDenied, path)
+	}
+	return nil
+}
+
+// checkWritePermission verifica que el usuario de la sesión pueda escribir en el inodo
+func checkWritePermission(superBlock *ext2.SuperBlock, session *auth.LoggedUser, inode *ext2.INode, path string) error {
+	if session.User == nil {
+		return fmt.Errorf("no hay un usuario loggeado")
+	}
+
+	uid, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gid, _ := strconv.ParseInt(session.GID, 10, 32)
+
+	if !superBlock.CanWrite(inode, int32(uid), int32(gid)) {
+		return fmt.Errorf("%w: no tienes permisos de escritura en '%s'", ErrPermissionDenied, path)
+	}
+	return nil
+}
+
+// CheckReadAccess verifica que el usuario de la sesión pueda leer la ruta
+// indicada en la partición montada con el id dado. Lo usan los reportes del
+// endpoint /reports, que leen el contenido sin pasar por los comandos
+func CheckReadAccess(session *auth.LoggedUser, id string, path string) error {
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return err
+	}
+
+	defer memory.RLockDisk(partitionPath)()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	// La raíz es el inodo 0; cualquier otra ruta se busca por sus carpetas
+	inodeIndex := int32(0)
+	if strings.Trim(path, "/") != "" {
+		parentDirs, name := utils.GetParentDirectories(path)
+		inodeIndex, err = superBlock.FindFileInode(partitionPath, parentDirs, name)
+		if err != nil {
+			return fmt.Errorf("error al buscar '%s': %v", path, err)
+		}
+	}
+
+	inode, err := superBlock.GetInodeByNumber(partitionPath, inodeIndex)
+	if err != nil {
+		return fmt.Errorf("error al leer el inodo de '%s': %v", path, err)
+	}
+	return checkReadPermission(&superBlock, session, inode, path)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // RecoverFromJournaling recupera archivos y carpetas ejecutando las operaciones registradas en el journaling
-func RecoverFromJournaling(id string) (string, error) {
+func RecoverFromJournaling(session *auth.LoggedUser, id string) (string, error) {
 	var output strings.Builder
 
 	// Obtener la partición montada
@@ -22,6 +22,8 @@ func RecoverFromJournaling(id string) (string, error) {
 		return "", fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.LockDisk(path)()
+
 	// Leer el superbloque
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
@@ -30,15 +32,12 @@ func RecoverFromJournaling(id string) (string, error) {
 	}
 
 	// Verificar si es ext3 (tiene journaling)
-	if superBlock.SFilesystemType != 3 {
+	if !superBlock.HasJournal() {
 		return "", fmt.Errorf("la partición no tiene journaling (no es ext3)")
 	}
 
-	// El inicio del journaling es justo después del SuperBlock
-	journalStart := partition.Partition.Part_start + ext2.SuperBlockSize
-
-	// Obtener todas las entradas del journal
-	journals, err := ext2.GetJournaling(path, int64(journalStart), superBlock.SFreeInodesCount)
+	// Obtener las entradas vivas del journal (entre head y tail)
+	journals, err := ext2.GetJournaling(path, partition.Partition.Part_start)
 	if err != nil {
 		return "", fmt.Errorf("error al obtener el journaling: %v", err)
 	}
@@ -48,8 +47,7 @@ func RecoverFromJournaling(id string) (string, error) {
 	}
 
 	// Verificar si hay un usuario conectado para ejecutar las operaciones
-	instance := auth.GetInstance()
-	if instance.User == nil {
+	if session.User == nil {
 		return "", fmt.Errorf("error al recuperar archivos: no hay un usuario loggeado")
 	}
 
@@ -81,15 +79,15 @@ func RecoverFromJournaling(id string) (string, error) {
 	// para asegurarse de que la estructura de directorios esté lista
 	output.WriteString("\nFase 1: Creando estructura de directorios...\n")
 	for i, journal := range journals {
-		operation := strings.TrimRight(string(journal.J_content.I_operation[:]), "\x00")
-		filePath := strings.TrimRight(string(journal.J_content.I_path[:]), "\x00")
+		operation := journal.Operation
+		filePath := journal.Path
 
 		if operation == "mkdir" {
 			output.WriteString(fmt.Sprintf("[Paso 1/%d] Creando directorio '%s'...\n",
 				i+1, filePath))
 
 			// Crear el directorio con la opción recursiva
-			err := CreateDirectory(filePath, true)
+			err := createDirectory(session, filePath, true)
 			if err != nil {
 				output.WriteString(fmt.Sprintf("  ADVERTENCIA: Error al crear directorio '%s': %v\n", filePath, err))
 			} else {
@@ -104,10 +102,10 @@ func RecoverFromJournaling(id string) (string, error) {
 	directoriesNeeded := make(map[string]bool)
 
 	for _, journal := range journals {
-		operation := strings.TrimRight(string(journal.J_content.I_operation[:]), "\x00")
-		filePath := strings.TrimRight(string(journal.J_content.I_path[:]), "\x00")
+		operation := journal.Operation
+		filePath := journal.Path
 
-		if operation == "mkfile" {
+		if operation == "mkfile" || operation == "upload" {
 			// Obtener la ruta del directorio padre
 			parentPath := filepath.Dir(filePath)
 			if parentPath != "/" {
@@ -119,7 +117,7 @@ func RecoverFromJournaling(id string) (string, error) {
 	// Crear todos los directorios padre necesarios
 	for dirPath := range directoriesNeeded {
 		output.WriteString(fmt.Sprintf("Asegurando directorio: %s\n", dirPath))
-		err := CreateDirectory(dirPath, true)
+		err := createDirectory(session, dirPath, true)
 		if err != nil {
 			output.WriteString(fmt.Sprintf("  ADVERTENCIA: No se pudo crear el directorio '%s': %v\n", dirPath, err))
 		}
@@ -128,12 +126,12 @@ func RecoverFromJournaling(id string) (string, error) {
 	// Finalmente, procesar todas las operaciones de archivo
 	output.WriteString("\nFase 3: Recuperando archivos y aplicando otras operaciones...\n")
 	for i, journal := range journals {
-		operation := strings.TrimRight(string(journal.J_content.I_operation[:]), "\x00")
-		filePath := strings.TrimRight(string(journal.J_content.I_path[:]), "\x00")
-		content := strings.TrimRight(string(journal.J_content.I_content[:]), "\x00")
+		operation := journal.Operation
+		filePath := journal.Path
+		content := journal.Content
 
 		// Fecha como referencia
-		date := time.Unix(int64(journal.J_content.I_date), 0)
+		date := journal.Date
 
 		output.WriteString(fmt.Sprintf("[%d/%d] Procesando operación '%s' en '%s' (registrada: %s)...\n",
 			i+1, len(journals), operation, filePath, date.Format("02/01/2006 15:04:05")))
@@ -145,18 +143,18 @@ func RecoverFromJournaling(id string) (string, error) {
 			output.WriteString(fmt.Sprintf("  ✓ Directorio ya procesado: %s\n", filePath))
 
 		case "mkfile":
-			// Determinar el tamaño del archivo (por defecto 0)
-			size := 0
+			// El archivo se crea con el tamaño del contenido registrado (por defecto 0)
+			size := len(content)
 
 			// Intentar crear el archivo
-			err := CreateFile(filePath, size, "", true)
+			err := createFile(session, filePath, size, "", true)
 			if err != nil {
 				output.WriteString(fmt.Sprintf("  ADVERTENCIA: Error al recrear archivo '%s': %v\n", filePath, err))
 			} else {
 				// Si hay contenido en la entrada del journal, intentamos editar el archivo
 				if content != "" {
 					// Intentar escribir el contenido
-					err = EditFile(filePath, content)
+					err = editFileContent(session, filePath, content)
 					if err != nil {
 						output.WriteString(fmt.Sprintf("  ADVERTENCIA: Error al restaurar contenido de '%s': %v\n", filePath, err))
 					}
@@ -164,13 +162,26 @@ func RecoverFromJournaling(id string) (string, error) {
 				output.WriteString(fmt.Sprintf("  ✓ Archivo recuperado: %s\n", filePath))
 			}
 
+		case "upload":
+			// El journal solo registra el tamaño y el hash de los archivos subidos,
+			// así que se recrean con ese tamaño pero sin su contenido
+			var size int
+			fmt.Sscanf(content, "%d bytes", &size)
+
+			err := createFile(session, filePath, size, "", true)
+			if err != nil {
+				output.WriteString(fmt.Sprintf("  ADVERTENCIA: Error al recrear archivo '%s': %v\n", filePath, err))
+			} else {
+				output.WriteString(fmt.Sprintf("  ✓ Archivo recuperado sin su contenido (%s): %s\n", content, filePath))
+			}
+
 		case "remove":
 			// No hacemos nada en caso de eliminación, ya que queremos recuperar archivos
 			output.WriteString(fmt.Sprintf("  ✓ Ignorando operación de eliminación para '%s'\n", filePath))
 
 		case "edit":
 			// Intentar editar el contenido si el archivo existe
-			err = EditFile(filePath, content)
+			err = editFileContent(session, filePath, content)
 			if err != nil {
 				output.WriteString(fmt.Sprintf("  ADVERTENCIA: Error al editar '%s': %v\n", filePath, err))
 			} else {
@@ -242,18 +253,14 @@ func recreateRootDirectory(sb *ext2.SuperBlock, path string) error {
 	}
 
 	// Actualizar el bitmap de inodos para marcar el primer inodo como usado
-	file, err := os.OpenFile(path, os.O_WRONLY, 0666)
+	file, err := ext2.OpenDisk(path, os.O_WRONLY)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
 	// Marcar como usado el primer inodo en el bitmap
-	_, err = file.Seek(int64(sb.SBmInodeStart), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	_, err = file.WriteAt([]byte{1}, int64(sb.SBmInodeStart)) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -275,11 +282,7 @@ func recreateRootDirectory(sb *ext2.SuperBlock, path string) error {
 	}
 
 	// Marcar como usado el primer bloque en el bitmap
-	_, err = file.Seek(int64(sb.SBmBlockStart), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	_, err = file.WriteAt([]byte{1}, int64(sb.SBmBlockStart)) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -340,18 +343,14 @@ func recreateUsersFile(sb *ext2.SuperBlock, path string, usersText string) error
 	}
 
 	// Actualizar el bitmap de inodos para marcar el segundo inodo como usado
-	file, err := os.OpenFile(path, os.O_WRONLY, 0666)
+	file, err := ext2.OpenDisk(path, os.O_WRONLY)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
 	// Marcar como usado el segundo inodo en el bitmap
-	_, err = file.Seek(int64(sb.SBmInodeStart+1), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	_, err = file.WriteAt([]byte{1}, int64(sb.SBmInodeStart+1)) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -369,11 +368,7 @@ func recreateUsersFile(sb *ext2.SuperBlock, path string, usersText string) error
 	}
 
 	// Marcar como usado el segundo bloque en el bitmap
-	_, err = file.Seek(int64(sb.SBmBlockStart+1), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	_, err = file.WriteAt([]byte{1}, int64(sb.SBmBlockStart+1)) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -10,20 +10,28 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func RemoveFileOrDirectory(path string) error {
-	instance := auth.GetInstance()
+func RemoveFileOrDirectory(session *auth.LoggedUser, path string) error {
 
-	if instance.User == nil {
+	if session.User == nil {
 		return fmt.Errorf("error al eliminar archivo o directorio: no hay un usuario loggeado")
 	}
 
-	id := instance.ID
+	id := session.ID
 
 	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
 	if err != nil {
 		return fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
 	parentDirs, destFile := utils.GetParentDirectories(path)
 
 	superBlock := ext2.SuperBlock{}
@@ -32,14 +40,31 @@ func RemoveFileOrDirectory(path string) error {
 		return fmt.Errorf("error al leer el superbloque: %v", err)
 	}
 
-	uidInt, _ := strconv.ParseInt(instance.User.UID, 10, 32)
-	gidInt, _ := strconv.ParseInt(instance.GID, 10, 32)
+	uidInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gidInt, _ := strconv.ParseInt(session.GID, 10, 32)
 
-	return superBlock.RemoveFileOrDirectory(
+	err = superBlock.RemoveFileOrDirectory(
 		partitionPath,
 		parentDirs,
 		destFile,
 		int32(uidInt),
 		int32(gidInt),
 	)
+	if err != nil {
+		return err
+	}
+
+	// Actualizar el superbloque con los inodos y bloques liberados
+	err = superBlock.SerializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al actualizar el superbloque: %v", err)
+	}
+
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,118 @@
+package partition_operations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// StatFile muestra todos los campos del inodo de un archivo o carpeta
+func StatFile(session *auth.LoggedUser, path string) (string, error) {
+	if session.User == nil {
+		return "", fmt.Errorf("error: no hay un usuario loggeado")
+	}
+
+	// Obtener la partición montada
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(session.ID)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.RLockDisk(partitionPath)()
+
+	// Leer el superbloque de la partición
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	inodeIndex, err := findPathInode(&superBlock, partitionPath, path)
+	if err != nil {
+		return "", err
+	}
+
+	inode := &ext2.INode{}
+	err = inode.Deserialize(partitionPath, int64(superBlock.SInodeStart+(inodeIndex*superBlock.SInodeS)))
+	if err != nil {
+		return "", fmt.Errorf("error al leer el inodo: %v", err)
+	}
+
+	refs, err := superBlock.InodeBlockList(partitionPath, inode)
+	if err != nil {
+		return "", fmt.Errorf("error al leer los bloques del inodo: %v", err)
+	}
+
+	// Resolver los nombres del propietario y del grupo desde users.txt
+	userName, groupName := "?", "?"
+	content, err := superBlock.FileContent(partitionPath, []string{}, "users.txt")
+	if err == nil {
+		if user, _ := utils.FindUserByUID(content, strconv.Itoa(int(inode.IUid))); user != nil {
+			userName = user.Username
+		}
+		if group, _ := utils.FindGroupByGID(content, strconv.Itoa(int(inode.IGid))); group != nil {
+			groupName = group.Name
+		}
+	}
+
+	fileType := "file"
+	if inode.IType[0] == '0' {
+		fileType = "directory"
+	}
+
+	output := &strings.Builder{}
+	fmt.Fprintf(output, "File: %s\n", normalizePath(path))
+	fmt.Fprintf(output, "Inode: %d\n", inodeIndex)
+	fmt.Fprintf(output, "Type: %s (%s)\n", fileType, string(inode.IType[:]))
+	fmt.Fprintf(output, "Size: %d bytes\n", inode.ISize)
+	fmt.Fprintf(output, "Blocks: %d\n", len(refs))
+	fmt.Fprintf(output, "Permissions: %s\n", string(inode.IPerm[:]))
+	fmt.Fprintf(output, "Uid: %d (%s)\n", inode.IUid, userName)
+	fmt.Fprintf(output, "Gid: %d (%s)\n", inode.IGid, groupName)
+	fmt.Fprintf(output, "Access: %s\n", formatInodeTime(inode.IAtime))
+	fmt.Fprintf(output, "Modify: %s\n", formatInodeTime(inode.IMtime))
+	fmt.Fprintf(output, "Create: %s\n", formatInodeTime(inode.ICtime))
+	fmt.Fprintf(output, "I_block: %v\n", inode.IBlock)
+
+	// Lista de bloques agrupada por nivel de indirección
+	levelNames := []string{"direct", "single indirect", "double indirect", "triple indirect"}
+	for level, levelName := range levelNames {
+		var data, pointers []string
+		for _, ref := range refs {
+			if ref.Level != level {
+				continue
+			}
+			if ref.Pointer {
+				pointers = append(pointers, strconv.Itoa(int(ref.Block)))
+			} else {
+				data = append(data, strconv.Itoa(int(ref.Block)))
+			}
+		}
+
+		if len(data) == 0 && len(pointers) == 0 {
+			continue
+		}
+
+		line := fmt.Sprintf("  %s: [%s]", levelName, strings.Join(data, " "))
+		if len(pointers) > 0 {
+			line += fmt.Sprintf(" pointers: [%s]", strings.Join(pointers, " "))
+		}
+		fmt.Fprintln(output, line)
+	}
+
+	return output.String(), nil
+}
+
+// formatInodeTime convierte una fecha del inodo a texto
+func formatInodeTime(t float32) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(int64(t), 0).Format(time.RFC3339)
+}
@@ -0,0 +1,48 @@
+package partition_operations
+
+import (
+	"fmt"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+)
+
+// TunePartition cambia parámetros del sistema de archivos de una partición
+// montada sin formatearla. Con journal convierte una partición ext2 en ext3
+func TunePartition(id string, journal bool) (string, error) {
+	if !journal {
+		return "", fmt.Errorf("no se indicó ningún cambio (use -journal)")
+	}
+
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.LockDisk(partitionPath)()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return "", fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	if superBlock.HasJournal() {
+		return "", fmt.Errorf("la partición ya tiene journaling (es ext3)")
+	}
+
+	// El journal tiene un slot por inodo, igual que al formatear con mkfs -fs=3fs
+	n := CalculateN(&partition.Partition, true)
+	err = superBlock.AddJournalArea(partitionPath, partition.Partition.Part_start, n)
+	if err != nil {
+		return "", fmt.Errorf("error al agregar el journal: %v", err)
+	}
+
+	output := &strings.Builder{}
+	fmt.Fprintf(output, "Partition %s (%s) converted to ext3\n", id, partition.Name)
+	fmt.Fprintf(output, "Journal: %d slots\n", superBlock.SJournalSize)
+	fmt.Fprintf(output, "Inodes: %d, Blocks: %d\n", superBlock.InodesTotal(), superBlock.BlocksTotal())
+
+	return output.String(), nil
+}
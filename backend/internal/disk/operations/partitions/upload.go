@@ -0,0 +1,175 @@
+package partition_operations
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// UploadFile crea el archivo filePath con size bytes leídos de reader. El
+// contenido pasa de a un bloque al disco, así que sirve para archivos binarios
+// y para los que usan los bloques indirectos. Lo usan mkfile -src y /upload
+func UploadFile(session *auth.LoggedUser, filePath string, reader io.Reader, size int64, r bool) error {
+	if session.User == nil {
+		return fmt.Errorf("error al subir el archivo: no hay un usuario loggeado")
+	}
+
+	if size > int64(ext2.MaxFileSize) {
+		return fmt.Errorf("el archivo de %d bytes excede el tamaño máximo de %d bytes", size, ext2.MaxFileSize)
+	}
+
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(session.ID)
+	if err != nil {
+		return fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.LockDisk(partitionPath)()
+
+	// Agrupar las escrituras de la operación en una transacción del journal
+	tx, err := ext2.BeginTransaction(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	parentDirs, fileName := utils.GetParentDirectories(filePath)
+
+	// La carpeta más profunda que ya existe es la que recibe el archivo o las
+	// carpetas que crea -r, así que debe permitir escribir
+	for i := len(parentDirs); i >= 0; i-- {
+		dirIndex, err := findPathInode(&superBlock, partitionPath, "/"+strings.Join(parentDirs[:i], "/"))
+		if err != nil {
+			continue
+		}
+
+		dir, err := superBlock.GetInodeByNumber(partitionPath, dirIndex)
+		if err != nil {
+			return fmt.Errorf("error al leer el inodo de la carpeta: %v", err)
+		}
+		if dir.IType[0] == '0' {
+			err = checkWritePermission(&superBlock, session, dir, "/"+strings.Join(parentDirs[:i], "/"))
+			if err != nil {
+				return err
+			}
+		}
+		break
+	}
+
+	uidInt, _ := strconv.ParseInt(session.User.UID, 10, 32)
+	gidInt, _ := strconv.ParseInt(session.GID, 10, 32)
+
+	// Primero se crea el archivo vacío: así se validan el nombre, los permisos
+	// de la carpeta y que no exista, y después se llenan sus bloques
+	err = superBlock.CreateFile(partitionPath, parentDirs, fileName, 0, "", r, int32(uidInt), int32(gidInt))
+	if err != nil {
+		return fmt.Errorf("error al crear el archivo: %v", err)
+	}
+
+	inodeIndex, err := superBlock.FindFileInode(partitionPath, parentDirs, fileName)
+	if err != nil {
+		return fmt.Errorf("error al buscar el archivo creado: %v", err)
+	}
+
+	// En ext3 el journal registra el tamaño y el hash del contenido; guardar el
+	// contenido completo obligaría a tener todo el archivo en memoria
+	hash := sha256.New()
+	if superBlock.HasJournal() {
+		reader = io.TeeReader(reader, hash)
+	}
+
+	// Los bloques del archivo se escriben directo en el disco antes del commit;
+	// la transacción solo guarda el inodo, los bitmaps, la carpeta y el superbloque
+	err = superBlock.WriteFileContent(partitionPath, inodeIndex, reader, size)
+	if err != nil {
+		return fmt.Errorf("error al escribir el contenido: %v", err)
+	}
+
+	err = superBlock.SerializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al actualizar el superbloque: %v", err)
+	}
+
+	if superBlock.HasJournal() {
+		err = ext2.AddJournal(
+			partitionPath,
+			int64(partition.Partition.Part_start),
+			0, // Este parámetro es ignorado ahora
+			"upload",
+			filePath,
+			fmt.Sprintf("%d bytes, sha256 %x", size, hash.Sum(nil)),
+		)
+
+		if err != nil {
+			// Con la política "error" el journal lleno impide aplicar la operación
+			if errors.Is(err, ext2.ErrJournalFull) {
+				return err
+			}
+			fmt.Printf("Advertencia: No se pudo registrar la operación en el journaling: %v\n", err)
+		}
+	}
+
+	// Confirmar la transacción: se escribe en el journal y luego se aplica en el disco
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %v", err)
+	}
+
+	return nil
+}
+
+// DownloadFile escribe el contenido del archivo filePath en el writer que
+// retorna open, que recibe antes el tamaño del archivo. Se necesita permiso de
+// lectura; si falla algo antes de llamar a open no se escribe nada
+func DownloadFile(session *auth.LoggedUser, filePath string, open func(size int64) io.Writer) error {
+	if session.User == nil {
+		return fmt.Errorf("error al descargar el archivo: no hay un usuario loggeado")
+	}
+
+	partition, partitionPath, err := memory.GetInstance().GetMountedPartition(session.ID)
+	if err != nil {
+		return fmt.Errorf("error al obtener la partición: %v", err)
+	}
+
+	defer memory.RLockDisk(partitionPath)()
+
+	superBlock := ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(partitionPath, partition.Partition.Part_start)
+	if err != nil {
+		return fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	inodeIndex, err := findPathInode(&superBlock, partitionPath, filePath)
+	if err != nil {
+		return err
+	}
+
+	inode, err := superBlock.GetInodeByNumber(partitionPath, inodeIndex)
+	if err != nil {
+		return fmt.Errorf("error al leer el inodo del archivo: %v", err)
+	}
+
+	if inode.IType[0] != '1' {
+		return fmt.Errorf("'%s' no es un archivo", normalizePath(filePath))
+	}
+
+	err = checkReadPermission(&superBlock, session, inode, normalizePath(filePath))
+	if err != nil {
+		return err
+	}
+
+	return superBlock.CopyFileContent(partitionPath, inode, open(int64(inode.ISize)))
+}
@@ -13,6 +13,7 @@ import (
 func BBlockReport(
 	outputPath string,
 	id string,
+	renderer Renderer,
 ) error {
 
 	partition, diskPath, err := memory.GetInstance().GetMountedPartition(id)
@@ -21,6 +22,8 @@ func BBlockReport(
 		return err
 	}
 
+	defer memory.RLockDisk(diskPath)()
+
 	superBlock := ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(diskPath, partition.Partition.Part_start)
 	superBlock.Print()
@@ -59,6 +62,17 @@ func BBlockReport(
 		}
 	}
 
+	// Con una extensión de imagen el bitmap se dibuja como una cuadrícula
+	if isImageOutput(outputPath) {
+		err = renderTables(outputPath, renderer, "Reporte de Bitmap de Bloques", []reportTable{bitmapTable("BITMAP DE BLOQUES", bitmapContent.String())}, false)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Imagen del bitmap de bloques generada:", outputPath)
+		return nil
+	}
+
 	out, err := os.Create(outputPath)
 	if err != nil {
 		return err
@@ -13,6 +13,7 @@ import (
 func BInodeReport(
 	outputPath string,
 	id string,
+	renderer Renderer,
 
 ) error {
 	partition, diskPath, err := memory.GetInstance().GetMountedPartition(id)
@@ -21,6 +22,8 @@ func BInodeReport(
 		return err
 	}
 
+	defer memory.RLockDisk(diskPath)()
+
 	superBlock := ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(diskPath, partition.Partition.Part_start)
 	superBlock.Print()
@@ -62,6 +65,17 @@ func BInodeReport(
 		}
 	}
 
+	// Con una extensión de imagen el bitmap se dibuja como una cuadrícula
+	if isImageOutput(outputPath) {
+		err = renderTables(outputPath, renderer, "Reporte de Bitmap de Inodos", []reportTable{bitmapTable("BITMAP DE INODOS", bitmapContent.String())}, false)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("Imagen del bitmap de inodos generada:", outputPath)
+		return nil
+	}
+
 	txtFile, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("error al crear el archivo TXT: %v", err)
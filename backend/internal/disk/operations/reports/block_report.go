@@ -22,6 +22,8 @@ func BlockReport(
 		return err
 	}
 
+	defer memory.RLockDisk(path)()
+
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
 	if err != nil {
@@ -128,7 +130,7 @@ func BlockReport(
 						continue
 					}
 
-					name := strings.TrimRight(string(content.BName[:]), "\x00")
+					name := superBlock.EntryName(path, content)
 					if name == "" {
 						continue
 					}
@@ -3,9 +3,6 @@ package reports
 import (
 	"bytes"
 	"fmt"
-	"html"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -16,14 +13,14 @@ import (
 )
 
 type DiskPartition struct {
-	Type       string // "MBR", "Primaria", "Extendida", "Lógica", "EBR", "Libre"
-	Start      int64
-	Size       int64
-	Percentage float64
-	Name       string
+	Type       string  `json:"type"` // "MBR", "Primaria", "Extendida", "Lógica", "EBR", "Libre"
+	Start      int64   `json:"start"`
+	Size       int64   `json:"size"`
+	Percentage float64 `json:"percentage"`
+	Name       string  `json:"name"`
 }
 
-func DiskReport(outputPath string, id string) error {
+func DiskReport(outputPath string, id string, renderer Renderer) error {
 	_, diskPath, err := memory.GetInstance().GetMountedPartition(id)
 
 	fmt.Println("Generando reporte de disco en:", outputPath)
@@ -32,33 +29,85 @@ func DiskReport(outputPath string, id string) error {
 		return err
 	}
 
+	defer memory.RLockDisk(diskPath)()
+
 	// Crear las carpetas necesarias para el archivo de salida
 	err = utils.CreateParentDirs(outputPath)
 	if err != nil {
 		return err
 	}
 
-	// Generar el nombre para el archivo .dot basado en la ruta de salida
-	dotFileName := filepath.Join(filepath.Dir(outputPath), filepath.Base(outputPath)+".dot")
-
-	// Deserializar el MBR
-	mbr := structures.MBR{}
-	err = mbr.DeserializeMBR(diskPath)
+	// Obtener las particiones y los espacios libres del disco, en orden
+	partitions, err := diskPartitions(diskPath)
 	if err != nil {
 		return err
 	}
 
-	// Obtener el tamaño total del disco
-	diskSize := mbr.Mbr_size
+	// Usar el nombre del archivo como nombre del disco
+	diskName := filepath.Base(diskPath)
+
+	// Una fila con una celda por partición, con ancho proporcional a su tamaño
+	table := reportTable{Rows: []reportRow{headerRow("Estructura del Disco", "#4b6584")}}
+	var row reportRow
+
+	for _, p := range partitions {
+		bgColor := "#FFFFFF" // Color por defecto
+		textColor := "#000000"
+
+		switch p.Type {
+		case "MBR":
+			bgColor = "#3498db" // Azul
+			textColor = "#FFFFFF"
+		case "Primaria":
+			bgColor = "#2ecc71" // Verde
+		case "Extendida":
+			bgColor = "#e74c3c" // Rojo
+		case "Lógica":
+			bgColor = "#9b59b6" // Púrpura
+		case "EBR":
+			bgColor = "#f39c12" // Naranja
+		case "Libre":
+			bgColor = "#ecf0f1" // Gris claro
+		}
+
+		// Calcular el ancho de la celda (mínimo 1%)
+		width := int(p.Percentage)
+		if width < 1 {
+			width = 1
+		}
 
-	// Obtener información del archivo de disco
-	_, err = os.Stat(diskPath)
+		row = append(row, reportCell{
+			Lines: []string{p.Type, fmt.Sprintf("%.1f%%", p.Percentage), p.Name},
+			Fill:  bgColor,
+			Color: textColor,
+			Width: float64(width),
+		})
+	}
+	table.Rows = append(table.Rows, row)
+
+	// Dibujar el reporte (Graphviz solo si se pidió explícitamente)
+	err = renderTables(outputPath, renderer, "Reporte DISK - "+diskName, []reportTable{table}, false)
 	if err != nil {
 		return err
 	}
 
-	// Usar el nombre del archivo como nombre del disco
-	diskName := filepath.Base(diskPath)
+	fmt.Println("Reporte de disco creado exitosamente en:", outputPath)
+
+	return nil
+}
+
+// diskPartitions recorre el MBR y las cadenas de EBR del disco y devuelve sus
+// segmentos (MBR, particiones, EBR y espacios libres) ordenados por posición
+func diskPartitions(diskPath string) ([]DiskPartition, error) {
+	// Deserializar el MBR
+	mbr := structures.MBR{}
+	err := mbr.DeserializeMBR(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtener el tamaño total del disco
+	diskSize := mbr.Mbr_size
 
 	// Lista para almacenar todas las particiones y espacios libres
 	var partitions []DiskPartition
@@ -132,7 +181,7 @@ func DiskReport(outputPath string, id string) error {
 				ebr := structures.EBR{}
 				err := ebr.DeserializeEBR(diskPath, currentEBRStart)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				// Agregar el EBR
@@ -186,87 +235,7 @@ func DiskReport(outputPath string, id string) error {
 		})
 	}
 
-	// Generar contenido dot con un formato extremadamente simple para evitar problemas
-	dotContent := `digraph G {
-    bgcolor = "#f7f7f7";
-    
-    node [shape = plaintext; fontname = "Arial"; style = "filled"; fillcolor = "#FFFFFF"; color = "#333333"];
-    
-    edge [color = "#666666"; penwidth = 1.5];
-    
-    label = "Reporte DISK - ` + html.EscapeString(diskName) + `";
-    labelloc = "t";
-    fontsize = "20";
-    fontname = "Arial Bold";
-    fontcolor = "#2c3e50";
-    
-    disk [label = <<table border="0" cellborder="1" cellspacing="0" cellpadding="6" style="rounded">
-        <tr>
-            <td colspan="` + fmt.Sprintf("%d", len(partitions)) + `" bgcolor="#4b6584"><font color="white"><b>Estructura del Disco</b></font></td>
-        </tr>
-        <tr>`
-
-	// Añadir las particiones con una estructura simplificada
-	for _, p := range partitions {
-		bgColor := "#FFFFFF" // Color por defecto
-		textColor := "#000000"
-
-		switch p.Type {
-		case "MBR":
-			bgColor = "#3498db" // Azul
-			textColor = "#FFFFFF"
-		case "Primaria":
-			bgColor = "#2ecc71" // Verde
-		case "Extendida":
-			bgColor = "#e74c3c" // Rojo
-		case "Lógica":
-			bgColor = "#9b59b6" // Púrpura
-		case "EBR":
-			bgColor = "#f39c12" // Naranja
-		case "Libre":
-			bgColor = "#ecf0f1" // Gris claro
-		}
-
-		// Calcular el ancho de la celda (mínimo 1%)
-		width := int(p.Percentage)
-		if width < 1 {
-			width = 1
-		}
-
-		// Escapar nombre para HTML y asegurar que sea seguro
-		escapedName := html.EscapeString(p.Name)
-
-		// Estructura simplificada sin tablas anidadas
-		cellContent := fmt.Sprintf(`<td bgcolor="%s" width="%d"><font color="%s"><b>%s</b><br/>%.1f%%<br/>%s</font></td>`,
-			bgColor, width, textColor, p.Type, p.Percentage, escapedName)
-
-		dotContent += cellContent
-	}
-
-	dotContent += `
-        </tr>
-    </table>>];
-}`
-
-	// Escribir el archivo dot
-	err = os.WriteFile(dotFileName, []byte(dotContent), 0644)
-	if err != nil {
-		return fmt.Errorf("error al escribir el archivo dot: %v", err)
-	}
-
-	// Convertir dot a imagen
-	cmd := exec.Command("dot", "-Tpng", dotFileName, "-o", outputPath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// Imprimir el contenido del archivo dot para depuración
-		fmt.Println("Contenido del archivo DOT que causó el error:")
-		fmt.Println(dotContent)
-		return fmt.Errorf("error al generar la imagen con dot: %v\nOutput: %s", err, string(output))
-	}
-
-	fmt.Println("Reporte de disco creado exitosamente en:", outputPath)
-
-	return nil
+	return partitions, nil
 }
 
 // cleanName limpia el nombre de una partición eliminando caracteres nulos
@@ -16,6 +16,8 @@ func FileReport(path_file string, output_path string, id string) error {
 		return fmt.Errorf("error al obtener partición: %v", err)
 	}
 
+	defer memory.RLockDisk(partitionPath)()
+
 	// Separar ruta en directorios padres y nombre de archivo
 	parentDirs, fileName := utils.GetParentDirectories(path_file)
 
@@ -27,7 +29,7 @@ func FileReport(path_file string, output_path string, id string) error {
 	}
 
 	// Leer contenido desde ext2
-	content, err := superBlock.ReadFile(partitionPath, parentDirs, fileName)
+	content, err := superBlock.FileContent(partitionPath, parentDirs, fileName)
 	if err != nil {
 		return fmt.Errorf("error leyendo archivo: %v", err)
 	}
@@ -3,7 +3,6 @@ package reports
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"disk.simulator.com/m/v2/internal/disk/memory"
 	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
@@ -17,6 +16,8 @@ func JournalingReport(outputPath string, id string) (string, error) {
 		return "", fmt.Errorf("error al obtener la partición: %v", err)
 	}
 
+	defer memory.RLockDisk(path)()
+
 	// Leer el superbloque
 	superBlock := ext2.SuperBlock{}
 	err = superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
@@ -25,15 +26,12 @@ func JournalingReport(outputPath string, id string) (string, error) {
 	}
 
 	// Verificar si es ext3 (tiene journaling)
-	journalStart := partition.Partition.Part_start + ext2.SuperBlockSize
-
-	// Simplemente verificamos el tipo de sistema de archivos
-	if superBlock.SFilesystemType != 3 {
+	if !superBlock.HasJournal() {
 		return "", fmt.Errorf("la partición no tiene journaling (no es ext3)")
 	}
 
-	// Obtener todas las entradas del journal
-	journals, err := ext2.GetJournaling(path, int64(journalStart), superBlock.SFreeInodesCount)
+	// Obtener las entradas vivas del journal (entre head y tail)
+	journals, err := ext2.GetJournaling(path, partition.Partition.Part_start)
 	if err != nil {
 		return "", fmt.Errorf("error al obtener el journaling: %v", err)
 	}
@@ -47,24 +45,21 @@ func JournalingReport(outputPath string, id string) (string, error) {
 	reportBuilder.WriteString(fmt.Sprintf("Partición: %s (ID: %s)\n", partition.Name, id))
 	reportBuilder.WriteString(fmt.Sprintf("Tipo de sistema de archivos: EXT%d\n", superBlock.SFilesystemType))
 	reportBuilder.WriteString(fmt.Sprintf("Número total de transacciones: %d\n", len(journals)))
+	reportBuilder.WriteString(fmt.Sprintf("Journal circular: head=%d, tail=%d, %d/%d slots usados\n",
+		superBlock.SJournalHead, superBlock.SJournalTail, superBlock.JournalUsed(), superBlock.JournalCapacity(partition.Partition.Part_start)))
+	reportBuilder.WriteString(fmt.Sprintf("Política de journal lleno: %s\n", ext2.JournalPolicyName(superBlock.SJournalPolicy)))
 	reportBuilder.WriteString("=============================================\n\n")
 
-	for i, journal := range journals {
+	for _, journal := range journals {
 		// Convertir el tiempo a formato legible
-		date := time.Unix(int64(journal.J_content.I_date), 0)
-		formattedDate := date.Format("02/01/2006 15:04:05")
-
-		// Extraer información de la transacción
-		operation := string(journal.J_content.I_operation[:])
-		filePath := string(journal.J_content.I_path[:])
-		content := string(journal.J_content.I_content[:])
+		formattedDate := journal.Date.Format("02/01/2006 15:04:05")
 
-		// Limpiar strings eliminando caracteres nulos
-		operation = cleanString(operation)
-		filePath = cleanString(filePath)
-		content = cleanString(content)
+		// La ruta y el contenido ya vienen reensamblados desde sus slots de continuación
+		operation := journal.Operation
+		filePath := journal.Path
+		content := journal.Content
 
-		reportBuilder.WriteString(fmt.Sprintf("TRANSACCIÓN #%d\n", i))
+		reportBuilder.WriteString(fmt.Sprintf("TRANSACCIÓN #%d\n", journal.Sequence))
 		reportBuilder.WriteString(fmt.Sprintf("- Operación:  %s\n", operation))
 		reportBuilder.WriteString(fmt.Sprintf("- Ruta:       %s\n", filePath))
 		reportBuilder.WriteString(fmt.Sprintf("- Contenido:  %s\n", content))
@@ -79,8 +74,3 @@ func JournalingReport(outputPath string, id string) (string, error) {
 	reportText := reportBuilder.String()
 	return reportText, nil
 }
-
-// Función auxiliar para limpiar strings eliminando caracteres nulos
-func cleanString(s string) string {
-	return strings.TrimRight(s, "\x00")
-}
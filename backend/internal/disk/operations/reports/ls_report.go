@@ -25,6 +25,8 @@ func LSReport(
 		return fmt.Errorf("error al obtener partición: %v", err)
 	}
 
+	defer memory.RLockDisk(partitionPath)()
+
 	// Separar ruta en directorios padres y nombre de archivo
 	parentDirs, fileName := utils.GetParentDirectories(path_file)
 
@@ -87,7 +89,7 @@ func LSReport(
 			if entry.BInodo == -1 {
 				continue
 			}
-			name := strings.TrimRight(string(entry.BName[:]), "\x00")
+			name := superBlock.EntryName(partitionPath, entry)
 			if name != "." && name != ".." {
 				entryInodeIndex := entry.BInodo
 				entryInode := &ext2.INode{}
@@ -3,9 +3,6 @@ package reports
 import (
 	"bytes"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,7 +11,7 @@ import (
 	"disk.simulator.com/m/v2/utils"
 )
 
-func MbrReport(outputPath string, id string) error {
+func MbrReport(outputPath string, id string, renderer Renderer) error {
 	_, diskPath, err := memory.GetInstance().GetMountedPartition(id)
 
 	fmt.Println("Generating MBR report at:", outputPath)
@@ -23,15 +20,14 @@ func MbrReport(outputPath string, id string) error {
 		return err
 	}
 
+	defer memory.RLockDisk(diskPath)()
+
 	// Crear las carpetas necesarias para el archivo de salida
 	err = utils.CreateParentDirs(outputPath)
 	if err != nil {
 		return err
 	}
 
-	// Generar el nombre para el archivo .dot basado en la ruta de salida
-	dotFileName := filepath.Join(filepath.Dir(outputPath), filepath.Base(outputPath)+".dot")
-
 	mbr := structures.MBR{}
 
 	err = mbr.DeserializeMBR(diskPath)
@@ -41,17 +37,13 @@ func MbrReport(outputPath string, id string) error {
 
 	fmt.Println("MBR size:", mbr.Mbr_size)
 
-	// Contenido del dot con estilos mejorados
-	dotContent := fmt.Sprintf(`digraph G {
-        bgcolor="#f7f7f7";
-        node [shape=plaintext fontname="Arial" fontsize=12];
-        tabla [label=<
-            <table border="0" cellborder="1" cellspacing="0" cellpadding="10" bgcolor="white" style="rounded">
-                <tr><td colspan="2" bgcolor="#4b6584" color="white"><b>REPORTE DE MBR</b></td></tr>
-                <tr><td bgcolor="#ecf0f1"><b>mbr_tamano</b></td><td>%d</td></tr>
-                <tr><td bgcolor="#ecf0f1"><b>mrb_fecha_creacion</b></td><td>%s</td></tr>
-                <tr><td bgcolor="#ecf0f1"><b>mbr_disk_signature</b></td><td>%d</td></tr>
-            `, mbr.Mbr_size, time.Unix(int64(mbr.Mbr_creation_date), 0), mbr.Mbr_disk_signature)
+	// Tabla del reporte con los datos del MBR
+	table := reportTable{Rows: []reportRow{
+		headerRow("REPORTE DE MBR", "#4b6584"),
+		fieldRow("mbr_tamano", fmt.Sprintf("%d", mbr.Mbr_size), "#ecf0f1"),
+		fieldRow("mrb_fecha_creacion", time.Unix(int64(mbr.Mbr_creation_date), 0).String(), "#ecf0f1"),
+		fieldRow("mbr_disk_signature", fmt.Sprintf("%d", mbr.Mbr_disk_signature), "#ecf0f1"),
+	}}
 
 	for i, partition := range mbr.Mbr_partitions {
 		// Convertimos los caracteres a string para evitar problemas de formato
@@ -70,15 +62,15 @@ func MbrReport(outputPath string, id string) error {
 			headerColor = "#e74c3c" // Rojo para extendidas
 		}
 
-		dotContent += fmt.Sprintf(`
-			<tr><td colspan="2" bgcolor="%s" color="white"><b>PARTICION %d</b></td></tr>
-			<tr><td bgcolor="#ecf0f1"><b>part_status</b></td><td>%s</td></tr>
-			<tr><td bgcolor="#ecf0f1"><b>part_type</b></td><td>%s</td></tr>
-			<tr><td bgcolor="#ecf0f1"><b>part_fit</b></td><td>%s</td></tr>
-			<tr><td bgcolor="#ecf0f1"><b>part_start</b></td><td>%d</td></tr>
-			<tr><td bgcolor="#ecf0f1"><b>part_size</b></td><td>%d</td></tr>
-			<tr><td bgcolor="#ecf0f1"><b>part_name</b></td><td>%s</td></tr>
-			`, headerColor, i, statusStr, typeStr, fitStr, partition.Part_start, partition.Part_size, nameStr)
+		table.Rows = append(table.Rows,
+			headerRow(fmt.Sprintf("PARTICION %d", i), headerColor),
+			fieldRow("part_status", statusStr, "#ecf0f1"),
+			fieldRow("part_type", typeStr, "#ecf0f1"),
+			fieldRow("part_fit", fitStr, "#ecf0f1"),
+			fieldRow("part_start", fmt.Sprintf("%d", partition.Part_start), "#ecf0f1"),
+			fieldRow("part_size", fmt.Sprintf("%d", partition.Part_size), "#ecf0f1"),
+			fieldRow("part_name", nameStr, "#ecf0f1"),
+		)
 
 		if partition.Part_type == 'E' {
 			currentEBRStart := partition.Part_start
@@ -96,14 +88,14 @@ func MbrReport(outputPath string, id string) error {
 				ebrFitStr := string(ebr.Part_fit)
 				ebrNameStr := strings.ReplaceAll(string(bytes.Trim(ebr.Part_name[:], "\x00")), "\x00", "")
 
-				dotContent += fmt.Sprintf(`
-					<tr><td colspan="2" bgcolor="#9b59b6" color="white"><b>Partición lógica %d</b></td></tr>
-					<tr><td bgcolor="#f0e6f6"><b>part_status</b></td><td>%s</td></tr>
-					<tr><td bgcolor="#f0e6f6"><b>part_fit</b></td><td>%s</td></tr>
-					<tr><td bgcolor="#f0e6f6"><b>part_start</b></td><td>%d</td></tr>
-					<tr><td bgcolor="#f0e6f6"><b>part_size</b></td><td>%d</td></tr>
-					<tr><td bgcolor="#f0e6f6"><b>part_name</b></td><td>%s</td></tr>
-					`, logicalPartitionNumber, ebrStatusStr, ebrFitStr, ebr.Part_start, ebr.Part_size, ebrNameStr)
+				table.Rows = append(table.Rows,
+					headerRow(fmt.Sprintf("Partición lógica %d", logicalPartitionNumber), "#9b59b6"),
+					fieldRow("part_status", ebrStatusStr, "#f0e6f6"),
+					fieldRow("part_fit", ebrFitStr, "#f0e6f6"),
+					fieldRow("part_start", fmt.Sprintf("%d", ebr.Part_start), "#f0e6f6"),
+					fieldRow("part_size", fmt.Sprintf("%d", ebr.Part_size), "#f0e6f6"),
+					fieldRow("part_name", ebrNameStr, "#f0e6f6"),
+				)
 
 				// Mover al siguiente EBR
 				currentEBRStart = ebr.Part_next
@@ -112,34 +104,10 @@ func MbrReport(outputPath string, id string) error {
 		}
 	}
 
-	// Cierre de la tabla con estilo
-	dotContent += `</table>> style="filled" fillcolor="white" color="#34495e" penwidth=2];
-	
-	// Añadimos un título
-    label = "Reporte de Master Boot Record (MBR)";
-    labelloc = "t";
-    fontname = "Arial Bold";
-    fontsize = 20;
-    fontcolor = "#2c3e50";
-	}`
-
-	// Crear el archivo .dot
-	file, err := os.Create(dotFileName)
-	if err != nil {
-		return fmt.Errorf("error al crear archivo .dot: %v", err)
-	}
-	defer file.Close()
-
-	_, err = file.WriteString(dotContent)
+	// Dibujar el reporte (Graphviz solo si se pidió explícitamente)
+	err = renderTables(outputPath, renderer, "Reporte de Master Boot Record (MBR)", []reportTable{table}, false)
 	if err != nil {
-		return fmt.Errorf("error al escribir en archivo .dot: %v", err)
-	}
-
-	// Crear la imagen PNG usando el archivo .dot
-	cmd := exec.Command("dot", "-Tpng", dotFileName, "-o", outputPath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("error al generar la imagen con dot: %v\nOutput: %s", err, string(output))
+		return err
 	}
 
 	fmt.Println("MBR report created successfully at:", outputPath)
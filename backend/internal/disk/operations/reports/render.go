@@ -0,0 +1,480 @@
+package reports
+
+import (
+	"fmt"
+	"html"
+	"image/jpeg"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+)
+
+// Renderer indica cómo se dibujan los reportes con forma de tabla
+type Renderer string
+
+const (
+	RendererNative Renderer = "native" // Dibujo en el proceso: SVG, o PNG/JPG con gg
+	RendererDot    Renderer = "dot"    // Archivo .dot convertido con el ejecutable de Graphviz
+)
+
+// ParseRenderer convierte el valor del parámetro -renderer; vacío equivale a native
+func ParseRenderer(name string) (Renderer, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", string(RendererNative):
+		return RendererNative, nil
+	case string(RendererDot):
+		return RendererDot, nil
+	}
+	return "", fmt.Errorf("renderer inválido '%s' (use native o dot)", name)
+}
+
+// reportCell es una celda de una tabla de reporte
+type reportCell struct {
+	Lines []string // Texto de la celda, una línea por elemento
+	Fill  string   // Color de fondo (#rrggbb)
+	Color string   // Color del texto; vacío equivale a negro
+	Bold  bool
+	Width float64 // Ancho relativo dentro de la fila; 0 reparte la fila en partes iguales
+}
+
+// reportRow es una fila de celdas que ocupa todo el ancho de la tabla
+type reportRow []reportCell
+
+// reportTable es una tabla de reporte con un encabezado en su primera fila
+type reportTable struct {
+	Rows []reportRow
+}
+
+// headerRow crea una fila de una sola celda con texto blanco en negrita
+func headerRow(text string, fill string) reportRow {
+	return reportRow{{Lines: []string{text}, Fill: fill, Color: "#ffffff", Bold: true}}
+}
+
+// fieldRow crea una fila con el nombre de un campo y su valor
+func fieldRow(label string, value string, labelFill string) reportRow {
+	return reportRow{
+		{Lines: []string{label}, Fill: labelFill, Bold: true},
+		{Lines: []string{value}, Fill: "#ffffff"},
+	}
+}
+
+// renderTables dibuja las tablas de un reporte en outputPath. Con chained las
+// tablas se unen con flechas de izquierda a derecha (por ejemplo, los inodos)
+func renderTables(outputPath string, renderer Renderer, title string, tables []reportTable, chained bool) error {
+	if renderer == RendererDot {
+		return renderDot(outputPath, tablesToDot(title, tables, chained))
+	}
+
+	layout := layoutTables(title, tables)
+
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".svg":
+		return os.WriteFile(outputPath, []byte(layout.svg(chained)), 0644)
+	case ".jpg", ".jpeg":
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("error al crear la imagen: %v", err)
+		}
+		defer file.Close()
+
+		return jpeg.Encode(file, layout.image(chained).Image(), &jpeg.Options{Quality: 95})
+	default:
+		// Igual que dot -Tpng, cualquier otra extensión se genera como PNG
+		return layout.image(chained).SavePNG(outputPath)
+	}
+}
+
+// renderDot escribe el archivo .dot junto a la salida y lo convierte con Graphviz
+func renderDot(outputPath string, dotContent string) error {
+	dotFileName := filepath.Join(filepath.Dir(outputPath), filepath.Base(outputPath)+".dot")
+
+	err := os.WriteFile(dotFileName, []byte(dotContent), 0644)
+	if err != nil {
+		return fmt.Errorf("error al escribir el archivo .dot: %v", err)
+	}
+
+	cmd := exec.Command("dot", "-Tpng", dotFileName, "-o", outputPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error al generar la imagen con dot: %v\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// tablesToDot genera el contenido .dot equivalente a las tablas del reporte
+func tablesToDot(title string, tables []reportTable, chained bool) string {
+	var dot strings.Builder
+
+	dot.WriteString("digraph G {\n")
+	dot.WriteString("    bgcolor=\"#f7f7f7\";\n")
+	dot.WriteString("    node [shape=plaintext fontname=\"Arial\" fontsize=12];\n")
+	dot.WriteString("    edge [color=\"#666666\" penwidth=1.5];\n")
+	if chained {
+		dot.WriteString("    rankdir=LR;\n")
+	}
+	dot.WriteString(fmt.Sprintf("    label=\"%s\";\n", html.EscapeString(title)))
+	dot.WriteString("    labelloc=\"t\";\n    fontname=\"Arial Bold\";\n    fontsize=20;\n    fontcolor=\"#2c3e50\";\n")
+
+	for i, table := range tables {
+		// Las filas de una sola celda ocupan todas las columnas de la tabla
+		columns := 1
+		for _, row := range table.Rows {
+			columns = max(columns, len(row))
+		}
+
+		dot.WriteString(fmt.Sprintf("    t%d [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"6\" bgcolor=\"white\">\n", i))
+		for _, row := range table.Rows {
+			dot.WriteString("        <tr>")
+			for _, cell := range row {
+				attrs := fmt.Sprintf(" bgcolor=\"%s\"", cell.Fill)
+				if len(row) == 1 && columns > 1 {
+					attrs += fmt.Sprintf(" colspan=\"%d\"", columns)
+				}
+				if cell.Width > 0 {
+					attrs += fmt.Sprintf(" width=\"%d\"", int(cell.Width))
+				}
+
+				lines := make([]string, len(cell.Lines))
+				for j, line := range cell.Lines {
+					lines[j] = html.EscapeString(line)
+				}
+				text := strings.Join(lines, "<br/>")
+				if cell.Bold {
+					text = "<b>" + text + "</b>"
+				}
+				if cell.Color != "" {
+					text = fmt.Sprintf("<font color=\"%s\">%s</font>", cell.Color, text)
+				}
+
+				dot.WriteString(fmt.Sprintf("<td%s>%s</td>", attrs, text))
+			}
+			dot.WriteString("</tr>\n")
+		}
+		dot.WriteString("    </table>>];\n")
+
+		if chained && i > 0 {
+			dot.WriteString(fmt.Sprintf("    t%d -> t%d [weight=2];\n", i-1, i))
+		}
+	}
+
+	dot.WriteString("}\n")
+	return dot.String()
+}
+
+// Medidas del renderizador nativo, en píxeles. El texto usa una fuente
+// monoespaciada, por lo que el ancho de una línea depende solo de sus caracteres
+const (
+	charWidth   = 7.5
+	lineHeight  = 16.0
+	cellPadding = 8.0
+	titleHeight = 48.0
+	pageMargin  = 24.0
+	tableGap    = 60.0
+	minRowWidth = 240.0
+	barRowWidth = 800.0 // Ancho mínimo de las filas con ancho relativo, para que se note la proporción
+)
+
+// cellBox es la posición calculada de una celda
+type cellBox struct {
+	x, y, w, h float64
+	cell       reportCell
+}
+
+// tableBox es la posición calculada de una tabla y sus celdas
+type tableBox struct {
+	x, y, w, h float64
+	cells      []cellBox
+}
+
+// reportLayout es la disposición completa de un reporte nativo
+type reportLayout struct {
+	title         string
+	width, height float64
+	tables        []tableBox
+}
+
+// textWidth devuelve el ancho natural de una celda según su línea más larga
+func textWidth(cell reportCell) float64 {
+	longest := 0
+	for _, line := range cell.Lines {
+		longest = max(longest, utf8.RuneCountInString(line))
+	}
+	return float64(longest)*charWidth + 2*cellPadding
+}
+
+// layoutTables calcula la posición de cada tabla (de izquierda a derecha) y de sus celdas
+func layoutTables(title string, tables []reportTable) reportLayout {
+	layout := reportLayout{title: title}
+	x := pageMargin
+	top := pageMargin + titleHeight
+
+	for _, table := range tables {
+		// El ancho de la tabla es el que necesita su fila más exigente
+		width := minRowWidth
+		for _, row := range table.Rows {
+			natural, widest := 0.0, 0.0
+			for _, cell := range row {
+				natural += textWidth(cell)
+				widest = max(widest, textWidth(cell))
+			}
+			if row.weighted() {
+				width = max(width, natural, barRowWidth)
+			} else {
+				width = max(width, widest*float64(len(row)))
+			}
+		}
+
+		box := tableBox{x: x, y: top, w: width}
+		y := top
+		for _, row := range table.Rows {
+			lines := 1
+			for _, cell := range row {
+				lines = max(lines, len(cell.Lines))
+			}
+			height := float64(lines)*lineHeight + 2*cellPadding
+
+			cx := x
+			for i, w := range row.widths(width) {
+				box.cells = append(box.cells, cellBox{x: cx, y: y, w: w, h: height, cell: row[i]})
+				cx += w
+			}
+			y += height
+		}
+		box.h = y - top
+
+		layout.tables = append(layout.tables, box)
+		layout.height = max(layout.height, y+pageMargin)
+		x += width + tableGap
+	}
+
+	layout.width = max(x-tableGap+pageMargin, float64(utf8.RuneCountInString(title))*charWidth*1.6+2*pageMargin)
+	layout.height = max(layout.height, top+pageMargin)
+	return layout
+}
+
+// weighted indica si la fila reparte su ancho según el ancho relativo de sus celdas
+func (row reportRow) weighted() bool {
+	for _, cell := range row {
+		if cell.Width > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// widths reparte el ancho de la tabla entre las celdas de la fila. En las filas
+// con ancho relativo cada celda conserva su ancho natural y el espacio sobrante
+// se reparte en proporción a su peso
+func (row reportRow) widths(total float64) []float64 {
+	widths := make([]float64, len(row))
+	if !row.weighted() {
+		for i := range row {
+			widths[i] = total / float64(len(row))
+		}
+		return widths
+	}
+
+	natural, weights := 0.0, 0.0
+	for i, cell := range row {
+		widths[i] = textWidth(cell)
+		natural += widths[i]
+		weights += max(cell.Width, 1)
+	}
+	for i, cell := range row {
+		widths[i] += (total - natural) * max(cell.Width, 1) / weights
+	}
+	return widths
+}
+
+// arrows devuelve los segmentos que unen cada tabla con la siguiente, a la altura del encabezado
+func (layout reportLayout) arrows() [][4]float64 {
+	var segments [][4]float64
+	for i := 1; i < len(layout.tables); i++ {
+		from, to := layout.tables[i-1], layout.tables[i]
+		y := from.y + lineHeight/2 + cellPadding
+		segments = append(segments, [4]float64{from.x + from.w, y, to.x, y})
+	}
+	return segments
+}
+
+// svg dibuja el reporte como un documento SVG
+func (layout reportLayout) svg(chained bool) string {
+	var svg strings.Builder
+
+	svg.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\" font-family=\"monospace\" font-size=\"12\">\n",
+		layout.width, layout.height, layout.width, layout.height))
+	svg.WriteString("<defs><marker id=\"arrow\" markerWidth=\"10\" markerHeight=\"8\" refX=\"10\" refY=\"4\" orient=\"auto\"><path d=\"M0,0 L10,4 L0,8 z\" fill=\"#666666\"/></marker></defs>\n")
+	svg.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"#f7f7f7\"/>\n")
+	svg.WriteString(fmt.Sprintf("<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" font-family=\"Arial, sans-serif\" font-size=\"20\" font-weight=\"bold\" fill=\"#2c3e50\">%s</text>\n",
+		layout.width/2, pageMargin+titleHeight/2, html.EscapeString(layout.title)))
+
+	for _, table := range layout.tables {
+		svg.WriteString(fmt.Sprintf("<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"#ffffff\" stroke=\"#34495e\" stroke-width=\"2\"/>\n",
+			table.x, table.y, table.w, table.h))
+
+		for _, box := range table.cells {
+			svg.WriteString(fmt.Sprintf("<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\" stroke=\"#95a5a6\"/>\n",
+				box.x, box.y, box.w, box.h, box.cell.Fill))
+
+			weight := ""
+			if box.cell.Bold {
+				weight = " font-weight=\"bold\""
+			}
+			color := box.cell.Color
+			if color == "" {
+				color = "#000000"
+			}
+
+			for i, line := range box.cell.Lines {
+				svg.WriteString(fmt.Sprintf("<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\"%s>%s</text>\n",
+					box.x+box.w/2, box.lineY(i), color, weight, html.EscapeString(line)))
+			}
+		}
+	}
+
+	if chained {
+		for _, s := range layout.arrows() {
+			svg.WriteString(fmt.Sprintf("<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#666666\" stroke-width=\"1.5\" marker-end=\"url(#arrow)\"/>\n",
+				s[0], s[1], s[2], s[3]))
+		}
+	}
+
+	svg.WriteString("</svg>\n")
+	return svg.String()
+}
+
+// fontFace carga una de las fuentes Go incluidas en golang.org/x/image, que cubren
+// los acentos del español sin depender de las fuentes instaladas en el sistema
+func fontFace(ttf []byte, size float64) font.Face {
+	parsed, err := truetype.Parse(ttf)
+	if err != nil {
+		panic(fmt.Sprintf("fuente embebida inválida: %v", err))
+	}
+	return truetype.NewFace(parsed, &truetype.Options{Size: size})
+}
+
+// Caras de texto del renderizador nativo; Go Mono de 12 puntos mide 7.2 píxeles por carácter
+var (
+	titleFace    = fontFace(gobold.TTF, 20)
+	textFace     = fontFace(gomono.TTF, 12)
+	boldTextFace = fontFace(gomonobold.TTF, 12)
+)
+
+// image dibuja el reporte en un contexto de gg para exportarlo como PNG o JPG
+func (layout reportLayout) image(chained bool) *gg.Context {
+	dc := gg.NewContext(int(layout.width), int(layout.height))
+
+	dc.SetHexColor("#f7f7f7")
+	dc.Clear()
+
+	dc.SetFontFace(titleFace)
+	dc.SetHexColor("#2c3e50")
+	dc.DrawStringAnchored(layout.title, layout.width/2, pageMargin+titleHeight/2, 0.5, 0.5)
+
+	for _, table := range layout.tables {
+		dc.DrawRectangle(table.x, table.y, table.w, table.h)
+		dc.SetHexColor("#ffffff")
+		dc.Fill()
+
+		for _, box := range table.cells {
+			dc.DrawRectangle(box.x, box.y, box.w, box.h)
+			dc.SetHexColor(box.cell.Fill)
+			dc.FillPreserve()
+			dc.SetHexColor("#95a5a6")
+			dc.SetLineWidth(1)
+			dc.Stroke()
+
+			color := box.cell.Color
+			if color == "" {
+				color = "#000000"
+			}
+			dc.SetFontFace(textFace)
+			if box.cell.Bold {
+				dc.SetFontFace(boldTextFace)
+			}
+			dc.SetHexColor(color)
+			for i, line := range box.cell.Lines {
+				dc.DrawStringAnchored(line, box.x+box.w/2, box.lineY(i), 0.5, 0.5)
+			}
+		}
+
+		dc.DrawRectangle(table.x, table.y, table.w, table.h)
+		dc.SetHexColor("#34495e")
+		dc.SetLineWidth(2)
+		dc.Stroke()
+	}
+
+	if chained {
+		dc.SetHexColor("#666666")
+		dc.SetLineWidth(1.5)
+		for _, s := range layout.arrows() {
+			dc.DrawLine(s[0], s[1], s[2], s[3])
+			dc.Stroke()
+			dc.MoveTo(s[2], s[3])
+			dc.LineTo(s[2]-10, s[3]-4)
+			dc.LineTo(s[2]-10, s[3]+4)
+			dc.ClosePath()
+			dc.Fill()
+		}
+	}
+
+	return dc
+}
+
+// lineY devuelve el centro vertical de la línea i de la celda, con el texto centrado
+func (box cellBox) lineY(i int) float64 {
+	textHeight := float64(len(box.cell.Lines)) * lineHeight
+	return box.y + (box.h-textHeight)/2 + float64(i)*lineHeight + lineHeight/2
+}
+
+// isImageOutput indica si la ruta de salida pide una imagen en lugar de texto
+func isImageOutput(outputPath string) bool {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".png", ".jpg", ".jpeg", ".svg":
+		return true
+	}
+	return false
+}
+
+// bitmapTable convierte el texto de un bitmap (una fila por línea) en una tabla
+// con una celda por posición, resaltando las que están en uso
+func bitmapTable(title string, content string) reportTable {
+	table := reportTable{Rows: []reportRow{headerRow(title, "#4b6584")}}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	columns := 0
+	for _, line := range lines {
+		columns = max(columns, len(line))
+	}
+
+	for _, line := range lines {
+		row := make(reportRow, columns)
+		for i := range row {
+			if i >= len(line) {
+				row[i] = reportCell{Fill: "#ffffff"}
+				continue
+			}
+
+			// El bitmap puede usar 0/1 binarios, '0'/'1' u 'O'/'X'
+			b := line[i]
+			if b != 0 && b != '0' && b != 'O' {
+				row[i] = reportCell{Lines: []string{"1"}, Fill: "#2ecc71", Color: "#ffffff", Bold: true}
+			} else {
+				row[i] = reportCell{Lines: []string{"0"}, Fill: "#ecf0f1"}
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
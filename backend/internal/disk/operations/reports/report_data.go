@@ -0,0 +1,628 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/types/structures"
+	"disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"disk.simulator.com/m/v2/utils"
+)
+
+// ErrUnknownReport se devuelve cuando el nombre del reporte no existe
+var ErrUnknownReport = errors.New("reporte desconocido")
+
+// ReportNames son los reportes que se pueden generar con rep
+var ReportNames = []string{"mbr", "disk", "inode", "block", "bm_inode", "bm_block", "sb", "file", "ls", "tree", "journaling"}
+
+// MbrData son los datos del reporte mbr
+type MbrData struct {
+	Size          int32              `json:"size"`
+	CreationDate  time.Time          `json:"creationDate"`
+	DiskSignature int32              `json:"diskSignature"`
+	Partitions    []MbrPartitionData `json:"partitions"`
+}
+
+// MbrPartitionData es una entrada de la tabla de particiones del MBR
+type MbrPartitionData struct {
+	Index   int              `json:"index"`
+	Status  string           `json:"status"`
+	Type    string           `json:"type"`
+	Fit     string           `json:"fit"`
+	Start   int32            `json:"start"`
+	Size    int32            `json:"size"`
+	Name    string           `json:"name"`
+	Logical []LogicalEbrData `json:"logical,omitempty"`
+}
+
+// LogicalEbrData es un EBR de la cadena de una partición extendida
+type LogicalEbrData struct {
+	Status string `json:"status"`
+	Fit    string `json:"fit"`
+	Start  int32  `json:"start"`
+	Size   int32  `json:"size"`
+	Next   int32  `json:"next"`
+	Name   string `json:"name"`
+}
+
+// DiskData son los datos del reporte disk
+type DiskData struct {
+	Disk     string          `json:"disk"`
+	Size     int32           `json:"size"`
+	Segments []DiskPartition `json:"segments"`
+}
+
+// SuperBlockData son los datos del reporte sb
+type SuperBlockData struct {
+	FilesystemType   int32     `json:"filesystemType"`
+	InodesCount      int32     `json:"inodesCount"`
+	BlocksCount      int32     `json:"blocksCount"`
+	FreeInodesCount  int32     `json:"freeInodesCount"`
+	FreeBlocksCount  int32     `json:"freeBlocksCount"`
+	MountTime        time.Time `json:"mountTime"`
+	UnmountTime      time.Time `json:"unmountTime"`
+	MountCount       int32     `json:"mountCount"`
+	Magic            int32     `json:"magic"`
+	InodeSize        int32     `json:"inodeSize"`
+	BlockSize        int32     `json:"blockSize"`
+	FirstInode       int32     `json:"firstInode"`
+	FirstBlock       int32     `json:"firstBlock"`
+	BitmapInodeStart int32     `json:"bitmapInodeStart"`
+	BitmapBlockStart int32     `json:"bitmapBlockStart"`
+	InodeStart       int32     `json:"inodeStart"`
+	BlockStart       int32     `json:"blockStart"`
+	JournalHead      int32     `json:"journalHead"`
+	JournalTail      int32     `json:"journalTail"`
+	JournalPolicy    string    `json:"journalPolicy"`
+	JournalSize      int32     `json:"journalSize"`
+}
+
+// InodeData son los datos de un inodo en uso
+type InodeData struct {
+	Index    int32     `json:"index"`
+	Type     string    `json:"type"` // "directory" o "file"
+	UID      int32     `json:"uid"`
+	GID      int32     `json:"gid"`
+	Size     int32     `json:"size"`
+	Perm     string    `json:"perm"`
+	Atime    time.Time `json:"atime"`
+	Ctime    time.Time `json:"ctime"`
+	Mtime    time.Time `json:"mtime"`
+	Direct   []int32   `json:"direct"` // Los 12 apuntadores directos; -1 si no se usan
+	Indirect int32     `json:"indirect"`
+	Double   int32     `json:"double"`
+	Triple   int32     `json:"triple"`
+}
+
+// BitmapData son los datos de los reportes bm_inode y bm_block
+type BitmapData struct {
+	Total int32  `json:"total"`
+	Used  int32  `json:"used"`
+	Free  int32  `json:"free"`
+	Bits  string `json:"bits"` // Un carácter por posición: '1' en uso, '0' libre
+}
+
+// BlockData son los datos de un bloque referenciado por un inodo
+type BlockData struct {
+	Index    int32          `json:"index"`
+	Owner    int32          `json:"owner"`
+	Kind     string         `json:"kind"` // "directory", "file" o "pointer"
+	Entries  []DirEntryData `json:"entries,omitempty"`
+	Content  string         `json:"content,omitempty"`
+	Pointers []int32        `json:"pointers,omitempty"`
+}
+
+// DirEntryData es una entrada de un bloque de directorio
+type DirEntryData struct {
+	Name  string `json:"name"`
+	Inode int32  `json:"inode"`
+}
+
+// FileData son los datos del reporte file
+type FileData struct {
+	Path    string `json:"path"`
+	Size    int    `json:"size"`
+	Content string `json:"content"`
+}
+
+// LsData son los datos del reporte ls
+type LsData struct {
+	Path    string        `json:"path"`
+	Entries []LsEntryData `json:"entries"`
+}
+
+// LsEntryData es un archivo o carpeta listado por el reporte ls
+type LsEntryData struct {
+	Name    string    `json:"name"`
+	Type    string    `json:"type"` // "directory" o "file"
+	Perm    string    `json:"perm"`
+	UID     int32     `json:"uid"`
+	Inode   int32     `json:"inode"`
+	Size    int32     `json:"size"`
+	Created time.Time `json:"created"`
+}
+
+// TreeNode es un inodo del reporte tree con sus bloques y sus hijos
+type TreeNode struct {
+	Name     string     `json:"name"`
+	Inode    int32      `json:"inode"`
+	Type     string     `json:"type"` // "directory" o "file"
+	Size     int32      `json:"size"`
+	Perm     string     `json:"perm"`
+	Blocks   []int32    `json:"blocks"`
+	Children []TreeNode `json:"children,omitempty"`
+}
+
+// JournalingData son los datos del reporte journaling
+type JournalingData struct {
+	Head     int32               `json:"head"`
+	Tail     int32               `json:"tail"`
+	Capacity int32               `json:"capacity"`
+	Used     int32               `json:"used"`
+	Policy   string              `json:"policy"`
+	Entries  []ext2.JournalEntry `json:"entries"`
+}
+
+// ReportData devuelve los datos estructurados del reporte name para la partición
+// id, listos para serializar como JSON. pathFile es la ruta dentro de la
+// partición que usan los reportes file y ls
+func ReportData(name string, id string, pathFile string) (interface{}, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !slices.Contains(ReportNames, name) {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownReport, name)
+	}
+
+	partition, diskPath, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer memory.RLockDisk(diskPath)()
+
+	// Los reportes del disco no necesitan un sistema de archivos
+	switch name {
+	case "mbr":
+		return mbrData(diskPath)
+	case "disk":
+		return diskData(diskPath)
+	}
+
+	superBlock := &ext2.SuperBlock{}
+	err = superBlock.DeserializeSuperBlock(diskPath, partition.Partition.Part_start)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	switch name {
+	case "sb":
+		return superBlockData(superBlock), nil
+	case "inode":
+		return inodeData(superBlock, diskPath)
+	case "bm_inode":
+		return bitmapData(diskPath, superBlock.SBmInodeStart, superBlock.SInodesCount+superBlock.SFreeInodesCount)
+	case "bm_block":
+		return bitmapData(diskPath, superBlock.SBmBlockStart, superBlock.SBlocksCount+superBlock.SFreeBlocksCount)
+	case "block":
+		return blockData(superBlock, diskPath)
+	case "file":
+		return fileData(superBlock, diskPath, pathFile)
+	case "ls":
+		return lsData(superBlock, diskPath, pathFile)
+	case "tree":
+		root, err := treeData(superBlock, diskPath, 0, "/", map[int32]bool{})
+		if err != nil {
+			return nil, err
+		}
+		return root, nil
+	case "journaling":
+		return journalingData(superBlock, diskPath, partition.Partition.Part_start)
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrUnknownReport, name)
+}
+
+// WriteReportJSON escribe los datos de un reporte como JSON indentado en outputPath
+func WriteReportJSON(outputPath string, data interface{}) error {
+	err := utils.CreateParentDirs(outputPath)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al serializar el reporte: %v", err)
+	}
+
+	return os.WriteFile(outputPath, append(content, '\n'), 0644)
+}
+
+func mbrData(diskPath string) (*MbrData, error) {
+	mbr := structures.MBR{}
+	err := mbr.DeserializeMBR(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &MbrData{
+		Size:          mbr.Mbr_size,
+		CreationDate:  time.Unix(int64(mbr.Mbr_creation_date), 0),
+		DiskSignature: mbr.Mbr_disk_signature,
+		Partitions:    []MbrPartitionData{},
+	}
+
+	for i, partition := range mbr.Mbr_partitions {
+		entry := MbrPartitionData{
+			Index:  i,
+			Status: string(partition.Part_status),
+			Type:   string(partition.Part_type),
+			Fit:    string(partition.Part_fit),
+			Start:  partition.Part_start,
+			Size:   partition.Part_size,
+			Name:   cleanName(string(partition.Part_name[:])),
+		}
+
+		// Recorrer la cadena de EBR de la partición extendida
+		if partition.Part_type == 'E' {
+			for currentEBRStart := partition.Part_start; currentEBRStart != -1; {
+				ebr := structures.EBR{}
+				err := ebr.DeserializeEBR(diskPath, currentEBRStart)
+				if err != nil {
+					return nil, err
+				}
+
+				entry.Logical = append(entry.Logical, LogicalEbrData{
+					Status: string(ebr.Part_mount),
+					Fit:    string(ebr.Part_fit),
+					Start:  ebr.Part_start,
+					Size:   ebr.Part_size,
+					Next:   ebr.Part_next,
+					Name:   cleanName(string(ebr.Part_name[:])),
+				})
+				currentEBRStart = ebr.Part_next
+			}
+		}
+
+		data.Partitions = append(data.Partitions, entry)
+	}
+
+	return data, nil
+}
+
+func diskData(diskPath string) (*DiskData, error) {
+	mbr := structures.MBR{}
+	err := mbr.DeserializeMBR(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := diskPartitions(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskData{Disk: filepath.Base(diskPath), Size: mbr.Mbr_size, Segments: segments}, nil
+}
+
+func superBlockData(sb *ext2.SuperBlock) *SuperBlockData {
+	return &SuperBlockData{
+		FilesystemType:   sb.SFilesystemType,
+		InodesCount:      sb.SInodesCount,
+		BlocksCount:      sb.SBlocksCount,
+		FreeInodesCount:  sb.SFreeInodesCount,
+		FreeBlocksCount:  sb.SFreeBlocksCount,
+		MountTime:        time.Unix(int64(sb.SMtime), 0),
+		UnmountTime:      time.Unix(int64(sb.SUmTime), 0),
+		MountCount:       sb.SMntCount,
+		Magic:            sb.SMagic,
+		InodeSize:        sb.SInodeS,
+		BlockSize:        sb.SBlockS,
+		FirstInode:       sb.SFirstIno,
+		FirstBlock:       sb.SFirstBlo,
+		BitmapInodeStart: sb.SBmInodeStart,
+		BitmapBlockStart: sb.SBmBlockStart,
+		InodeStart:       sb.SInodeStart,
+		BlockStart:       sb.SBlockStart,
+		JournalHead:      sb.SJournalHead,
+		JournalTail:      sb.SJournalTail,
+		JournalPolicy:    ext2.JournalPolicyName(sb.SJournalPolicy),
+		JournalSize:      sb.SJournalSize,
+	}
+}
+
+// inodeJSON convierte un inodo al formato del reporte
+func inodeJSON(index int32, inode *ext2.INode) InodeData {
+	return InodeData{
+		Index:    index,
+		Type:     entryType(inode),
+		UID:      inode.IUid,
+		GID:      inode.IGid,
+		Size:     inode.ISize,
+		Perm:     string(inode.IPerm[:]),
+		Atime:    time.Unix(int64(inode.IAtime), 0),
+		Ctime:    time.Unix(int64(inode.ICtime), 0),
+		Mtime:    time.Unix(int64(inode.IMtime), 0),
+		Direct:   append([]int32{}, inode.IBlock[:12]...),
+		Indirect: inode.IBlock[12],
+		Double:   inode.IBlock[13],
+		Triple:   inode.IBlock[14],
+	}
+}
+
+// entryType devuelve "directory" o "file" según el tipo del inodo
+func entryType(inode *ext2.INode) string {
+	if inode.IType[0] == '0' {
+		return "directory"
+	}
+	return "file"
+}
+
+func inodeData(sb *ext2.SuperBlock, diskPath string) ([]InodeData, error) {
+	inodes := []InodeData{}
+	for i := int32(0); i < sb.SInodesCount; i++ {
+		inode := &ext2.INode{}
+		err := inode.Deserialize(diskPath, int64(sb.SInodeStart+(i*sb.SInodeS)))
+		if err != nil {
+			return nil, err
+		}
+		inodes = append(inodes, inodeJSON(i, inode))
+	}
+	return inodes, nil
+}
+
+func bitmapData(diskPath string, start int32, total int32) (*BitmapData, error) {
+	bitmap := make([]byte, max(total, 0))
+
+	file, err := os.Open(diskPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	_, err = file.ReadAt(bitmap, int64(start))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el bitmap: %v", err)
+	}
+
+	// El bitmap puede usar 0/1 binarios, '0'/'1' u 'O'/'X'
+	data := &BitmapData{Total: total}
+	bits := make([]byte, len(bitmap))
+	for i, b := range bitmap {
+		if b != 0 && b != '0' && b != 'O' {
+			bits[i] = '1'
+			data.Used++
+		} else {
+			bits[i] = '0'
+		}
+	}
+	data.Free = total - data.Used
+	data.Bits = string(bits)
+
+	return data, nil
+}
+
+func blockData(sb *ext2.SuperBlock, diskPath string) ([]BlockData, error) {
+	blocks := []BlockData{}
+	for i := int32(0); i < sb.SInodesCount; i++ {
+		inode := &ext2.INode{}
+		err := inode.Deserialize(diskPath, int64(sb.SInodeStart+(i*sb.SInodeS)))
+		if err != nil {
+			continue
+		}
+
+		for j, blockIndex := range inode.IBlock {
+			if blockIndex == -1 {
+				continue
+			}
+
+			block := BlockData{Index: blockIndex, Owner: i}
+			offset := int64(sb.SBlockStart + (blockIndex * sb.SBlockS))
+
+			switch {
+			case j >= 12: // Apuntadores indirectos del inodo
+				pointerBlock := &ext2.PointerBlock{}
+				if pointerBlock.Deserialize(diskPath, offset) != nil {
+					continue
+				}
+				block.Kind = "pointer"
+				for _, pointer := range pointerBlock.PContent {
+					if pointer != -1 {
+						block.Pointers = append(block.Pointers, pointer)
+					}
+				}
+
+			case inode.IType[0] == '0':
+				dirBlock := &ext2.DirBlock{}
+				if dirBlock.Deserialize(diskPath, offset) != nil {
+					continue
+				}
+				block.Kind = "directory"
+				for _, entry := range dirBlock.BContent {
+					if entry.BInodo != -1 {
+						block.Entries = append(block.Entries, DirEntryData{Name: sb.EntryName(diskPath, entry), Inode: entry.BInodo})
+					}
+				}
+
+			default:
+				fileBlock := &ext2.FileBlock{}
+				if fileBlock.Deserialize(diskPath, offset) != nil {
+					continue
+				}
+				block.Kind = "file"
+				block.Content = string(bytes.TrimRight(fileBlock.BContent[:], "\x00"))
+			}
+
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func fileData(sb *ext2.SuperBlock, diskPath string, pathFile string) (*FileData, error) {
+	if pathFile == "" {
+		return nil, fmt.Errorf("el reporte file requiere la ruta del archivo (path_file_ls)")
+	}
+
+	parentDirs, fileName := utils.GetParentDirectories(pathFile)
+	content, err := sb.FileContent(diskPath, parentDirs, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo archivo: %v", err)
+	}
+
+	return &FileData{Path: pathFile, Size: len(content), Content: content}, nil
+}
+
+func lsData(sb *ext2.SuperBlock, diskPath string, pathFile string) (*LsData, error) {
+	if pathFile == "" {
+		pathFile = "/"
+	}
+
+	// La raíz es el inodo 0; cualquier otra carpeta se busca por su ruta
+	dirInodeIndex := int32(0)
+	if strings.Trim(pathFile, "/") != "" {
+		parentDirs, dirName := utils.GetParentDirectories(pathFile)
+		index, err := sb.FindFileInode(diskPath, parentDirs, dirName)
+		if err != nil {
+			return nil, fmt.Errorf("error al encontrar inodo de '%s': %v", pathFile, err)
+		}
+		dirInodeIndex = index
+	}
+
+	dirInode := &ext2.INode{}
+	err := dirInode.Deserialize(diskPath, int64(sb.SInodeStart+(dirInodeIndex*sb.SInodeS)))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer inodo: %v", err)
+	}
+	if dirInode.IType[0] != '0' {
+		return nil, fmt.Errorf("'%s' no es una carpeta", pathFile)
+	}
+
+	data := &LsData{Path: pathFile, Entries: []LsEntryData{}}
+	for _, blockIndex := range dirInode.IBlock[:12] {
+		if blockIndex == -1 {
+			break
+		}
+
+		dirBlock := &ext2.DirBlock{}
+		err := dirBlock.Deserialize(diskPath, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer bloque %d: %v", blockIndex, err)
+		}
+
+		for _, entry := range dirBlock.BContent {
+			if entry.BInodo == -1 {
+				continue
+			}
+			name := sb.EntryName(diskPath, entry)
+			if name == "." || name == ".." {
+				continue
+			}
+
+			entryInode := &ext2.INode{}
+			err := entryInode.Deserialize(diskPath, int64(sb.SInodeStart+(entry.BInodo*sb.SInodeS)))
+			if err != nil {
+				return nil, fmt.Errorf("error al leer inodo del entry %d: %v", entry.BInodo, err)
+			}
+
+			data.Entries = append(data.Entries, LsEntryData{
+				Name:    name,
+				Type:    entryType(entryInode),
+				Perm:    string(entryInode.IPerm[:]),
+				UID:     entryInode.IUid,
+				Inode:   entry.BInodo,
+				Size:    entryInode.ISize,
+				Created: time.Unix(int64(entryInode.ICtime), 0),
+			})
+		}
+	}
+
+	return data, nil
+}
+
+// treeData arma el árbol de inodos a partir de inodeIndex. Igual que el reporte
+// tree, los hijos de una carpeta se leen de sus bloques directos
+func treeData(sb *ext2.SuperBlock, diskPath string, inodeIndex int32, name string, visited map[int32]bool) (TreeNode, error) {
+	if inodeIndex < 0 || inodeIndex >= sb.SInodesCount {
+		return TreeNode{}, fmt.Errorf("número de inodo %d fuera de rango (0-%d)", inodeIndex, sb.SInodesCount-1)
+	}
+	visited[inodeIndex] = true
+
+	inode, err := sb.GetInodeByNumber(diskPath, inodeIndex)
+	if err != nil {
+		return TreeNode{}, fmt.Errorf("error al obtener inodo %d: %v", inodeIndex, err)
+	}
+
+	node := TreeNode{
+		Name:   name,
+		Inode:  inodeIndex,
+		Type:   entryType(inode),
+		Size:   inode.ISize,
+		Perm:   string(inode.IPerm[:]),
+		Blocks: []int32{},
+	}
+	for _, blockIndex := range inode.IBlock {
+		if blockIndex != -1 {
+			node.Blocks = append(node.Blocks, blockIndex)
+		}
+	}
+
+	if inode.IType[0] != '0' {
+		return node, nil
+	}
+
+	for _, blockIndex := range inode.IBlock[:12] {
+		if blockIndex == -1 {
+			continue
+		}
+
+		dirBlock := &ext2.DirBlock{}
+		if dirBlock.Deserialize(diskPath, int64(sb.SBlockStart+(blockIndex*sb.SBlockS))) != nil {
+			continue
+		}
+
+		for _, entry := range dirBlock.BContent {
+			childName := sb.EntryName(diskPath, entry)
+			if entry.BInodo == -1 || childName == "." || childName == ".." || visited[entry.BInodo] {
+				continue
+			}
+
+			child, err := treeData(sb, diskPath, entry.BInodo, childName, visited)
+			if err != nil {
+				continue
+			}
+			node.Children = append(node.Children, child)
+		}
+	}
+
+	return node, nil
+}
+
+func journalingData(sb *ext2.SuperBlock, diskPath string, partitionStart int32) (*JournalingData, error) {
+	if !sb.HasJournal() {
+		return nil, fmt.Errorf("la partición no tiene journaling (no es ext3)")
+	}
+
+	entries, err := ext2.GetJournaling(diskPath, partitionStart)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []ext2.JournalEntry{}
+	}
+
+	return &JournalingData{
+		Head:     sb.SJournalHead,
+		Tail:     sb.SJournalTail,
+		Capacity: sb.JournalCapacity(partitionStart),
+		Used:     sb.JournalUsed(),
+		Policy:   ext2.JournalPolicyName(sb.SJournalPolicy),
+		Entries:  entries,
+	}, nil
+}
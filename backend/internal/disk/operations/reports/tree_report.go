@@ -62,7 +62,7 @@ func generateInodeNodeDOT(inode *ext2.INode, inodeIndex int32, name string) stri
 }
 
 // generateBlockNodeDOT crea el código DOT para representar cualquier tipo de bloque
-func generateBlockNodeDOT(block interface{}, blockIndex int32) string {
+func generateBlockNodeDOT(superBlock *ext2.SuperBlock, path string, block interface{}, blockIndex int32) string {
 	switch typedBlock := block.(type) {
 	case *ext2.DirBlock:
 		// Bloque de directorio (naranja)
@@ -74,7 +74,7 @@ func generateBlockNodeDOT(block interface{}, blockIndex int32) string {
 		// Mostrar las entradas del directorio
 		for _, content := range typedBlock.BContent {
 			if content.BInodo != -1 {
-				name := strings.Trim(string(content.BName[:]), "\x00")
+				name := superBlock.EntryName(path, content)
 				if name == "" {
 					name = "-"
 				}
@@ -176,7 +176,7 @@ func processInode(
 			dirBlock := &ext2.DirBlock{}
 			err := dirBlock.Deserialize(path, int64(superBlock.SBlockStart+(block*superBlock.SBlockS)))
 			if err == nil {
-				blockDef = generateBlockNodeDOT(dirBlock, block)
+				blockDef = generateBlockNodeDOT(superBlock, path, dirBlock, block)
 				*nodeDefinitions = append(*nodeDefinitions, blockDef)
 
 				// Procesar entradas de directorio
@@ -188,7 +188,7 @@ func processInode(
 							continue
 						}
 
-						childName := strings.Trim(string(content.BName[:]), "\x00")
+						childName := superBlock.EntryName(path, content)
 						if childName == "" {
 							childName = "-"
 						}
@@ -211,7 +211,7 @@ func processInode(
 			fileBlock := &ext2.FileBlock{}
 			err := fileBlock.Deserialize(path, int64(superBlock.SBlockStart+(block*superBlock.SBlockS)))
 			if err == nil {
-				blockDef = generateBlockNodeDOT(fileBlock, block)
+				blockDef = generateBlockNodeDOT(superBlock, path, fileBlock, block)
 				*nodeDefinitions = append(*nodeDefinitions, blockDef)
 			}
 		}
@@ -227,7 +227,7 @@ func processInode(
 			*nodeConnections = append(*nodeConnections, fmt.Sprintf("inode%d -> block%d [label=\"Simple\"];", inodeIndex, blockIndex))
 
 			// Generar y agregar la definición del bloque de punteros
-			blockDef := generateBlockNodeDOT(pointerBlock, blockIndex)
+			blockDef := generateBlockNodeDOT(superBlock, path, pointerBlock, blockIndex)
 			*nodeDefinitions = append(*nodeDefinitions, blockDef)
 
 			// Procesar cada puntero válido
@@ -241,7 +241,7 @@ func processInode(
 						fileBlock := &ext2.FileBlock{}
 						err := fileBlock.Deserialize(path, int64(superBlock.SBlockStart+(ptr*superBlock.SBlockS)))
 						if err == nil {
-							dataBlockDef := generateBlockNodeDOT(fileBlock, ptr)
+							dataBlockDef := generateBlockNodeDOT(superBlock, path, fileBlock, ptr)
 							*nodeDefinitions = append(*nodeDefinitions, dataBlockDef)
 						}
 					}
@@ -260,7 +260,7 @@ func processInode(
 			*nodeConnections = append(*nodeConnections, fmt.Sprintf("inode%d -> block%d [label=\"Doble\"];", inodeIndex, doubleBlockIndex))
 
 			// Generar y agregar la definición del bloque de punteros dobles
-			blockDef := generateBlockNodeDOT(doublePointerBlock, doubleBlockIndex)
+			blockDef := generateBlockNodeDOT(superBlock, path, doublePointerBlock, doubleBlockIndex)
 			*nodeDefinitions = append(*nodeDefinitions, blockDef)
 
 			// Procesar cada puntero válido en el bloque doble
@@ -273,7 +273,7 @@ func processInode(
 					simplePointerBlock := &ext2.PointerBlock{}
 					err := simplePointerBlock.Deserialize(path, int64(superBlock.SBlockStart+(ptr*superBlock.SBlockS)))
 					if err == nil {
-						simpleBlockDef := generateBlockNodeDOT(simplePointerBlock, ptr)
+						simpleBlockDef := generateBlockNodeDOT(superBlock, path, simplePointerBlock, ptr)
 						*nodeDefinitions = append(*nodeDefinitions, simpleBlockDef)
 
 						// Procesar cada puntero válido en el bloque simple
@@ -287,7 +287,7 @@ func processInode(
 									fileBlock := &ext2.FileBlock{}
 									err := fileBlock.Deserialize(path, int64(superBlock.SBlockStart+(dataPtr*superBlock.SBlockS)))
 									if err == nil {
-										dataBlockDef := generateBlockNodeDOT(fileBlock, dataPtr)
+										dataBlockDef := generateBlockNodeDOT(superBlock, path, fileBlock, dataPtr)
 										*nodeDefinitions = append(*nodeDefinitions, dataBlockDef)
 									}
 								}
@@ -309,7 +309,7 @@ func processInode(
 			*nodeConnections = append(*nodeConnections, fmt.Sprintf("inode%d -> block%d [label=\"Triple\"];", inodeIndex, tripleBlockIndex))
 
 			// Generar y agregar la definición del bloque de punteros triples
-			blockDef := generateBlockNodeDOT(triplePointerBlock, tripleBlockIndex)
+			blockDef := generateBlockNodeDOT(superBlock, path, triplePointerBlock, tripleBlockIndex)
 			*nodeDefinitions = append(*nodeDefinitions, blockDef)
 
 			// Procesar cada puntero válido en el bloque triple
@@ -322,7 +322,7 @@ func processInode(
 					doublePointerBlock := &ext2.PointerBlock{}
 					err := doublePointerBlock.Deserialize(path, int64(superBlock.SBlockStart+(ptr*superBlock.SBlockS)))
 					if err == nil {
-						doubleBlockDef := generateBlockNodeDOT(doublePointerBlock, ptr)
+						doubleBlockDef := generateBlockNodeDOT(superBlock, path, doublePointerBlock, ptr)
 						*nodeDefinitions = append(*nodeDefinitions, doubleBlockDef)
 
 						// Procesar cada puntero válido en el bloque doble
@@ -335,7 +335,7 @@ func processInode(
 								simplePointerBlock := &ext2.PointerBlock{}
 								err := simplePointerBlock.Deserialize(path, int64(superBlock.SBlockStart+(simplePtr*superBlock.SBlockS)))
 								if err == nil {
-									simpleBlockDef := generateBlockNodeDOT(simplePointerBlock, simplePtr)
+									simpleBlockDef := generateBlockNodeDOT(superBlock, path, simplePointerBlock, simplePtr)
 									*nodeDefinitions = append(*nodeDefinitions, simpleBlockDef)
 
 									// Procesar cada puntero válido en el bloque simple
@@ -349,7 +349,7 @@ func processInode(
 												fileBlock := &ext2.FileBlock{}
 												err := fileBlock.Deserialize(path, int64(superBlock.SBlockStart+(dataPtr*superBlock.SBlockS)))
 												if err == nil {
-													dataBlockDef := generateBlockNodeDOT(fileBlock, dataPtr)
+													dataBlockDef := generateBlockNodeDOT(superBlock, path, fileBlock, dataPtr)
 													*nodeDefinitions = append(*nodeDefinitions, dataBlockDef)
 												}
 											}
@@ -378,6 +378,8 @@ func TreeReport(
 		return err
 	}
 
+	defer memory.RLockDisk(path)()
+
 	superBlock := ext2.SuperBlock{}
 	superBlock.DeserializeSuperBlock(path, partition.Partition.Part_start)
 	superBlock.Print()
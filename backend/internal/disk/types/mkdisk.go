@@ -1,8 +1,9 @@
 package types
 
 type MkDisk struct {
-	Path string
-	Size int
-	Unit string
-	Fit  string
+	Path   string
+	Size   int
+	Unit   string
+	Fit    string
+	Sparse bool // Crear el archivo con truncate en lugar de escribir ceros
 }
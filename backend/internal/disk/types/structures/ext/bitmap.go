@@ -1,104 +1,59 @@
 package ext2
 
-import (
-	"encoding/binary"
-	"os"
-)
+import "fmt"
 
 // CreateBitMaps crea los Bitmaps de inodos y bloques en el archivo especificado
 func (sb *SuperBlock) CreateBitMaps(path string) error {
-	// Escribir Bitmaps
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Bitmap de inodos
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(int64(sb.SBmInodeStart), 0)
-	if err != nil {
-		return err
-	}
-
-	// Crear un buffer de n '0'
+	// Bitmap de inodos: un buffer de n '0'
 	buffer := make([]byte, sb.SFreeInodesCount)
 	for i := range buffer {
 		buffer[i] = '0'
 	}
 
-	// Escribir el buffer en el archivo
-	err = binary.Write(file, binary.LittleEndian, buffer)
-	if err != nil {
-		return err
-	}
-
-	// Bitmap de bloques
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(int64(sb.SBlockStart), 0)
+	err := writeDisk(path, int64(sb.SBmInodeStart), buffer)
 	if err != nil {
 		return err
 	}
 
-	// Crear un buffer de n 'O'
+	// Bitmap de bloques: un buffer de n 'O'
 	buffer = make([]byte, sb.SFreeBlocksCount)
 	for i := range buffer {
 		buffer[i] = 'O'
 	}
 
-	// Escribir el buffer en el archivo
-	err = binary.Write(file, binary.LittleEndian, buffer)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return writeDisk(path, int64(sb.SBlockStart), buffer)
 }
 
 // Actualizar Bitmap de inodos
 func (sb *SuperBlock) UpdateBitmapInode(path string) error {
-	// Abrir el archivo
-	file, err := os.OpenFile(path, os.O_RDWR, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Mover el puntero del archivo a la posición del bitmap de inodos
-	_, err = file.Seek(int64(sb.SBmInodeStart)+int64(sb.SInodesCount), 0)
-	if err != nil {
-		return err
-	}
-
-	// Escribir el bit en el archivo
-	_, err = file.Write([]byte{'1'})
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Escribir el bit en la posición del bitmap de inodos
+	return writeDisk(path, int64(sb.SBmInodeStart)+int64(sb.SInodesCount), []byte{'1'})
 }
 
 // Actualizar Bitmap de bloques
 func (sb *SuperBlock) UpdateBitmapBlock(path string) error {
-	// Abrir el archivo
-	file, err := os.OpenFile(path, os.O_RDWR, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	// Se marca el bloque que indica SFirstBlo, que es el que se está reservando.
+	// SBlocksCount solo coincide con él mientras los contadores estén sincronizados
+	blockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
+	return writeDisk(path, int64(sb.SBmBlockStart)+int64(blockIndex), []byte{'X'})
+}
 
-	// Mover el puntero del archivo a la posición del bitmap de bloques
-	_, err = file.Seek(int64(sb.SBmBlockStart)+int64(sb.SBlocksCount), 0)
-	if err != nil {
-		return err
+// allocateBlock reserva el bloque que indica SFirstBlo: lo marca en el bitmap,
+// actualiza los contadores y retorna su índice
+func (sb *SuperBlock) allocateBlock(path string) (int32, error) {
+	blockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
+	if sb.SFreeBlocksCount <= 0 || blockIndex >= sb.BlocksTotal() {
+		return -1, fmt.Errorf("no hay bloques libres")
 	}
 
-	// Escribir el bit en el archivo
-	_, err = file.Write([]byte{'X'})
+	err := writeDisk(path, int64(sb.SBmBlockStart)+int64(blockIndex), []byte{'X'})
 	if err != nil {
-		return err
+		return -1, err
 	}
 
-	return nil
+	sb.SBlocksCount = blockIndex + 1
+	sb.SFreeBlocksCount--
+	sb.SFirstBlo += sb.SBlockS
+
+	return blockIndex, nil
 }
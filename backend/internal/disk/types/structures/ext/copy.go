@@ -148,7 +148,7 @@ func (sb *SuperBlock) fileExistsInDirectory(path string, dirInodeIndex int32, na
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			if strings.EqualFold(entryName, name) {
 				return true, nil
 			}
@@ -341,7 +341,7 @@ func (sb *SuperBlock) copyDirectoryContents(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			// Ignorar las entradas "." y ".." que son referencias a sí mismo y al padre
 			if entryName == "." || entryName == ".." {
 				continue
@@ -465,6 +465,11 @@ func (inode *INode) GetName() string {
 	return "archivo/directorio"
 }
 
+// CanRead indica si un usuario puede leer un inodo, con las mismas reglas que copy y move
+func (sb *SuperBlock) CanRead(inode *INode, uid int32, gid int32) bool {
+	return sb.userHasReadPermission(inode, uid, gid)
+}
+
 // userHasReadPermission verifica si un usuario tiene permisos de lectura en un inodo
 func (sb *SuperBlock) userHasReadPermission(inode *INode, uid int32, gid int32) bool {
 	// Usuario propietario
@@ -507,7 +512,7 @@ func (sb *SuperBlock) findInodeInDirectory(diskPath string, dirInodeIndex int32,
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(diskPath, entry)
 			if entryName == name {
 				return entry.BInodo, nil
 			}
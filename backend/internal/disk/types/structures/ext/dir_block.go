@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"os"
 )
 
 const (
@@ -22,41 +21,19 @@ type DirContent struct {
 
 // Serialize escribe la estructura FolderBlock en un archivo binario en la posición especificada
 func (fb *DirBlock) Serialize(path string, offset int64) error {
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	// Serializar la estructura FolderBlock en un buffer
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, fb)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
-	// Serializar la estructura FolderBlock directamente en el archivo
-	err = binary.Write(file, binary.LittleEndian, fb)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Escribir el buffer en el disco (queda pendiente si hay una transacción activa)
+	return writeDisk(path, offset, buf.Bytes())
 }
 
 // Deserialize lee la estructura FolderBlock desde un archivo binario en la posición especificada
 func (fb *DirBlock) Deserialize(path string, offset int64) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
 	// Obtener el tamaño de la estructura FolderBlock
 	fbSize := binary.Size(fb)
 	if fbSize <= 0 {
@@ -64,8 +41,7 @@ func (fb *DirBlock) Deserialize(path string, offset int64) error {
 	}
 
 	// Leer solo la cantidad de bytes que corresponden al tamaño de la estructura FolderBlock
-	buffer := make([]byte, fbSize)
-	_, err = file.Read(buffer)
+	buffer, err := readDisk(path, offset, fbSize)
 	if err != nil {
 		return err
 	}
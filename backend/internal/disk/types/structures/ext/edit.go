@@ -7,6 +7,18 @@ import (
 )
 
 func (sb *SuperBlock) EditFile(partitionPath string, parentDirs []string, fileName string, contentPath string, uid int32, gid int32) error {
+	// Leer el contenido del archivo local
+	newContent, err := os.ReadFile(contentPath)
+	if err != nil {
+		return fmt.Errorf("error al leer el archivo de contenido '%s': %v", contentPath, err)
+	}
+
+	return sb.EditFileContent(partitionPath, parentDirs, fileName, newContent, uid, gid)
+}
+
+// EditFileContent reemplaza el contenido de un archivo existente por newContent,
+// sin superar el tamaño original del archivo
+func (sb *SuperBlock) EditFileContent(partitionPath string, parentDirs []string, fileName string, newContent []byte, uid int32, gid int32) error {
 	// 1. Buscar inodo del archivo
 	inodeIndex, err := sb.FindFileInode(partitionPath, parentDirs, fileName)
 	if err != nil {
@@ -30,22 +42,16 @@ func (sb *SuperBlock) EditFile(partitionPath string, parentDirs []string, fileNa
 		return fmt.Errorf("error: no tienes permisos de escritura sobre este archivo")
 	}
 
-	// 5. Leer el contenido del archivo local
-	newContent, err := os.ReadFile(contentPath)
-	if err != nil {
-		return fmt.Errorf("error al leer el archivo de contenido '%s': %v", contentPath, err)
-	}
-
-	// 6. Verificar que el nuevo contenido no exceda el tamaño original
+	// 5. Verificar que el nuevo contenido no exceda el tamaño original
 	if len(newContent) > int(fileInode.ISize) {
 		return fmt.Errorf("el nuevo contenido excede el tamaño original del archivo (%d bytes vs %d bytes)", len(newContent), fileInode.ISize)
 	}
 
-	// 7. Escribir el nuevo contenido en los bloques existentes
+	// 6. Escribir el nuevo contenido en los bloques existentes
 	contentOffset := 0
 	contentSize := len(newContent)
 
-	// 7.1 Escribir en bloques directos (0-11)
+	// 6.1 Escribir en bloques directos (0-11)
 	for i := 0; i < 12 && fileInode.IBlock[i] != -1 && contentOffset < contentSize; i++ {
 		blockIndex := fileInode.IBlock[i]
 		fileBlock := &FileBlock{
@@ -84,7 +90,7 @@ func (sb *SuperBlock) EditFile(partitionPath string, parentDirs []string, fileNa
 		fmt.Printf("Bloque directo #%d actualizado con %d bytes\n", blockIndex, bytesToCopy)
 	}
 
-	// 7.2 Escribir en bloques indirectos simples (si es necesario)
+	// 6.2 Escribir en bloques indirectos simples (si es necesario)
 	if contentOffset < contentSize && fileInode.IBlock[12] != -1 {
 		// Obtener el bloque de punteros indirectos
 		pointerBlock := &PointerBlock{}
@@ -133,7 +139,7 @@ func (sb *SuperBlock) EditFile(partitionPath string, parentDirs []string, fileNa
 		}
 	}
 
-	// 7.3 Si hay bloques indirectos dobles, procesarlos (si es necesario)
+	// 6.3 Si hay bloques indirectos dobles, procesarlos (si es necesario)
 	if contentOffset < contentSize && fileInode.IBlock[13] != -1 {
 		// Obtener el bloque de punteros dobles
 		doublePointerBlock := &PointerBlock{}
@@ -194,18 +200,18 @@ func (sb *SuperBlock) EditFile(partitionPath string, parentDirs []string, fileNa
 		}
 	}
 
-	// 7.4 Si hay bloques indirectos triples, procesarlos (si es necesario)
+	// 6.4 Si hay bloques indirectos triples, procesarlos (si es necesario)
 	if contentOffset < contentSize && fileInode.IBlock[14] != -1 {
 		// Lógica similar a los bloques indirectos dobles, pero con un nivel más de indirección
 		// Esta parte se implementaría si fuera necesario (raramente se usarían bloques triples para archivos pequeños)
 		fmt.Println("Advertencia: Edición en bloques indirectos triples no implementada")
 	}
 
-	// 8. Actualizar el tamaño del archivo y timestamp de modificación
+	// 7. Actualizar el tamaño del archivo y timestamp de modificación
 	fileInode.ISize = int32(len(newContent))
 	fileInode.IMtime = float32(time.Now().Unix())
 
-	// 9. Guardar el inodo actualizado
+	// 8. Guardar el inodo actualizado
 	err = fileInode.Serialize(partitionPath, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
 	if err != nil {
 		return fmt.Errorf("error al actualizar el inodo del archivo: %v", err)
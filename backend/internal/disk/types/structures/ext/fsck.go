@@ -0,0 +1,403 @@
+package ext2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FsckResult contiene el resultado de la verificación del sistema de archivos
+type FsckResult struct {
+	Problems    []string // Problemas encontrados
+	Repaired    int      // Cantidad de problemas reparados
+	InodesUsed  int32    // Inodos alcanzables desde la raíz
+	BlocksUsed  int32    // Bloques referenciados por los inodos alcanzables
+	InodesTotal int32    // Total de inodos de la partición
+	BlocksTotal int32    // Total de bloques de la partición
+}
+
+// fsChecker guarda el estado del recorrido del verificador
+type fsChecker struct {
+	sb     *SuperBlock
+	path   string
+	fix    bool
+	result *FsckResult
+
+	visited    map[int32]bool  // Inodos alcanzados desde la raíz
+	inodeRefs  map[int32]int   // Entradas de directorio que apuntan a cada inodo
+	blockRefs  map[int32]int   // Referencias a cada bloque
+	blockOwner map[int32]int32 // Primer inodo que referencia cada bloque
+}
+
+// Check recorre el sistema de archivos desde el inodo raíz y compara los inodos y
+// bloques alcanzables con los bitmaps y los contadores del superbloque.
+// Si fix es true se reparan los problemas que tienen una reparación segura; el
+// llamador debe guardar el superbloque después.
+func (sb *SuperBlock) Check(path string, fix bool) (*FsckResult, error) {
+	if sb.SMagic != 0xEF53 {
+		return nil, fmt.Errorf("el superbloque no tiene un número mágico válido")
+	}
+
+	checker := &fsChecker{
+		sb:   sb,
+		path: path,
+		fix:  fix,
+		result: &FsckResult{
+			InodesTotal: sb.InodesTotal(),
+			BlocksTotal: sb.BlocksTotal(),
+		},
+		visited:    map[int32]bool{},
+		inodeRefs:  map[int32]int{},
+		blockRefs:  map[int32]int{},
+		blockOwner: map[int32]int32{},
+	}
+
+	// Sin un inodo raíz válido no se puede recorrer el árbol
+	root := &INode{}
+	err := root.Deserialize(path, int64(sb.SInodeStart))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el inodo raíz: %v", err)
+	}
+	if root.IType[0] != '0' {
+		checker.problem("el inodo raíz está dañado, use 'recovery' para reconstruir el sistema de archivos")
+		return checker.result, nil
+	}
+
+	err = checker.walk(0, 0, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.checkInodeBitmap()
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.checkBlockBitmap()
+	if err != nil {
+		return nil, err
+	}
+
+	checker.checkCounters()
+
+	return checker.result, nil
+}
+
+// problem agrega un problema al resultado
+func (c *fsChecker) problem(format string, args ...interface{}) {
+	c.result.Problems = append(c.result.Problems, fmt.Sprintf(format, args...))
+}
+
+// repaired agrega un problema y lo marca como reparado si se está reparando
+func (c *fsChecker) repaired(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if c.fix {
+		message += " (reparado)"
+		c.result.Repaired++
+	}
+	c.result.Problems = append(c.result.Problems, message)
+}
+
+// markBlock registra una referencia a un bloque
+func (c *fsChecker) markBlock(blockIndex int32, owner int32) {
+	c.blockRefs[blockIndex]++
+	if c.blockRefs[blockIndex] == 1 {
+		c.blockOwner[blockIndex] = owner
+	}
+}
+
+// walk recorre un inodo y, si es una carpeta, todas sus entradas
+func (c *fsChecker) walk(inodeIndex int32, parentIndex int32, entryPath string) error {
+	if c.visited[inodeIndex] {
+		return nil
+	}
+	c.visited[inodeIndex] = true
+
+	inode := &INode{}
+	err := inode.Deserialize(c.path, int64(c.sb.SInodeStart+(inodeIndex*c.sb.SInodeS)))
+	if err != nil {
+		return fmt.Errorf("error al leer el inodo %d: %v", inodeIndex, err)
+	}
+
+	refs, err := c.sb.InodeBlockList(c.path, inode)
+	if err != nil {
+		c.problem("no se pudieron leer los bloques de '%s' (inodo %d): %v", entryPath, inodeIndex, err)
+		return nil
+	}
+
+	for _, ref := range refs {
+		c.markBlock(ref.Block, inodeIndex)
+	}
+
+	if inode.IType[0] != '0' {
+		return nil
+	}
+
+	firstDataBlock := true
+	for _, ref := range refs {
+		if ref.Pointer {
+			continue
+		}
+
+		err = c.checkDirBlock(ref.Block, inodeIndex, parentIndex, entryPath, firstDataBlock && ref.Block == inode.IBlock[0])
+		if err != nil {
+			return err
+		}
+		firstDataBlock = false
+	}
+
+	return nil
+}
+
+// checkDirBlock verifica las entradas de un bloque de carpeta y recorre sus hijos
+func (c *fsChecker) checkDirBlock(blockIndex int32, inodeIndex int32, parentIndex int32, entryPath string, first bool) error {
+	offset := int64(c.sb.SBlockStart + (blockIndex * c.sb.SBlockS))
+
+	dirBlock := &DirBlock{}
+	err := dirBlock.Deserialize(c.path, offset)
+	if err != nil {
+		return fmt.Errorf("error al leer el bloque de carpeta %d: %v", blockIndex, err)
+	}
+
+	modified := false
+
+	// El primer bloque de cada carpeta empieza con "." y ".."
+	if first {
+		dots := []struct {
+			name  string
+			inode int32
+		}{{".", inodeIndex}, {"..", parentIndex}}
+
+		for j, dot := range dots {
+			entry := &dirBlock.BContent[j]
+			name := c.sb.EntryName(c.path, *entry)
+			if name == dot.name && entry.BInodo == dot.inode {
+				continue
+			}
+
+			if entry.BInodo != -1 && name != "." && name != ".." {
+				c.problem("la entrada '%s' de '%s' ocupa el lugar de '%s'", name, entryPath, dot.name)
+				continue
+			}
+
+			c.repaired("la entrada '%s' de '%s' apunta al inodo %d en lugar de %d", dot.name, entryPath, entry.BInodo, dot.inode)
+			if c.fix {
+				entry.BName = [12]byte{}
+				copy(entry.BName[:], dot.name)
+				entry.BInodo = dot.inode
+				modified = true
+			}
+		}
+	}
+
+	for j := range dirBlock.BContent {
+		entry := &dirBlock.BContent[j]
+		if entry.BInodo == -1 {
+			continue
+		}
+
+		name := c.sb.EntryName(c.path, *entry)
+		if name == "." || name == ".." {
+			continue
+		}
+
+		childPath := strings.TrimSuffix(entryPath, "/") + "/" + name
+
+		// La entrada debe apuntar a un inodo válido
+		if !c.isValidEntryInode(entry.BInodo) {
+			c.repaired("la entrada '%s' apunta a un inodo inválido (%d)", childPath, entry.BInodo)
+			if c.fix {
+				entry.BInodo = -1
+				entry.BName = [12]byte{'-'}
+				modified = true
+			}
+			continue
+		}
+
+		for _, nameBlock := range c.sb.nameBlocks(c.path, *entry) {
+			c.markBlock(nameBlock, inodeIndex)
+		}
+
+		c.inodeRefs[entry.BInodo]++
+		if c.inodeRefs[entry.BInodo] > 1 {
+			c.problem("el inodo %d está referenciado por más de una entrada ('%s')", entry.BInodo, childPath)
+			continue
+		}
+
+		err = c.walk(entry.BInodo, inodeIndex, childPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if modified {
+		err = dirBlock.Serialize(c.path, offset)
+		if err != nil {
+			return fmt.Errorf("error al escribir el bloque de carpeta %d: %v", blockIndex, err)
+		}
+	}
+
+	return nil
+}
+
+// isValidEntryInode verifica que el índice esté en la tabla y que el inodo tenga un tipo válido
+func (c *fsChecker) isValidEntryInode(inodeIndex int32) bool {
+	if inodeIndex < 0 || inodeIndex >= c.sb.InodesTotal() {
+		return false
+	}
+
+	inode := &INode{}
+	err := inode.Deserialize(c.path, int64(c.sb.SInodeStart+(inodeIndex*c.sb.SInodeS)))
+	if err != nil {
+		return false
+	}
+
+	return inode.IType[0] == '0' || inode.IType[0] == '1'
+}
+
+// checkInodeBitmap compara el bitmap de inodos con los inodos alcanzables
+func (c *fsChecker) checkInodeBitmap() error {
+	total := c.sb.InodesTotal()
+	bitmap, err := readBitmap(c.path, int64(c.sb.SBmInodeStart), total)
+	if err != nil {
+		return fmt.Errorf("error al leer el bitmap de inodos: %v", err)
+	}
+
+	for i := int32(0); i < total; i++ {
+		used := bitmapUsed(bitmap[i])
+		reachable := c.visited[i]
+
+		switch {
+		case used && !reachable:
+			c.repaired("el inodo %d está marcado en uso pero no es alcanzable desde la raíz (huérfano)", i)
+			bitmap[i] = 0
+		case !used && reachable:
+			c.repaired("el inodo %d está en uso pero marcado libre en el bitmap", i)
+			bitmap[i] = '1'
+		}
+	}
+
+	c.result.InodesUsed = int32(len(c.visited))
+
+	if c.fix {
+		return writeBitmap(c.path, int64(c.sb.SBmInodeStart), bitmap)
+	}
+	return nil
+}
+
+// checkBlockBitmap compara el bitmap de bloques con los bloques referenciados
+func (c *fsChecker) checkBlockBitmap() error {
+	total := c.sb.BlocksTotal()
+	bitmap, err := readBitmap(c.path, int64(c.sb.SBmBlockStart), total)
+	if err != nil {
+		return fmt.Errorf("error al leer el bitmap de bloques: %v", err)
+	}
+
+	for i := int32(0); i < total; i++ {
+		used := bitmapUsed(bitmap[i])
+		refs := c.blockRefs[i]
+
+		if refs > 1 {
+			c.problem("el bloque %d está referenciado %d veces (primer dueño: inodo %d)", i, refs, c.blockOwner[i])
+		}
+
+		switch {
+		case used && refs == 0:
+			c.repaired("el bloque %d está marcado en uso pero no está referenciado", i)
+			bitmap[i] = 0
+		case !used && refs > 0:
+			c.repaired("el bloque %d está en uso por el inodo %d pero marcado libre en el bitmap", i, c.blockOwner[i])
+			bitmap[i] = 'X'
+		}
+	}
+
+	c.result.BlocksUsed = int32(len(c.blockRefs))
+
+	if c.fix {
+		return writeBitmap(c.path, int64(c.sb.SBmBlockStart), bitmap)
+	}
+	return nil
+}
+
+// bitmapUsed indica si una entrada del bitmap está marcada en uso. Las entradas
+// libres pueden contener 0, '0' (inodos) u 'O' (bloques) según quién las escribió.
+func bitmapUsed(b byte) bool {
+	return b != 0 && b != '0' && b != 'O'
+}
+
+// checkCounters verifica los contadores y los punteros al primer inodo y bloque libre.
+// SInodesCount y SBlocksCount se usan para asignar el siguiente índice, por lo que
+// nunca deben quedar por debajo del último inodo o bloque en uso. SFirstIno y
+// SFirstBlo se comparan con el valor corregido de esos contadores, así la revisión
+// reporta los mismos problemas con y sin -fix.
+func (c *fsChecker) checkCounters() {
+	sb := c.sb
+
+	freeInodes := c.result.InodesTotal - c.result.InodesUsed
+	if sb.SFreeInodesCount != freeInodes {
+		c.repaired("SFreeInodesCount es %d, se esperaba %d", sb.SFreeInodesCount, freeInodes)
+		if c.fix {
+			sb.SFreeInodesCount = freeInodes
+		}
+	}
+
+	freeBlocks := c.result.BlocksTotal - c.result.BlocksUsed
+	if sb.SFreeBlocksCount != freeBlocks {
+		c.repaired("SFreeBlocksCount es %d, se esperaba %d", sb.SFreeBlocksCount, freeBlocks)
+		if c.fix {
+			sb.SFreeBlocksCount = freeBlocks
+		}
+	}
+
+	inodesCount := max(sb.SInodesCount, highestIndex(c.visited)+1)
+	if sb.SInodesCount != inodesCount {
+		c.repaired("SInodesCount es %d pero el inodo %d está en uso", sb.SInodesCount, inodesCount-1)
+		if c.fix {
+			sb.SInodesCount = inodesCount
+		}
+	}
+
+	blocksCount := max(sb.SBlocksCount, highestIndex(c.blockRefs)+1)
+	if sb.SBlocksCount != blocksCount {
+		c.repaired("SBlocksCount es %d pero el bloque %d está en uso", sb.SBlocksCount, blocksCount-1)
+		if c.fix {
+			sb.SBlocksCount = blocksCount
+		}
+	}
+
+	firstIno := sb.SInodeStart + inodesCount*sb.SInodeS
+	if sb.SFirstIno != firstIno {
+		c.repaired("SFirstIno es %d, se esperaba %d", sb.SFirstIno, firstIno)
+		if c.fix {
+			sb.SFirstIno = firstIno
+		}
+	}
+
+	firstBlo := sb.SBlockStart + blocksCount*sb.SBlockS
+	if sb.SFirstBlo != firstBlo {
+		c.repaired("SFirstBlo es %d, se esperaba %d", sb.SFirstBlo, firstBlo)
+		if c.fix {
+			sb.SFirstBlo = firstBlo
+		}
+	}
+}
+
+// highestIndex devuelve el índice más alto de un conjunto de inodos o bloques
+func highestIndex[V any](set map[int32]V) int32 {
+	highest := int32(-1)
+	for index := range set {
+		if index > highest {
+			highest = index
+		}
+	}
+	return highest
+}
+
+// readBitmap lee un bitmap completo desde el disco
+func readBitmap(path string, offset int64, size int32) ([]byte, error) {
+	return readDisk(path, offset, int(size))
+}
+
+// writeBitmap escribe un bitmap completo en el disco
+func writeBitmap(path string, offset int64, bitmap []byte) error {
+	return writeDisk(path, offset, bitmap)
+}
@@ -0,0 +1,326 @@
+package ext2
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fsckDiskSize es el tamaño de los discos de las pruebas de fsck (1 MB)
+const fsckDiskSize = 1024 * 1024
+
+// readInode lee un inodo de la tabla de inodos
+func readInode(t *testing.T, path string, sb *SuperBlock, index int32) *INode {
+	t.Helper()
+
+	inode := &INode{}
+	if err := inode.Deserialize(path, int64(sb.SInodeStart+index*sb.SInodeS)); err != nil {
+		t.Fatal(err)
+	}
+	return inode
+}
+
+// writeInode escribe un inodo en la tabla de inodos
+func writeInode(t *testing.T, path string, sb *SuperBlock, index int32, inode *INode) {
+	t.Helper()
+
+	if err := inode.Serialize(path, int64(sb.SInodeStart+index*sb.SInodeS)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// editDirBlock lee un bloque de carpeta, le aplica edit y lo vuelve a escribir
+func editDirBlock(t *testing.T, path string, sb *SuperBlock, index int32, edit func(block *DirBlock)) {
+	t.Helper()
+
+	offset := int64(sb.SBlockStart + index*sb.SBlockS)
+	block := &DirBlock{}
+	if err := block.Deserialize(path, offset); err != nil {
+		t.Fatal(err)
+	}
+	edit(block)
+	if err := block.Serialize(path, offset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// check ejecuta fsck y falla la prueba si no se pudo recorrer la partición
+func check(t *testing.T, path string, sb *SuperBlock, fix bool) *FsckResult {
+	t.Helper()
+
+	result, err := sb.Check(path, fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// expectProblems verifica que fsck reporte exactamente los problemas indicados,
+// sin importar el orden ni la marca de reparado
+func expectProblems(t *testing.T, result *FsckResult, want ...string) {
+	t.Helper()
+
+	got := make([]string, len(result.Problems))
+	for i, problem := range result.Problems {
+		got[i] = strings.TrimSuffix(problem, " (reparado)")
+	}
+	slices.Sort(got)
+	want = slices.Clone(want)
+	slices.Sort(want)
+
+	if !slices.Equal(got, want) {
+		t.Errorf("problemas = %q\nse esperaba %q", got, want)
+	}
+}
+
+func TestCheckCleanPartition(t *testing.T) {
+	silenceOutput(t)
+	path, sb := newTestDisk(t, fsckDiskSize)
+
+	if err := sb.CreateFolder(path, nil, "docs", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.CreateFile(path, []string{"docs"}, "a.txt", 300, "", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	result := check(t, path, sb, false)
+	expectProblems(t, result)
+	if result.InodesUsed != sb.SInodesCount {
+		t.Errorf("InodesUsed = %d, se esperaba %d", result.InodesUsed, sb.SInodesCount)
+	}
+}
+
+func TestCheckOrphanInode(t *testing.T) {
+	silenceOutput(t)
+	path, sb := newTestDisk(t, fsckDiskSize)
+
+	if err := sb.CreateFile(path, nil, "a.txt", 200, "", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	orphan := sb.SInodesCount - 1
+	blocks := readInode(t, path, sb, orphan).IBlock[:4]
+
+	// Borrar la entrada de la raíz deja el inodo y sus bloques marcados sin dueño
+	editDirBlock(t, path, sb, 0, func(block *DirBlock) {
+		block.BContent[3] = DirContent{BName: [12]byte{'-'}, BInodo: -1}
+	})
+
+	want := []string{
+		fmt.Sprintf("el inodo %d está marcado en uso pero no es alcanzable desde la raíz (huérfano)", orphan),
+		fmt.Sprintf("SFreeInodesCount es %d, se esperaba %d", sb.SFreeInodesCount, sb.SFreeInodesCount+1),
+		fmt.Sprintf("SFreeBlocksCount es %d, se esperaba %d", sb.SFreeBlocksCount, sb.SFreeBlocksCount+4),
+	}
+	for _, block := range blocks {
+		want = append(want, fmt.Sprintf("el bloque %d está marcado en uso pero no está referenciado", block))
+	}
+
+	expectProblems(t, check(t, path, sb, false), want...)
+
+	result := check(t, path, sb, true)
+	expectProblems(t, result, want...)
+	if result.Repaired != len(want) {
+		t.Errorf("Repaired = %d, se esperaba %d", result.Repaired, len(want))
+	}
+
+	bitmap, err := readBitmap(path, int64(sb.SBmInodeStart), sb.InodesTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bitmapUsed(bitmap[orphan]) {
+		t.Errorf("el inodo huérfano %d sigue marcado en el bitmap", orphan)
+	}
+
+	expectProblems(t, check(t, path, sb, false))
+}
+
+func TestCheckBlockReferencedTwice(t *testing.T) {
+	silenceOutput(t)
+	path, sb := newTestDisk(t, fsckDiskSize)
+
+	if err := sb.CreateFolder(path, nil, "d", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"f1.txt", "f2.txt"} {
+		if err := sb.CreateFile(path, []string{"d"}, name, 10, "", false, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, second := sb.SInodesCount-2, sb.SInodesCount-1
+
+	// f2.txt pasa a compartir el bloque de f1.txt y su bloque original queda sin dueño
+	shared := readInode(t, path, sb, first).IBlock[0]
+	inode := readInode(t, path, sb, second)
+	lost := inode.IBlock[0]
+	inode.IBlock[0] = shared
+	writeInode(t, path, sb, second, inode)
+
+	twice := fmt.Sprintf("el bloque %d está referenciado 2 veces (primer dueño: inodo %d)", shared, first)
+	want := []string{
+		twice,
+		fmt.Sprintf("el bloque %d está marcado en uso pero no está referenciado", lost),
+		fmt.Sprintf("SFreeBlocksCount es %d, se esperaba %d", sb.SFreeBlocksCount, sb.SFreeBlocksCount+1),
+	}
+
+	expectProblems(t, check(t, path, sb, false), want...)
+
+	// Un bloque compartido no tiene una reparación segura, así que no se cuenta
+	result := check(t, path, sb, true)
+	expectProblems(t, result, want...)
+	if result.Repaired != 2 {
+		t.Errorf("Repaired = %d, se esperaba 2", result.Repaired)
+	}
+
+	expectProblems(t, check(t, path, sb, false), twice)
+}
+
+func TestCheckDotEntries(t *testing.T) {
+	silenceOutput(t)
+	path, sb := newTestDisk(t, fsckDiskSize)
+
+	if err := sb.CreateFolder(path, nil, "d", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	dir := sb.SInodesCount - 1
+	dirBlock := readInode(t, path, sb, dir).IBlock[0]
+
+	// "." apunta a la raíz y ".." se borra
+	editDirBlock(t, path, sb, dirBlock, func(block *DirBlock) {
+		block.BContent[0].BInodo = 0
+		block.BContent[1] = DirContent{BName: [12]byte{'-'}, BInodo: -1}
+	})
+
+	want := []string{
+		fmt.Sprintf("la entrada '.' de '/d' apunta al inodo 0 en lugar de %d", dir),
+		"la entrada '..' de '/d' apunta al inodo -1 en lugar de 0",
+	}
+
+	expectProblems(t, check(t, path, sb, false), want...)
+
+	result := check(t, path, sb, true)
+	expectProblems(t, result, want...)
+	if result.Repaired != len(want) {
+		t.Errorf("Repaired = %d, se esperaba %d", result.Repaired, len(want))
+	}
+
+	editDirBlock(t, path, sb, dirBlock, func(block *DirBlock) {
+		for i, dot := range []DirContent{{BName: [12]byte{'.'}, BInodo: dir}, {BName: [12]byte{'.', '.'}, BInodo: 0}} {
+			if block.BContent[i] != dot {
+				t.Errorf("entrada %d = %+v, se esperaba %+v", i, block.BContent[i], dot)
+			}
+		}
+	})
+
+	expectProblems(t, check(t, path, sb, false))
+}
+
+func TestCheckDotEntryTakenByFile(t *testing.T) {
+	silenceOutput(t)
+	path, sb := newTestDisk(t, fsckDiskSize)
+
+	if err := sb.CreateFolder(path, nil, "d", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	dirBlock := readInode(t, path, sb, sb.SInodesCount-1).IBlock[0]
+
+	// Un archivo en el lugar de ".." no se puede reparar sin perderlo
+	editDirBlock(t, path, sb, dirBlock, func(block *DirBlock) {
+		block.BContent[1] = DirContent{BName: [12]byte{'x'}, BInodo: 1}
+	})
+
+	want := []string{
+		"la entrada 'x' de '/d' ocupa el lugar de '..'",
+		"el inodo 1 está referenciado por más de una entrada ('/d/x')",
+	}
+
+	result := check(t, path, sb, true)
+	expectProblems(t, result, want...)
+	if result.Repaired != 0 {
+		t.Errorf("Repaired = %d, se esperaba 0", result.Repaired)
+	}
+}
+
+func TestCheckCounters(t *testing.T) {
+	tests := []struct {
+		name string
+		// corrupt daña los contadores del superbloque
+		corrupt func(sb *SuperBlock)
+		want    func(sb *SuperBlock) []string
+	}{
+		{
+			name: "SBlocksCount y SFirstBlo atrasados",
+			corrupt: func(sb *SuperBlock) {
+				sb.SBlocksCount -= 2
+				sb.SFirstBlo -= 2 * sb.SBlockS
+			},
+			want: func(sb *SuperBlock) []string {
+				return []string{
+					fmt.Sprintf("SBlocksCount es %d pero el bloque %d está en uso", sb.SBlocksCount-2, sb.SBlocksCount-1),
+					fmt.Sprintf("SFirstBlo es %d, se esperaba %d", sb.SFirstBlo-2*sb.SBlockS, sb.SFirstBlo),
+				}
+			},
+		},
+		{
+			name: "solo SBlocksCount atrasado",
+			corrupt: func(sb *SuperBlock) {
+				sb.SBlocksCount -= 2
+			},
+			want: func(sb *SuperBlock) []string {
+				return []string{
+					fmt.Sprintf("SBlocksCount es %d pero el bloque %d está en uso", sb.SBlocksCount-2, sb.SBlocksCount-1),
+				}
+			},
+		},
+		{
+			name: "SInodesCount y SFirstIno atrasados",
+			corrupt: func(sb *SuperBlock) {
+				sb.SInodesCount--
+				sb.SFirstIno -= sb.SInodeS
+			},
+			want: func(sb *SuperBlock) []string {
+				return []string{
+					fmt.Sprintf("SInodesCount es %d pero el inodo %d está en uso", sb.SInodesCount-1, sb.SInodesCount-1),
+					fmt.Sprintf("SFirstIno es %d, se esperaba %d", sb.SFirstIno-sb.SInodeS, sb.SFirstIno),
+				}
+			},
+		},
+		{
+			name: "SFirstBlo adelantado",
+			corrupt: func(sb *SuperBlock) {
+				sb.SFirstBlo += sb.SBlockS
+			},
+			want: func(sb *SuperBlock) []string {
+				return []string{
+					fmt.Sprintf("SFirstBlo es %d, se esperaba %d", sb.SFirstBlo+sb.SBlockS, sb.SFirstBlo),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silenceOutput(t)
+			path, sb := newTestDisk(t, fsckDiskSize)
+
+			if err := sb.CreateFile(path, nil, "a.txt", 100, "", false, 1, 1); err != nil {
+				t.Fatal(err)
+			}
+			clean := *sb
+			want := tt.want(&clean)
+			tt.corrupt(sb)
+
+			// Con y sin -fix se reportan los mismos problemas, y con -fix todos se reparan
+			expectProblems(t, check(t, path, sb, false), want...)
+
+			result := check(t, path, sb, true)
+			expectProblems(t, result, want...)
+			if result.Repaired != len(want) {
+				t.Errorf("Repaired = %d, se esperaba %d", result.Repaired, len(want))
+			}
+			if *sb != clean {
+				t.Errorf("superbloque reparado = %+v\nse esperaba %+v", *sb, clean)
+			}
+		})
+	}
+}
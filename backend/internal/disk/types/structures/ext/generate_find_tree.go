@@ -191,7 +191,7 @@ func (sb *SuperBlock) findInIndirectBlocks(diskPath string, blockIndex int32, na
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(diskPath, entry)
 			if entryName == name {
 				return entry.BInodo, true, nil
 			}
@@ -237,7 +237,7 @@ func (sb *SuperBlock) searchInDirectory(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(diskPath, entry)
 			if entryName == "." || entryName == ".." {
 				continue
 			}
@@ -336,7 +336,7 @@ func (sb *SuperBlock) searchInIndirectBlocks(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(diskPath, entry)
 			if entryName == "." || entryName == ".." {
 				continue
 			}
@@ -418,7 +418,7 @@ func (sb *SuperBlock) traverseDirectory(
 			}
 
 			// Obtener nombre de la entrada
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(diskPath, entry)
 
 			// Ignorar "." y ".."
 			if entryName == "." || entryName == ".." {
@@ -498,7 +498,7 @@ func (sb *SuperBlock) traverseIndirectBlocks(
 			}
 
 			// Obtener nombre de la entrada
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(diskPath, entry)
 
 			// Ignorar "." y ".."
 			if entryName == "." || entryName == ".." {
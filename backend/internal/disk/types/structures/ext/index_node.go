@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"os"
 	"time"
 )
 
@@ -26,41 +25,19 @@ type INode struct {
 
 // Serialize escribe la estructura Inode en un archivo binario en la posición especificada
 func (inode *INode) Serialize(path string, offset int64) error {
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	// Serializar la estructura Inode en un buffer
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, inode)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
-	// Serializar la estructura Inode directamente en el archivo
-	err = binary.Write(file, binary.LittleEndian, inode)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Escribir el buffer en el disco (queda pendiente si hay una transacción activa)
+	return writeDisk(path, offset, buf.Bytes())
 }
 
 // Deserialize lee la estructura Inode desde un archivo binario en la posición especificada
 func (inode *INode) Deserialize(path string, offset int64) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
 	// Obtener el tamaño de la estructura Inode
 	inodeSize := binary.Size(inode)
 	if inodeSize <= 0 {
@@ -68,8 +45,7 @@ func (inode *INode) Deserialize(path string, offset int64) error {
 	}
 
 	// Leer solo la cantidad de bytes que corresponden al tamaño de la estructura Inode
-	buffer := make([]byte, inodeSize)
-	_, err = file.Read(buffer)
+	buffer, err := readDisk(path, offset, inodeSize)
 	if err != nil {
 		return err
 	}
@@ -1,9 +1,12 @@
 package ext2
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -26,47 +29,26 @@ func (journal *Journal) Serialize(path string, journauling_start int64) error {
 	// Calcular la posición en el archivo
 	offset := journauling_start + (int64(binary.Size(Journal{})) * int64(journal.J_count))
 
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	// Serializar la estructura Journal en un buffer
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, journal)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
-	// Serializar la estructura Journal directamente en el archivo
-	err = binary.Write(file, binary.LittleEndian, journal)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Escribir el buffer en el disco (queda pendiente si hay una transacción activa)
+	return writeDisk(path, offset, buf.Bytes())
 }
 
 // DeserializeJournal lee la estructura Journal desde un archivo binario
 func (journal *Journal) Deserialize(path string, offset int64) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
+	buffer, err := readDisk(path, offset, binary.Size(journal))
 	if err != nil {
 		return err
 	}
 
-	// Deserializar la estructura Journal directamente desde el archivo
-	err = binary.Read(file, binary.LittleEndian, journal)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Deserializar los bytes leídos en la estructura Journal
+	return binary.Read(bytes.NewReader(buffer), binary.LittleEndian, journal)
 }
 
 // PrintJournal imprime en consola la estructura Journal
@@ -83,6 +65,91 @@ func (journal *Journal) Print() {
 	fmt.Printf("I_date: %s", date.Format(time.RFC3339))
 }
 
+// Políticas para cuando el journal circular está lleno
+const (
+	JournalOverwrite int32 = 0 // Se recupera la entrada más antigua y se sobrescribe
+	JournalError     int32 = 1 // La operación falla hasta que se haga un checkpoint
+)
+
+// ErrJournalFull se devuelve cuando el journal está lleno y la política es JournalError
+var ErrJournalFull = errors.New("el journal está lleno, ejecute un checkpoint o cambie la política a overwrite")
+
+// ErrTransactionTooLarge se devuelve cuando una transacción necesita más slots
+// de los que tiene el journal y no se puede aplicar de forma atómica
+var ErrTransactionTooLarge = errors.New("la operación es demasiado grande para el journal de la partición")
+
+// JournalPolicyName devuelve el nombre de una política de journal lleno
+func JournalPolicyName(policy int32) string {
+	if policy == JournalError {
+		return "error"
+	}
+	return "overwrite"
+}
+
+// ParseJournalPolicy convierte el nombre de una política en su valor
+func ParseJournalPolicy(name string) (int32, error) {
+	switch strings.ToLower(name) {
+	case "overwrite":
+		return JournalOverwrite, nil
+	case "error":
+		return JournalError, nil
+	}
+	return 0, fmt.Errorf("política de journal inválida '%s' (use overwrite o error)", name)
+}
+
+// HasJournal indica si la partición es EXT3 y tiene un área de journaling
+func (sb *SuperBlock) HasJournal() bool {
+	return sb.SMagic == 0xEF53 && sb.SFilesystemType == 3 && sb.SJournalSize > 0
+}
+
+// JournalCapacity devuelve la cantidad de slots del journal circular
+func (sb *SuperBlock) JournalCapacity(partitionStart int32) int32 {
+	_, slots := sb.journalArea(partitionStart)
+	return slots
+}
+
+// JournalUsed devuelve la cantidad de entradas vivas entre head y tail
+func (sb *SuperBlock) JournalUsed() int32 {
+	return sb.SJournalTail - sb.SJournalHead
+}
+
+// Tipos de los slots de continuación de una entrada del journal. Igual que los
+// registros transaccionales, ocupan la posición de I_operation
+const (
+	JournalPathCont    = "JPATH"
+	JournalContentCont = "JCONT"
+)
+
+// journalContDataSize es la cantidad de bytes que caben en un slot de continuación
+const journalContDataSize = 296
+
+// JournalContinuation guarda la parte de la ruta o del contenido de una entrada
+// que no cabe en su slot principal. Se escribe en los slots siguientes y tiene
+// el mismo tamaño que Journal
+type JournalContinuation struct {
+	C_count  int32                     // 4 bytes - Secuencia del slot (misma posición que J_count)
+	C_kind   [10]byte                  // 10 bytes - JPATH o JCONT
+	C_head   int32                     // 4 bytes - Secuencia del slot principal de la entrada
+	C_length int32                     // 4 bytes - Bytes válidos en C_data
+	C_data   [journalContDataSize]byte // 296 bytes - Fragmento de la ruta o del contenido
+	// Total: 318 bytes
+}
+
+// JournalEntry es una entrada del journal reensamblada a partir de su slot
+// principal y de sus slots de continuación
+type JournalEntry struct {
+	Sequence  int32     `json:"sequence"`
+	Operation string    `json:"operation"`
+	Path      string    `json:"path"`
+	Content   string    `json:"content"`
+	Date      time.Time `json:"date"`
+}
+
+// AddJournal agrega una entrada al final (tail) del journal circular. La ruta y
+// el contenido que no caben en el slot principal se guardan completos en slots de
+// continuación consecutivos. Si el journal está lleno se aplica la política del
+// superbloque: con JournalOverwrite se recuperan las entradas más antiguas (head)
+// y con JournalError se devuelve ErrJournalFull.
 func AddJournal(path string, partitionStart int64, journalCount int32, operation, filePath, content string) error {
 	// Leer el SuperBlock para verificar si es ext3
 	sb := &SuperBlock{}
@@ -92,121 +159,234 @@ func AddJournal(path string, partitionStart int64, journalCount int32, operation
 	}
 
 	// Verificar si el journaling está habilitado (ext3)
-	if sb.SFilesystemType != 3 {
+	if !sb.HasJournal() {
 		fmt.Println("El sistema de archivos no es ext3, no se creará el journal.")
 		return nil
 	}
 
 	// El inicio del journaling es justo después del SuperBlock
-	journalingStart := partitionStart + int64(binary.Size(SuperBlock{}))
+	journalingStart, capacity := sb.journalArea(int32(partitionStart))
+	if capacity <= 0 {
+		fmt.Println("La partición no tiene área de journaling, no se creará el journal.")
+		return nil
+	}
 
-	// Encontrar el siguiente índice de journal disponible
-	nextIndex := int32(0)
-	file, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("error al abrir el archivo: %v", err)
+	// Armar los slots de la entrada: el principal y los de continuación
+	date := float32(time.Now().Unix())
+	sequence := sb.SJournalTail
+	records := journalRecords(sequence, operation, filePath, content, date)
+	needed := int32(len(records))
+	if needed > capacity {
+		return fmt.Errorf("la entrada ocupa %d slots y el journal solo tiene %d", needed, capacity)
 	}
-	defer file.Close()
 
-	// Buscar la primera entrada vacía o el final del journaling
-	for i := int32(0); i < sb.SFreeInodesCount; i++ { // Usar SFreeInodesCount como límite para la búsqueda
-		offset := journalingStart + (int64(binary.Size(Journal{})) * int64(i))
-		journal := Journal{}
+	// Journal lleno: aplicar la política configurada
+	if sb.JournalUsed()+needed > capacity {
+		if sb.SJournalPolicy == JournalError {
+			return ErrJournalFull
+		}
 
-		file.Seek(offset, 0)
-		err := binary.Read(file, binary.LittleEndian, &journal)
+		sb.SJournalHead = sb.SJournalTail + needed - capacity
+		fmt.Println("El journaling está lleno, se sobrescribirán las entradas más antiguas.")
+	}
+
+	fmt.Printf("Usando índice de journaling: %d (slot %d, %d slots)\n", sequence, sequence%capacity, needed)
 
-		// Si hay error de lectura o la entrada está vacía, usar este índice
-		if err != nil || isEmptyJournal(journal) {
-			nextIndex = i
-			break
+	// Escribir los slots; forman parte de la transacción activa, si la hay.
+	// La secuencia crece siempre; el slot se obtiene con el módulo de la capacidad
+	for i, record := range records {
+		buf := new(bytes.Buffer)
+		err = binary.Write(buf, binary.LittleEndian, record)
+		if err != nil {
+			return fmt.Errorf("error al serializar el journal: %v", err)
 		}
 
-		// También comprobamos si hemos llegado al final del journaling
-		if i == sb.SFreeInodesCount-1 {
-			// Si llegamos al final, volver al principio (journaling circular)
-			nextIndex = 0
-			fmt.Println("El journaling está lleno, se sobrescribirá desde el principio.")
-			break
+		slot := (sequence + int32(i)) % capacity
+		offset := journalingStart + (int64(binary.Size(Journal{})) * int64(slot))
+		err = writeDisk(path, offset, buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("error al escribir el journal: %v", err)
 		}
 	}
 
-	fmt.Printf("Usando índice de journaling: %d\n", nextIndex)
+	// Avanzar el tail en el superbloque
+	sb.SJournalTail += needed
+	err = sb.SerializeSuperBlock(path, int32(partitionStart))
+	if err != nil {
+		return fmt.Errorf("error al actualizar el SuperBlock: %v", err)
+	}
 
-	// Crear una nueva entrada de Journal
+	fmt.Printf("Journal agregado exitosamente con índice %d\n", sequence)
+	return nil
+}
+
+// journalRecords divide una entrada en su slot principal (Journal) y los slots de
+// continuación necesarios para guardar la ruta y el contenido completos
+func journalRecords(sequence int32, operation, filePath, content string, date float32) []interface{} {
 	journal := Journal{
-		J_count: nextIndex,
+		J_count: sequence,
 		J_content: Information{
-			I_operation: [10]byte{},
-			I_path:      [100]byte{},
-			I_content:   [200]byte{},
-			I_date:      float32(time.Now().Unix()),
+			I_date: date,
 		},
 	}
 
-	// Copiar los valores a los arrays de bytes
+	// Copiar los valores a los arrays de bytes; lo que no cabe pasa a la continuación
 	copy(journal.J_content.I_operation[:], operation)
-	copy(journal.J_content.I_path[:], filePath)
-
-	// Si el contenido no está vacío, copiarlo
-	if content != "" {
-		copy(journal.J_content.I_content[:], content)
+	pathRest := []byte(filePath)[copy(journal.J_content.I_path[:], filePath):]
+	contentRest := []byte(content)[copy(journal.J_content.I_content[:], content):]
+
+	records := []interface{}{&journal}
+	for _, part := range []struct {
+		kind string
+		data []byte
+	}{{JournalPathCont, pathRest}, {JournalContentCont, contentRest}} {
+		for len(part.data) > 0 {
+			cont := &JournalContinuation{
+				C_count: sequence + int32(len(records)),
+				C_head:  sequence,
+			}
+			copy(cont.C_kind[:], part.kind)
+			cont.C_length = int32(copy(cont.C_data[:], part.data))
+			part.data = part.data[cont.C_length:]
+			records = append(records, cont)
+		}
 	}
 
-	// Serializar la entrada de Journal en el archivo
-	writeFile, err := os.OpenFile(path, os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("error al abrir el archivo para escritura: %v", err)
-	}
-	defer writeFile.Close()
+	return records
+}
 
-	offset := journalingStart + (int64(binary.Size(Journal{})) * int64(nextIndex))
-	writeFile.Seek(offset, 0)
+// GetJournaling devuelve las entradas vivas del journal circular, desde head
+// hasta tail, en el orden en que fueron registradas. Cada entrada se reensambla
+// con sus slots de continuación; las continuaciones cuya entrada principal ya fue
+// sobrescrita se descartan
+func GetJournaling(path string, partitionStart int32) ([]JournalEntry, error) {
+	var entries []JournalEntry
 
-	err = binary.Write(writeFile, binary.LittleEndian, &journal)
+	sb := &SuperBlock{}
+	err := sb.DeserializeSuperBlock(path, partitionStart)
 	if err != nil {
-		return fmt.Errorf("error al escribir el journal: %v", err)
+		return nil, fmt.Errorf("error al leer el SuperBlock: %v", err)
 	}
 
-	fmt.Printf("Journal agregado exitosamente con índice %d\n", nextIndex)
-	return nil
-}
-
-func GetJournaling(path string, journalingStart int64, journalCount int32) ([]Journal, error) {
-	var journals []Journal
+	journalingStart, capacity := sb.journalArea(partitionStart)
+	if capacity <= 0 {
+		return entries, nil
+	}
 
 	// Abrir el archivo en modo lectura
-	file, err := os.Open(path)
+	file, err := OpenDisk(path, os.O_RDONLY)
 	if err != nil {
 		return nil, fmt.Errorf("error al abrir el archivo: %v", err)
 	}
 	defer file.Close()
 
-	// Leer cada entrada de Journal
-	for i := int32(0); i < journalCount; i++ {
-		offset := journalingStart + (int64(binary.Size(Journal{})) * int64(i))
-		journal := Journal{}
+	// Leer cada slot vivo del Journal
+	buffer := make([]byte, binary.Size(Journal{}))
+	var current *JournalEntry
+	for sequence := sb.SJournalHead; sequence < sb.SJournalTail; sequence++ {
+		offset := journalingStart + (int64(len(buffer)) * int64(sequence%capacity))
 
-		// Mover el puntero del archivo a la posición especificada
-		_, err := file.Seek(offset, 0)
+		_, err := file.ReadAt(buffer, offset)
 		if err != nil {
-			return nil, fmt.Errorf("error al mover el puntero del archivo: %v", err)
+			return nil, fmt.Errorf("error al leer el journal: %v", err)
 		}
 
-		// Deserializar la estructura Journal
-		err = binary.Read(file, binary.LittleEndian, &journal)
+		journal := Journal{}
+		err = binary.Read(bytes.NewReader(buffer), binary.LittleEndian, &journal)
 		if err != nil {
 			return nil, fmt.Errorf("error al leer el journal: %v", err)
 		}
 
-		// Solo añadir journales que tengan operaciones registradas
-		// (para filtrar entradas vacías o no inicializadas)
-		if !isEmptyJournal(journal) {
-			journals = append(journals, journal)
+		// Filtrar slots vacíos, sobrescritos o con registros de transacciones
+		if journal.J_count != sequence || isEmptyJournal(journal) || isTxRecord(journal) {
+			current = nil
+			continue
+		}
+
+		kind := string(bytes.TrimRight(journal.J_content.I_operation[:], "\x00"))
+		if kind == JournalPathCont || kind == JournalContentCont {
+			cont := JournalContinuation{}
+			err = binary.Read(bytes.NewReader(buffer), binary.LittleEndian, &cont)
+			if err != nil || current == nil || cont.C_head != current.Sequence {
+				continue
+			}
+
+			length := min(max(cont.C_length, 0), journalContDataSize)
+			if kind == JournalPathCont {
+				current.Path += string(cont.C_data[:length])
+			} else {
+				current.Content += string(cont.C_data[:length])
+			}
+			continue
+		}
+
+		entries = append(entries, JournalEntry{
+			Sequence:  journal.J_count,
+			Operation: kind,
+			Path:      string(bytes.TrimRight(journal.J_content.I_path[:], "\x00")),
+			Content:   string(bytes.TrimRight(journal.J_content.I_content[:], "\x00")),
+			Date:      time.Unix(int64(journal.J_content.I_date), 0),
+		})
+		current = &entries[len(entries)-1]
+	}
+
+	return entries, nil
+}
+
+// CheckpointJournal recupera todas las entradas vivas del journal: sus cambios
+// ya están aplicados en el disco, así que se limpian sus slots y head alcanza a tail.
+// Devuelve la cantidad de entradas recuperadas.
+func CheckpointJournal(path string, partitionStart int32) (int32, error) {
+	sb := &SuperBlock{}
+	err := sb.DeserializeSuperBlock(path, partitionStart)
+	if err != nil {
+		return 0, fmt.Errorf("error al leer el SuperBlock: %v", err)
+	}
+
+	journalingStart, capacity := sb.journalArea(partitionStart)
+	if capacity <= 0 {
+		return 0, fmt.Errorf("la partición no tiene área de journaling")
+	}
+
+	reclaimed := sb.JournalUsed()
+	if reclaimed > capacity {
+		reclaimed = capacity
+	}
+
+	for i := int32(0); i < reclaimed; i++ {
+		slot := (sb.SJournalTail - reclaimed + i) % capacity
+
+		buf := new(bytes.Buffer)
+		err = binary.Write(buf, binary.LittleEndian, &Journal{J_count: slot})
+		if err != nil {
+			return 0, err
+		}
+
+		err = writeDisk(path, journalingStart+int64(buf.Len())*int64(slot), buf.Bytes())
+		if err != nil {
+			return 0, fmt.Errorf("error al limpiar el journal: %v", err)
 		}
 	}
 
-	return journals, nil
+	sb.SJournalHead = sb.SJournalTail
+	err = sb.SerializeSuperBlock(path, partitionStart)
+	if err != nil {
+		return 0, fmt.Errorf("error al actualizar el SuperBlock: %v", err)
+	}
+
+	return reclaimed, nil
+}
+
+// SetJournalPolicy cambia la política que se aplica cuando el journal está lleno
+func SetJournalPolicy(path string, partitionStart int32, policy int32) error {
+	sb := &SuperBlock{}
+	err := sb.DeserializeSuperBlock(path, partitionStart)
+	if err != nil {
+		return fmt.Errorf("error al leer el SuperBlock: %v", err)
+	}
+
+	sb.SJournalPolicy = policy
+	return sb.SerializeSuperBlock(path, partitionStart)
 }
 
 // isEmptyJournal verifica si un journal está vacío (no tiene operación registrada)
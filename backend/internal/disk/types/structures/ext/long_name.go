@@ -0,0 +1,155 @@
+package ext2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxNameLength es el largo máximo de un nombre de archivo o carpeta
+	MaxNameLength = 255
+
+	// shortNameLength es el largo que cabe directamente en DirContent.BName
+	shortNameLength = 12
+
+	// longNameMarker identifica una entrada cuyo nombre está en bloques de nombre.
+	// Formato de BName: [marcador][bloque int32][prefijo de 7 bytes]
+	longNameMarker byte = 0x01
+	longNamePrefix      = 5
+)
+
+// isLongName indica si la entrada guarda su nombre en bloques de nombre
+func (entry *DirContent) isLongName() bool {
+	return entry.BName[0] == longNameMarker
+}
+
+// nameBlockIndex devuelve el primer bloque de nombre de una entrada larga
+func (entry *DirContent) nameBlockIndex() int32 {
+	return int32(binary.LittleEndian.Uint32(entry.BName[1:longNamePrefix]))
+}
+
+// EntryName devuelve el nombre completo de una entrada de directorio.
+// Las entradas antiguas de 12 bytes se leen tal cual.
+func (sb *SuperBlock) EntryName(path string, entry DirContent) string {
+	if !entry.isLongName() {
+		return strings.Trim(string(entry.BName[:]), "\x00")
+	}
+
+	var name strings.Builder
+	blockIndex := entry.nameBlockIndex()
+
+	// Como máximo se recorren los bloques necesarios para MaxNameLength
+	for i := 0; blockIndex != -1 && i*len(NameBlock{}.BName) < MaxNameLength; i++ {
+		nameBlock := &NameBlock{}
+		err := nameBlock.Deserialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
+		if err != nil {
+			// Si no se puede leer el bloque, se devuelve el prefijo guardado en la entrada
+			return strings.Trim(string(entry.BName[longNamePrefix:]), "\x00")
+		}
+
+		name.WriteString(strings.TrimRight(string(nameBlock.BName[:]), "\x00"))
+		blockIndex = nameBlock.BNext
+	}
+
+	return name.String()
+}
+
+// setEntryName guarda el nombre en la entrada, reservando bloques de nombre si no
+// cabe en los 12 bytes de BName. Debe llamarse antes de calcular el siguiente
+// bloque libre para otras estructuras, ya que avanza SFirstBlo.
+func (sb *SuperBlock) setEntryName(path string, entry *DirContent, name string) error {
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("el nombre '%s' excede el máximo de %d caracteres", name, MaxNameLength)
+	}
+
+	entry.BName = [12]byte{}
+	if len(name) <= shortNameLength {
+		copy(entry.BName[:], name)
+		return nil
+	}
+
+	chunkSize := len(NameBlock{}.BName)
+	chunks := (len(name) + chunkSize - 1) / chunkSize
+	if sb.SFreeBlocksCount < int32(chunks) {
+		return fmt.Errorf("no hay bloques libres para guardar el nombre '%s'", name)
+	}
+
+	// Cada bloque se escribe después de reservar el siguiente, para enlazarlo
+	// con el índice que realmente se reservó
+	firstBlockIndex, err := sb.allocateBlock(path)
+	if err != nil {
+		return err
+	}
+
+	blockIndex := firstBlockIndex
+	for i := 0; i < chunks; i++ {
+		nameBlock := &NameBlock{BNext: -1}
+		if i < chunks-1 {
+			nameBlock.BNext, err = sb.allocateBlock(path)
+			if err != nil {
+				return err
+			}
+		}
+		copy(nameBlock.BName[:], name[i*chunkSize:])
+
+		err = nameBlock.Serialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
+		if err != nil {
+			return fmt.Errorf("error al escribir el bloque de nombre: %v", err)
+		}
+
+		blockIndex = nameBlock.BNext
+	}
+
+	entry.BName[0] = longNameMarker
+	binary.LittleEndian.PutUint32(entry.BName[1:longNamePrefix], uint32(firstBlockIndex))
+	copy(entry.BName[longNamePrefix:], name)
+
+	return nil
+}
+
+// freeEntryName libera los bloques de nombre de una entrada larga
+func (sb *SuperBlock) freeEntryName(path string, entry DirContent) error {
+	if !entry.isLongName() {
+		return nil
+	}
+
+	blockIndex := entry.nameBlockIndex()
+	for i := 0; blockIndex != -1 && i*len(NameBlock{}.BName) < MaxNameLength; i++ {
+		nameBlock := &NameBlock{}
+		err := nameBlock.Deserialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
+		if err != nil {
+			return fmt.Errorf("error al leer el bloque de nombre: %v", err)
+		}
+
+		err = sb.freeBlock(path, blockIndex)
+		if err != nil {
+			return err
+		}
+
+		blockIndex = nameBlock.BNext
+	}
+
+	return nil
+}
+
+// nameBlocks devuelve los bloques de nombre usados por una entrada
+func (sb *SuperBlock) nameBlocks(path string, entry DirContent) []int32 {
+	if !entry.isLongName() {
+		return nil
+	}
+
+	var blocks []int32
+	blockIndex := entry.nameBlockIndex()
+	for i := 0; sb.isValidBlock(blockIndex) && i*len(NameBlock{}.BName) < MaxNameLength; i++ {
+		blocks = append(blocks, blockIndex)
+
+		nameBlock := &NameBlock{}
+		if err := nameBlock.Deserialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS))); err != nil {
+			break
+		}
+		blockIndex = nameBlock.BNext
+	}
+
+	return blocks
+}
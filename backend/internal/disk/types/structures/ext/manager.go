@@ -3,7 +3,6 @@ package ext2
 import (
 	"fmt"
 	"math"
-	"os"
 	"strings"
 	"time"
 
@@ -32,18 +31,7 @@ func (sb *SuperBlock) CreateUsersFile(path string) error {
 	}
 
 	// Actualizar el bitmap de inodos para marcar el primer inodo como usado
-	file, err := os.OpenFile(path, os.O_WRONLY, 0666)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Marcar como usado el primer inodo en el bitmap
-	_, err = file.Seek(int64(sb.SBmInodeStart), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	err = writeDisk(path, int64(sb.SBmInodeStart), []byte{1}) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -70,11 +58,7 @@ func (sb *SuperBlock) CreateUsersFile(path string) error {
 	}
 
 	// Marcar como usado el primer bloque en el bitmap
-	_, err = file.Seek(int64(sb.SBmBlockStart), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	err = writeDisk(path, int64(sb.SBmBlockStart), []byte{1}) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -126,11 +110,7 @@ func (sb *SuperBlock) CreateUsersFile(path string) error {
 	}
 
 	// Marcar como usado el segundo inodo en el bitmap
-	_, err = file.Seek(int64(sb.SBmInodeStart+1), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	err = writeDisk(path, int64(sb.SBmInodeStart+1), []byte{1}) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -166,11 +146,7 @@ func (sb *SuperBlock) CreateUsersFile(path string) error {
 	}
 
 	// Marcar como usado el segundo bloque en el bitmap
-	_, err = file.Seek(int64(sb.SBmBlockStart+1), 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{1}) // 1 = usado
+	err = writeDisk(path, int64(sb.SBmBlockStart+1), []byte{1}) // 1 = usado
 	if err != nil {
 		return err
 	}
@@ -204,6 +180,10 @@ func (sb *SuperBlock) createFolderInInode(
 	uid int32,
 	gid int32,
 ) error {
+	if len(destDir) > MaxNameLength {
+		return fmt.Errorf("el nombre '%s' excede el máximo de %d caracteres", destDir, MaxNameLength)
+	}
+
 	inode := &INode{}
 
 	err := inode.Deserialize(path, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
@@ -252,7 +232,7 @@ func (sb *SuperBlock) createFolderInInode(
 					continue
 				}
 
-				entryName := strings.Trim(string(content.BName[:]), "\x00")
+				entryName := sb.EntryName(path, content)
 				parentDirStr := strings.Trim(parentDir, "\x00")
 
 				if strings.EqualFold(entryName, parentDirStr) {
@@ -303,7 +283,7 @@ func (sb *SuperBlock) createFolderInInode(
 							continue
 						}
 
-						entryName := strings.Trim(string(content.BName[:]), "\x00")
+						entryName := sb.EntryName(path, content)
 						parentDirStr := strings.Trim(parentDir, "\x00")
 
 						if strings.EqualFold(entryName, parentDirStr) {
@@ -364,7 +344,7 @@ func (sb *SuperBlock) createFolderInInode(
 					continue
 				}
 
-				entryName := strings.Trim(string(content.BName[:]), "\x00")
+				entryName := sb.EntryName(path, content)
 				if strings.EqualFold(entryName, destDir) {
 					// El directorio ya existe
 					return fmt.Errorf("el directorio '%s' ya existe", destDir)
@@ -378,6 +358,13 @@ func (sb *SuperBlock) createFolderInInode(
 		// Buscar un bloque con espacio libre
 		for i, blockIndex := range inode.IBlock {
 			if blockIndex == -1 {
+				// Reservar primero el nombre, ya que puede ocupar bloques de nombre
+				entry := DirContent{}
+				err = sb.setEntryName(path, &entry, destDir)
+				if err != nil {
+					return err
+				}
+
 				// Encontramos un espacio para nuevo bloque
 				// Crear nuevo bloque de directorio
 				newBlockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
@@ -397,11 +384,10 @@ func (sb *SuperBlock) createFolderInInode(
 					BContent: [4]DirContent{
 						{BName: [12]byte{'.'}, BInodo: inodeIndex},
 						{BName: [12]byte{'.', '.'}, BInodo: inodeIndex},
-						{BName: [12]byte{}, BInodo: newDirInodeIndex},
+						{BName: entry.BName, BInodo: newDirInodeIndex},
 						{BName: [12]byte{'-'}, BInodo: -1},
 					},
 				}
-				copy(dirBlock.BContent[2].BName[:], destDir)
 
 				// Escribir el bloque de directorio
 				err = dirBlock.Serialize(path, int64(sb.SBlockStart+(newBlockIndex*sb.SBlockS)))
@@ -499,7 +485,10 @@ func (sb *SuperBlock) createFolderInInode(
 						newDirInodeIndex := sb.SInodesCount
 
 						// Actualizar la entrada en el bloque directorio
-						copy(dirBlock.BContent[j].BName[:], destDir)
+						err = sb.setEntryName(path, &dirBlock.BContent[j], destDir)
+						if err != nil {
+							return err
+						}
 						dirBlock.BContent[j].BInodo = newDirInodeIndex
 
 						// Escribir el bloque actualizado
@@ -693,7 +682,7 @@ func (sb *SuperBlock) CreateFile(
 					continue
 				}
 
-				entryName := strings.Trim(string(entry.BName[:]), "\x00")
+				entryName := sb.EntryName(path, entry)
 				fmt.Printf("Comparando entrada '%s' con '%s'\n", entryName, parentDir)
 
 				if strings.EqualFold(entryName, parentDir) {
@@ -748,7 +737,7 @@ func (sb *SuperBlock) CreateFile(
 						continue
 					}
 
-					entryName := strings.Trim(string(entry.BName[:]), "\x00")
+					entryName := sb.EntryName(path, entry)
 					if strings.EqualFold(entryName, parentDir) {
 						foundInodeIndex = entry.BInodo
 						fmt.Printf("¡Encontrado directorio recién creado '%s' en inodo %d!\n", parentDir, foundInodeIndex)
@@ -792,6 +781,10 @@ func (sb *SuperBlock) createFileInInode(
 	uid int32,
 	gid int32,
 ) error {
+	if len(destFile) > MaxNameLength {
+		return fmt.Errorf("el nombre '%s' excede el máximo de %d caracteres", destFile, MaxNameLength)
+	}
+
 	// Obtener el inodo donde crearemos el archivo
 	inode := &INode{}
 	err := inode.Deserialize(path, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
@@ -829,7 +822,7 @@ func (sb *SuperBlock) createFileInInode(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			if strings.EqualFold(entryName, destFile) {
 				return fmt.Errorf("el archivo '%s' ya existe en este directorio", destFile)
 			}
@@ -1271,21 +1264,25 @@ func (sb *SuperBlock) createFileInInode(
 		if blockIndex == -1 {
 			fmt.Printf("Creando nuevo bloque de directorio para la entrada de archivo\n")
 
+			// Reservar primero el nombre, ya que puede ocupar bloques de nombre
+			entry := DirContent{BInodo: fileInodeIndex}
+			err := sb.setEntryName(path, &entry, destFile)
+			if err != nil {
+				return err
+			}
+
 			blockIndex = (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
 			newBlock := &DirBlock{
 				BContent: [4]DirContent{
 					{BName: [12]byte{'.'}, BInodo: inodeIndex},
 					{BName: [12]byte{'.', '.'}, BInodo: inodeIndex},
-					{BName: [12]byte{}, BInodo: fileInodeIndex}, // Entrada para nuestro nuevo archivo
+					entry, // Entrada para nuestro nuevo archivo
 					{BName: [12]byte{'-'}, BInodo: -1},
 				},
 			}
 
-			// Copiar el nombre del archivo
-			copy(newBlock.BContent[2].BName[:], destFile)
-
 			// Escribir el bloque
-			err := newBlock.Serialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
+			err = newBlock.Serialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
 			if err != nil {
 				return fmt.Errorf("error al serializar bloque de directorio: %v", err)
 			}
@@ -1325,7 +1322,10 @@ func (sb *SuperBlock) createFileInInode(
 				fmt.Printf("Usando entrada libre %d en bloque %d\n", i, blockIndex)
 
 				// Actualizar la entrada con el archivo
-				copy(dirBlock.BContent[i].BName[:], destFile)
+				err = sb.setEntryName(path, &dirBlock.BContent[i], destFile)
+				if err != nil {
+					return err
+				}
 				dirBlock.BContent[i].BInodo = fileInodeIndex
 
 				// Escribir el bloque actualizado
@@ -1564,7 +1564,7 @@ func (sb *SuperBlock) FindFileInode(
 					continue
 				}
 
-				entryName := strings.Trim(string(entry.BName[:]), "\x00")
+				entryName := sb.EntryName(path, entry)
 				if strings.EqualFold(entryName, parentDir) {
 					currentInodeIndex = entry.BInodo
 					found = true
@@ -1603,7 +1603,7 @@ func (sb *SuperBlock) FindFileInode(
 						continue
 					}
 
-					entryName := strings.Trim(string(entry.BName[:]), "\x00")
+					entryName := sb.EntryName(path, entry)
 					if strings.EqualFold(entryName, parentDir) {
 						currentInodeIndex = entry.BInodo
 						found = true
@@ -1653,7 +1653,7 @@ func (sb *SuperBlock) FindFileInode(
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			if strings.EqualFold(entryName, fileName) {
 				return entry.BInodo, nil
 			}
@@ -1686,7 +1686,7 @@ func (sb *SuperBlock) FindFileInode(
 					continue
 				}
 
-				entryName := strings.Trim(string(entry.BName[:]), "\x00")
+				entryName := sb.EntryName(path, entry)
 				if strings.EqualFold(entryName, fileName) {
 					return entry.BInodo, nil
 				}
@@ -1719,182 +1719,45 @@ func (sb *SuperBlock) UpdateFile(
 		return fmt.Errorf("'%s' no es un archivo", fileName)
 	}
 
-	// 3. Liberar todos los bloques asignados a este inodo
-	for i := 0; i < 12; i++ {
-		blockIndex := fileInode.IBlock[i]
-		if blockIndex == -1 {
-			continue
-		}
+	// 3. Verificar que el nuevo contenido quepa antes de liberar los bloques actuales
+	err = sb.checkFileSpace(int64(len(newContent)))
+	if err != nil {
+		return err
+	}
 
-		// Marcar el bloque como libre en el bitmap
-		file, err := os.OpenFile(path, os.O_WRONLY, 0666)
+	// 4. Liberar todos los bloques asignados a este inodo, incluidos los de punteros
+	refs, err := sb.InodeBlockList(path, fileInode)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		err = sb.freeBlock(path, ref.Block)
 		if err != nil {
 			return err
 		}
-		defer file.Close()
+	}
+	for i := range fileInode.IBlock {
+		fileInode.IBlock[i] = -1
+	}
 
-		_, err = file.Seek(int64(sb.SBmBlockStart+(blockIndex*sb.SBlockS)), 0)
-		if err != nil {
-			return err
-		}
-		_, err = file.Write([]byte{0}) // 0 = libre
-		if err != nil {
-			return err
-		}
+	fmt.Printf("Actualizando archivo con contenido de %d bytes\n", len(newContent))
 
-		// Actualizar contadores
-		sb.SBlocksCount--
-		sb.SFreeBlocksCount++
+	// 5. Reescribir el nuevo contenido en bloques nuevos
+	err = sb.fillFileBlocks(path, fileInode, strings.NewReader(newContent), int64(len(newContent)))
+	if err != nil {
+		return err
 	}
 
-	// 4. Reescribir nuevo contenido y reasignar bloques (similar a createFile)
-	fileInode.ISize = int32(len(newContent))
 	fileInode.IMtime = float32(time.Now().Unix())
 	fileInode.IAtime = float32(time.Now().Unix())
 
-	// Calcular cuántos bloques necesitamos para el contenido
-	contentSize := len(newContent)
-	blocksNeeded := int(math.Ceil(float64(contentSize) / float64(FileBlockSize)))
-
-	fmt.Printf("Actualizando archivo con contenido de %d bytes, necesitando %d bloques\n", contentSize, blocksNeeded)
-
-	// Asignar bloques para el contenido
-	contentOffset := 0
-
-	// Primero asignamos los bloques directos (0-11)
-	for i := 0; i < blocksNeeded && i < 12; i++ {
-		// Crear un nuevo bloque de archivo
-		fileBlock := &FileBlock{
-			BContent: [FileBlockSize]byte{},
-		}
-
-		// Copiar parte del contenido a este bloque
-		remainingBytes := contentSize - contentOffset
-		bytesToCopy := FileBlockSize
-		if remainingBytes < FileBlockSize {
-			bytesToCopy = remainingBytes
-		}
-
-		copy(fileBlock.BContent[:bytesToCopy], newContent[contentOffset:contentOffset+bytesToCopy])
-		contentOffset += bytesToCopy
-
-		// Asignar y escribir el bloque
-		blockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
-		fileInode.IBlock[i] = blockIndex
-
-		err := fileBlock.Serialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
-		if err != nil {
-			return fmt.Errorf("error al serializar bloque de archivo %d: %v", blockIndex, err)
-		}
-
-		// Actualizar bitmap de bloques y contadores
-		err = sb.UpdateBitmapBlock(path)
-		if err != nil {
-			return err
-		}
-
-		sb.SBlocksCount++
-		sb.SFreeBlocksCount--
-		sb.SFirstBlo += sb.SBlockS
-
-		fmt.Printf("Bloque #%d asignado con %d bytes de contenido\n", blockIndex, bytesToCopy)
-	}
-
-	// Si necesitamos más bloques, usar el bloque indirecto simple (posición 12)
-	if blocksNeeded > 12 {
-		// Crear bloque de punteros indirectos
-		pointerBlock := &PointerBlock{}
-		for i := range pointerBlock.PContent {
-			pointerBlock.PContent[i] = -1 // Inicializar como vacíos
-		}
-
-		// Asignar bloque para los punteros
-		pointerBlockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
-		fileInode.IBlock[12] = pointerBlockIndex
-
-		// Escribir el bloque de punteros
-		err := pointerBlock.Serialize(path, int64(sb.SBlockStart+(pointerBlockIndex*sb.SBlockS)))
-		if err != nil {
-			return fmt.Errorf("error al serializar bloque de punteros: %v", err)
-		}
-
-		// Actualizar bitmap de bloques
-		err = sb.UpdateBitmapBlock(path)
-		if err != nil {
-			return err
-		}
-
-		// Actualizar bitmap y contadores
-		err = sb.UpdateBitmapBlock(path)
-		if err != nil {
-			return err
-		}
-
-		sb.SBlocksCount++
-		sb.SFreeBlocksCount--
-		sb.SFirstBlo += sb.SBlockS
-
-		fmt.Printf("Bloque de punteros #%d asignado para bloques indirectos\n", pointerBlockIndex)
-
-		// Asignar bloques indirectos mediante el bloque de punteros
-		for i := 0; i < blocksNeeded-12 && i < len(pointerBlock.PContent); i++ {
-			// Crear un nuevo bloque de archivo
-			fileBlock := &FileBlock{
-				BContent: [FileBlockSize]byte{},
-			}
-
-			// Copiar parte del contenido a este bloque
-			remainingBytes := contentSize - contentOffset
-			bytesToCopy := FileBlockSize
-			if remainingBytes < FileBlockSize {
-				bytesToCopy = remainingBytes
-			}
-
-			copy(fileBlock.BContent[:bytesToCopy], newContent[contentOffset:contentOffset+bytesToCopy])
-			contentOffset += bytesToCopy
-
-			// Asignar y escribir el bloque
-			blockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
-			pointerBlock.PContent[i] = blockIndex
-
-			err := fileBlock.Serialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
-			if err != nil {
-				return fmt.Errorf("error al serializar bloque indirecto %d: %v", blockIndex, err)
-			}
-
-			// Actualizar bitmap de bloques
-			err = sb.UpdateBitmapBlock(path)
-			if err != nil {
-				return err
-			}
-
-			// Actualizar bitmap y contadores
-			err = sb.UpdateBitmapBlock(path)
-			if err != nil {
-				return err
-			}
-
-			sb.SBlocksCount++
-			sb.SFreeBlocksCount--
-			sb.SFirstBlo += sb.SBlockS
-
-			fmt.Printf("Bloque indirecto #%d asignado con %d bytes de contenido\n", blockIndex, bytesToCopy)
-		}
-
-		// Actualizar el bloque de punteros con los nuevos valores
-		err = pointerBlock.Serialize(path, int64(sb.SBlockStart+(pointerBlockIndex*sb.SBlockS)))
-		if err != nil {
-			return fmt.Errorf("error al actualizar bloque de punteros: %v", err)
-		}
-	}
-
 	// Actualizar y serializar inodo
-	err = fileInode.Serialize(path, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
-	if err != nil {
-		return err
-	}
+	return fileInode.Serialize(path, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
+}
 
-	return nil
+// CanWrite indica si un usuario puede escribir en un inodo, con las mismas reglas que mkfile y mkdir
+func (sb *SuperBlock) CanWrite(inode *INode, uid int32, gid int32) bool {
+	return sb.userHasWritePermission(inode, uid, gid)
 }
 
 func (sb *SuperBlock) userHasWritePermission(inode *INode, uid int32, gid int32) bool {
@@ -2,7 +2,6 @@ package ext2
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 )
@@ -137,6 +136,11 @@ func (sb *SuperBlock) Move(
 				return fmt.Errorf("error al eliminar directorio origen después de copiar: %v", err)
 			}
 
+			err = sb.freeInode(path, sourceInodeIndex)
+			if err != nil {
+				return fmt.Errorf("error al liberar el inodo del directorio origen: %v", err)
+			}
+
 			// Eliminar la entrada del directorio en el padre
 			err = sb.removeDirectoryEntry(path, sourceParentInodeIndex, sourceName)
 			if err != nil {
@@ -238,44 +242,21 @@ func (sb *SuperBlock) removeFileOrDirectory(path string, parentInodeIndex, inode
 	return sb.removeDirectoryEntry(path, parentInodeIndex, name)
 }
 
-// freeFileBlocks libera los bloques ocupados por un archivo
+// freeFileBlocks libera los bloques ocupados por un archivo, incluidos los
+// bloques de punteros de los niveles indirectos
 func (sb *SuperBlock) freeFileBlocks(path string, inode *INode) error {
-	// Liberar bloques directos
-	for i := 0; i < 12; i++ {
-		if inode.IBlock[i] != -1 {
-			err := sb.freeBlock(path, inode.IBlock[i])
-			if err != nil {
-				return err
-			}
-		}
+	refs, err := sb.InodeBlockList(path, inode)
+	if err != nil {
+		return err
 	}
 
-	// Liberar bloques indirectos simples
-	if inode.IBlock[12] != -1 {
-		pointerBlock := &PointerBlock{}
-		err := pointerBlock.Deserialize(path, int64(sb.SBlockStart+(inode.IBlock[12]*sb.SBlockS)))
-		if err != nil {
-			return err
-		}
-
-		for _, ptr := range pointerBlock.PContent {
-			if ptr != -1 {
-				err := sb.freeBlock(path, ptr)
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		// Liberar el bloque de punteros
-		err = sb.freeBlock(path, inode.IBlock[12])
+	for _, ref := range refs {
+		err = sb.freeBlock(path, ref.Block)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Aquí se podrían agregar bloques indirectos dobles y triples si fuera necesario
-
 	return nil
 }
 
@@ -283,18 +264,8 @@ func (sb *SuperBlock) freeFileBlocks(path string, inode *INode) error {
 func (sb *SuperBlock) freeBlock(path string, blockIndex int32) error {
 	bitmapOffset := int64(sb.SBmBlockStart + blockIndex)
 
-	file, err := sb.openPartition(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
 	// Marcar el bloque como libre (0)
-	_, err = file.Seek(bitmapOffset, 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{0})
+	err := writeDisk(path, bitmapOffset, []byte{0})
 	if err != nil {
 		return err
 	}
@@ -307,18 +278,8 @@ func (sb *SuperBlock) freeBlock(path string, blockIndex int32) error {
 func (sb *SuperBlock) freeInode(path string, inodeIndex int32) error {
 	bitmapOffset := int64(sb.SBmInodeStart + inodeIndex)
 
-	file, err := sb.openPartition(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
 	// Marcar el inodo como libre (0)
-	_, err = file.Seek(bitmapOffset, 0)
-	if err != nil {
-		return err
-	}
-	_, err = file.Write([]byte{0})
+	err := writeDisk(path, bitmapOffset, []byte{0})
 	if err != nil {
 		return err
 	}
@@ -354,7 +315,7 @@ func (sb *SuperBlock) removeDirectoryContents(path string, dirInodeIndex int32)
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			if entryName == "." || entryName == ".." {
 				continue
 			}
@@ -387,9 +348,15 @@ func (sb *SuperBlock) removeDirectoryContents(path string, dirInodeIndex int32)
 				return err
 			}
 
+			// Liberar los bloques del nombre si es un nombre largo
+			err = sb.freeEntryName(path, entry)
+			if err != nil {
+				return err
+			}
+
 			// Marcar la entrada como libre
 			dirBlock.BContent[j].BInodo = -1
-			copy(dirBlock.BContent[j].BName[:], "-")
+			dirBlock.BContent[j].BName = [12]byte{'-'}
 		}
 
 		// Actualizar el bloque de directorio
@@ -441,11 +408,17 @@ func (sb *SuperBlock) removeDirectoryEntry(path string, dirInodeIndex int32, ent
 				continue
 			}
 
-			currentName := strings.Trim(string(entry.BName[:]), "\x00")
+			currentName := sb.EntryName(path, entry)
 			if strings.EqualFold(currentName, entryName) {
+				// Liberar los bloques del nombre si es un nombre largo
+				err = sb.freeEntryName(path, entry)
+				if err != nil {
+					return err
+				}
+
 				// Marcar la entrada como libre
 				dirBlock.BContent[j].BInodo = -1
-				copy(dirBlock.BContent[j].BName[:], "-")
+				dirBlock.BContent[j].BName = [12]byte{'-'}
 
 				// Actualizar el bloque de directorio
 				err = dirBlock.Serialize(path, int64(sb.SBlockStart+(dirInode.IBlock[i]*sb.SBlockS)))
@@ -475,8 +448,3 @@ func (sb *SuperBlock) GetInodeByNumber(diskPath string, inodeIndex int32) (*INod
 	err := inode.Deserialize(diskPath, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
 	return inode, err
 }
-
-// openPartition abre el archivo de la partición para escritura
-func (sb *SuperBlock) openPartition(path string) (*os.File, error) {
-	return os.OpenFile(path, os.O_RDWR, 0666)
-}
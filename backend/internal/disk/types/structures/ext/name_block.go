@@ -0,0 +1,61 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	NameBlockSize = 64
+)
+
+// NameBlock guarda un fragmento de un nombre largo de archivo o carpeta.
+// Los bloques se encadenan con BNext hasta completar el nombre.
+type NameBlock struct {
+	BName [60]byte // Fragmento del nombre
+	BNext int32    // Siguiente bloque de nombre (-1 si es el último)
+}
+
+// Serialize escribe la estructura NameBlock en un archivo binario en la posición especificada
+func (nb *NameBlock) Serialize(path string, offset int64) error {
+	// Serializar la estructura NameBlock en un buffer
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, nb)
+	if err != nil {
+		return err
+	}
+
+	// Escribir el buffer en el disco (queda pendiente si hay una transacción activa)
+	return writeDisk(path, offset, buf.Bytes())
+}
+
+// Deserialize lee la estructura NameBlock desde un archivo binario en la posición especificada
+func (nb *NameBlock) Deserialize(path string, offset int64) error {
+	// Obtener el tamaño de la estructura NameBlock
+	nbSize := binary.Size(nb)
+	if nbSize <= 0 {
+		return fmt.Errorf("invalid NameBlock size: %d", nbSize)
+	}
+
+	// Leer solo la cantidad de bytes que corresponden al tamaño de la estructura
+	buffer, err := readDisk(path, offset, nbSize)
+	if err != nil {
+		return err
+	}
+
+	// Deserializar los bytes leídos en la estructura NameBlock
+	reader := bytes.NewReader(buffer)
+	err = binary.Read(reader, binary.LittleEndian, nb)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Print imprime el fragmento de nombre y el siguiente bloque
+func (nb *NameBlock) Print() {
+	fmt.Printf("B_name: %s\n", bytes.Trim(nb.BName[:], "\x00"))
+	fmt.Printf("B_next: %d\n", nb.BNext)
+}
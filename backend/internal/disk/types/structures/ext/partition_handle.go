@@ -0,0 +1,313 @@
+package ext2
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	handlePageSize = 4096 // Tamaño de las páginas de la caché
+	handleMaxPages = 4096 // Páginas en caché antes de vaciarla (16 MiB)
+)
+
+// DiskFile es el acceso directo a los bytes del disco que usan las estructuras.
+// Lo implementan tanto *os.File como el handle de una partición montada
+type DiskFile interface {
+	io.ReaderAt
+	io.WriterAt
+	Sync() error
+	Close() error
+}
+
+// cachePage es una página del disco en memoria junto con el rango de bytes
+// modificados que todavía no se escriben en el archivo
+type cachePage struct {
+	data      []byte
+	dirtyFrom int
+	dirtyTo   int // dirtyFrom == dirtyTo si la página no tiene cambios
+}
+
+// PartitionHandle mantiene abierto el archivo del disco de una partición
+// montada y guarda en caché las páginas leídas, de modo que leer inodos y
+// bloques no abre el archivo en cada estructura. Las escrituras quedan en la
+// caché hasta Sync (al terminar cada operación) o hasta desmontar.
+// Las particiones montadas de un mismo disco comparten el handle.
+type PartitionHandle struct {
+	path  string
+	mutex sync.Mutex
+	file  *os.File
+	info  os.FileInfo // Estado del archivo después de la última sincronización
+	size  int64       // Tamaño del archivo incluyendo las escrituras pendientes
+	pages map[int64]*cachePage
+}
+
+var (
+	handlesMutex sync.Mutex
+	handles      = map[string]*PartitionHandle{} // Handle abierto por archivo de disco
+)
+
+// OpenPartition abre el handle del disco o retorna el que ya está abierto
+func OpenPartition(path string) (*PartitionHandle, error) {
+	handlesMutex.Lock()
+	defer handlesMutex.Unlock()
+
+	key := filepath.Clean(path)
+	if handle, ok := handles[key]; ok {
+		return handle, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir el disco: %v", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error al leer el disco: %v", err)
+	}
+
+	handle := &PartitionHandle{
+		path:  key,
+		file:  file,
+		info:  info,
+		size:  info.Size(),
+		pages: map[int64]*cachePage{},
+	}
+	handles[key] = handle
+
+	return handle, nil
+}
+
+// getHandle retorna el handle abierto del disco, o nil si no hay uno
+func getHandle(path string) *PartitionHandle {
+	handlesMutex.Lock()
+	defer handlesMutex.Unlock()
+
+	return handles[filepath.Clean(path)]
+}
+
+// ClosePartition escribe los cambios pendientes y cierra el handle del disco
+func ClosePartition(path string) error {
+	handle := getHandle(path)
+	if handle == nil {
+		return nil
+	}
+	return handle.Close()
+}
+
+// SyncPartition escribe en el archivo los cambios pendientes del handle del
+// disco. Sin un handle abierto las escrituras ya van directo al archivo
+func SyncPartition(path string) error {
+	if handle := getHandle(path); handle != nil {
+		return handle.Sync()
+	}
+	return nil
+}
+
+// RefreshPartition descarta la caché si el archivo cambió por fuera del
+// handle (otro proceso o una escritura directa) y cierra el handle si el
+// archivo ya no existe
+func RefreshPartition(path string) {
+	handle := getHandle(path)
+	if handle == nil {
+		return
+	}
+
+	info, err := os.Stat(handle.path)
+	if err != nil || !os.SameFile(info, handle.info) {
+		handle.Close()
+		return
+	}
+
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	if info.Size() != handle.info.Size() || !info.ModTime().Equal(handle.info.ModTime()) {
+		handle.writeBack()
+		handle.pages = map[int64]*cachePage{}
+		handle.size = info.Size()
+		handle.info = info
+	}
+}
+
+// OpenDisk abre el disco para leer o escribir bytes directamente. Si la
+// partición está montada se usa su handle, para que la caché no quede desactualizada
+func OpenDisk(path string, flag int) (DiskFile, error) {
+	if handle := getHandle(path); handle != nil {
+		return sharedHandle{handle}, nil
+	}
+	return os.OpenFile(path, flag, 0644)
+}
+
+// sharedHandle es el handle visto como DiskFile: cerrarlo no cierra el handle
+type sharedHandle struct {
+	*PartitionHandle
+}
+
+func (sharedHandle) Close() error {
+	return nil
+}
+
+// ReadAt lee bytes del disco desde la caché, cargando las páginas que falten
+func (h *PartitionHandle) ReadAt(p []byte, offset int64) (int, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.file == nil {
+		return 0, os.ErrClosed
+	}
+	if offset >= h.size {
+		return 0, io.EOF
+	}
+
+	n := len(p)
+	if offset+int64(n) > h.size {
+		n = int(h.size - offset)
+	}
+
+	for done := 0; done < n; {
+		page, err := h.page((offset + int64(done)) / handlePageSize)
+		if err != nil {
+			return done, err
+		}
+		start := int((offset + int64(done)) % handlePageSize)
+		done += copy(p[done:n], page.data[start:])
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt escribe bytes en la caché; llegan al archivo con Sync
+func (h *PartitionHandle) WriteAt(p []byte, offset int64) (int, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	for done := 0; done < len(p); {
+		page, err := h.page((offset + int64(done)) / handlePageSize)
+		if err != nil {
+			return done, err
+		}
+		start := int((offset + int64(done)) % handlePageSize)
+		written := copy(page.data[start:], p[done:])
+
+		if page.dirtyFrom == page.dirtyTo {
+			page.dirtyFrom, page.dirtyTo = start, start+written
+		} else {
+			page.dirtyFrom = min(page.dirtyFrom, start)
+			page.dirtyTo = max(page.dirtyTo, start+written)
+		}
+		done += written
+	}
+
+	h.size = max(h.size, offset+int64(len(p)))
+	return len(p), nil
+}
+
+// page retorna una página de la caché, leyéndola del archivo si no está.
+// Se debe llamar con el mutex del handle tomado.
+func (h *PartitionHandle) page(index int64) (*cachePage, error) {
+	if page, ok := h.pages[index]; ok {
+		return page, nil
+	}
+
+	// Con la caché llena se escriben los cambios y se empieza de nuevo
+	if len(h.pages) >= handleMaxPages {
+		if _, err := h.writeBack(); err != nil {
+			return nil, err
+		}
+		h.pages = map[int64]*cachePage{}
+	}
+
+	page := &cachePage{data: make([]byte, handlePageSize)}
+	_, err := h.file.ReadAt(page.data, index*handlePageSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	h.pages[index] = page
+	return page, nil
+}
+
+// writeBack escribe en el archivo los rangos modificados de las páginas y
+// retorna cuántos escribió. Se debe llamar con el mutex del handle tomado.
+func (h *PartitionHandle) writeBack() (int, error) {
+	indexes := make([]int64, 0, len(h.pages))
+	for index, page := range h.pages {
+		if page.dirtyFrom != page.dirtyTo {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	for _, index := range indexes {
+		page := h.pages[index]
+		_, err := h.file.WriteAt(page.data[page.dirtyFrom:page.dirtyTo], index*handlePageSize+int64(page.dirtyFrom))
+		if err != nil {
+			return 0, fmt.Errorf("error al escribir en el disco: %v", err)
+		}
+		page.dirtyFrom, page.dirtyTo = 0, 0
+	}
+
+	return len(indexes), nil
+}
+
+// Sync escribe los cambios pendientes en el archivo y lo sincroniza
+func (h *PartitionHandle) Sync() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.file == nil {
+		return os.ErrClosed
+	}
+
+	written, err := h.writeBack()
+	if err != nil || written == 0 {
+		return err
+	}
+	if err = h.file.Sync(); err != nil {
+		return err
+	}
+
+	// Recordar el estado del archivo para detectar cambios hechos por fuera
+	if info, err := h.file.Stat(); err == nil {
+		h.info = info
+	}
+	return nil
+}
+
+// Close escribe los cambios pendientes y cierra el archivo del disco
+func (h *PartitionHandle) Close() error {
+	handlesMutex.Lock()
+	if handles[h.path] == h {
+		delete(handles, h.path)
+	}
+	handlesMutex.Unlock()
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+
+	_, err := h.writeBack()
+	if closeErr := h.file.Close(); err == nil {
+		err = closeErr
+	}
+	h.file = nil
+	h.pages = nil
+
+	return err
+}
@@ -0,0 +1,377 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// benchDiskSize es el tamaño del disco de los benchmarks (50 MB)
+const benchDiskSize = 50 * 1024 * 1024
+
+// silenceOutput descarta lo que las operaciones imprimen en consola
+func silenceOutput(tb testing.TB) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = devNull
+	tb.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+// newTestDisk crea un disco del tamaño indicado con una sola partición EXT2 que
+// ocupa todo el archivo, recién formateada con la carpeta raíz y users.txt
+func newTestDisk(tb testing.TB, size int32) (string, *SuperBlock) {
+	tb.Helper()
+
+	path := filepath.Join(tb.TempDir(), "disco.mia")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.Truncate(path, int64(size)); err != nil {
+		tb.Fatal(err)
+	}
+
+	// Misma distribución que mkfs para una partición que empieza en 0
+	sbSize := int32(binary.Size(SuperBlock{}))
+	n := (size - sbSize) / int32(4+INodeSize+3*binary.Size(FileBlock{}))
+	bmInodeStart := sbSize
+	bmBlockStart := bmInodeStart + n
+	inodeStart := bmBlockStart + 3*n
+	blockStart := inodeStart + INodeSize*n
+
+	sb := &SuperBlock{
+		SFilesystemType:  2,
+		SFreeBlocksCount: 3 * n,
+		SFreeInodesCount: n,
+		SMagic:           0xEF53,
+		SInodeS:          INodeSize,
+		SBlockS:          64,
+		SFirstIno:        inodeStart,
+		SFirstBlo:        blockStart,
+		SBmInodeStart:    bmInodeStart,
+		SBmBlockStart:    bmBlockStart,
+		SInodeStart:      inodeStart,
+		SBlockStart:      blockStart,
+	}
+
+	if err := sb.CreateUsersFile(path); err != nil {
+		tb.Fatal(err)
+	}
+	if err := sb.SerializeSuperBlock(path, 0); err != nil {
+		tb.Fatal(err)
+	}
+
+	return path, sb
+}
+
+// newBenchDisk crea un disco de 50 MB con una sola partición EXT2 que ocupa
+// todo el archivo y la llena con carpetas y archivos
+func newBenchDisk(b *testing.B) (string, *SuperBlock) {
+	b.Helper()
+
+	path, sb := newTestDisk(b, benchDiskSize)
+
+	// El llenado usa el handle para que la preparación no domine el benchmark
+	handle, err := OpenPartition(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer handle.Close()
+
+	for i := 0; i < 10; i++ {
+		dir := fmt.Sprintf("dir%d", i)
+		if err := sb.CreateFolder(path, nil, dir, false, 1, 1); err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < 10; j++ {
+			name := fmt.Sprintf("file%d.txt", j)
+			if err := sb.CreateFile(path, []string{dir}, name, 300, "", false, 1, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	if err := sb.SerializeSuperBlock(path, 0); err != nil {
+		b.Fatal(err)
+	}
+
+	return path, sb
+}
+
+// openTestHandle crea un archivo de disco con size bytes en cero y abre su handle
+func openTestHandle(t *testing.T, size int64) (string, *PartitionHandle) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "disco.mia")
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handle, err := OpenPartition(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { handle.Close() })
+
+	return path, handle
+}
+
+// readFileAt lee bytes del archivo sin pasar por el handle
+func readFileAt(t *testing.T, path string, offset int64, size int) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content[offset : offset+int64(size)]
+}
+
+// readHandleAt lee bytes a través del handle
+func readHandleAt(t *testing.T, handle *PartitionHandle, offset int64, size int) []byte {
+	t.Helper()
+
+	buffer := make([]byte, size)
+	if _, err := handle.ReadAt(buffer, offset); err != nil {
+		t.Fatal(err)
+	}
+	return buffer
+}
+
+func TestPartitionHandleReadAfterWrite(t *testing.T) {
+	path, handle := openTestHandle(t, 3*handlePageSize)
+
+	// La escritura cruza el límite entre la primera y la segunda página
+	data := []byte("datos entre dos páginas")
+	offset := int64(handlePageSize - 5)
+	if _, err := handle.WriteAt(data, offset); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readHandleAt(t, handle, offset, len(data)); !bytes.Equal(got, data) {
+		t.Errorf("ReadAt después de WriteAt = %q, se esperaba %q", got, data)
+	}
+
+	// Hasta Sync los cambios solo están en la caché
+	if got := readFileAt(t, path, offset, len(data)); !bytes.Equal(got, make([]byte, len(data))) {
+		t.Errorf("el archivo cambió antes de Sync: %q", got)
+	}
+
+	// OpenDisk usa el mismo handle mientras la partición está montada
+	disk, err := OpenDisk(path, os.O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer disk.Close()
+	buffer := make([]byte, len(data))
+	if _, err := disk.ReadAt(buffer, offset); err != nil || !bytes.Equal(buffer, data) {
+		t.Errorf("OpenDisk leyó %q (%v), se esperaba %q", buffer, err, data)
+	}
+}
+
+func TestPartitionHandleSync(t *testing.T) {
+	path, handle := openTestHandle(t, 2*handlePageSize)
+
+	writes := map[int64]string{10: "inicio", handlePageSize + 100: "segunda página", 20: "otro rango"}
+	for offset, data := range writes {
+		if _, err := handle.WriteAt([]byte(data), offset); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := SyncPartition(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for offset, data := range writes {
+		if got := readFileAt(t, path, offset, len(data)); string(got) != data {
+			t.Errorf("bytes en %d después de Sync = %q, se esperaba %q", offset, got, data)
+		}
+	}
+}
+
+func TestPartitionHandleClose(t *testing.T) {
+	path, handle := openTestHandle(t, handlePageSize)
+
+	// Escribir después del final agranda el disco
+	data := []byte("después del final")
+	offset := int64(handlePageSize + 10)
+	if _, err := handle.WriteAt(data, offset); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ClosePartition(path); err != nil {
+		t.Fatal(err)
+	}
+	if getHandle(path) != nil {
+		t.Error("el handle sigue registrado después de cerrarlo")
+	}
+	if _, err := handle.ReadAt(make([]byte, 1), 0); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("ReadAt con el handle cerrado = %v, se esperaba os.ErrClosed", err)
+	}
+
+	if got := readFileAt(t, path, offset, len(data)); !bytes.Equal(got, data) {
+		t.Errorf("bytes después de cerrar = %q, se esperaba %q", got, data)
+	}
+}
+
+func TestPartitionHandleEOF(t *testing.T) {
+	_, handle := openTestHandle(t, 100)
+
+	buffer := make([]byte, 10)
+	n, err := handle.ReadAt(buffer, 95)
+	if n != 5 || err != io.EOF {
+		t.Errorf("ReadAt al final = %d, %v; se esperaba 5, EOF", n, err)
+	}
+	if _, err := handle.ReadAt(buffer, 100); err != io.EOF {
+		t.Errorf("ReadAt después del final = %v, se esperaba EOF", err)
+	}
+}
+
+func TestPartitionHandleFullCache(t *testing.T) {
+	path, handle := openTestHandle(t, 0)
+
+	// Una página más de las que caben obliga a vaciar la caché a mitad de camino
+	for i := int64(0); i <= handleMaxPages; i++ {
+		if _, err := handle.WriteAt([]byte{byte(i) | 1}, i*handlePageSize); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := handle.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int64(0); i <= handleMaxPages; i++ {
+		if content[i*handlePageSize] != byte(i)|1 {
+			t.Fatalf("la página %d no se escribió en el archivo", i)
+		}
+	}
+}
+
+func TestRefreshPartition(t *testing.T) {
+	path, handle := openTestHandle(t, 2*handlePageSize)
+
+	// Leer carga la página en la caché y escribir deja otra página pendiente
+	readHandleAt(t, handle, 0, 16)
+	pending := []byte("pendiente")
+	if _, err := handle.WriteAt(pending, handlePageSize); err != nil {
+		t.Fatal(err)
+	}
+
+	// Como mkdisk o fdisk al escribir el MBR, escribir sin pasar por el handle
+	external := []byte("MBR nuevo")
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteAt(external, 0); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	// La hora de modificación puede no cambiar entre dos escrituras seguidas
+	modified := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readHandleAt(t, handle, 0, len(external)); bytes.Equal(got, external) {
+		t.Fatal("la caché ya veía el cambio externo antes de RefreshPartition")
+	}
+
+	RefreshPartition(path)
+
+	if got := readHandleAt(t, handle, 0, len(external)); !bytes.Equal(got, external) {
+		t.Errorf("después de RefreshPartition se leyó %q, se esperaba %q", got, external)
+	}
+	if got := readHandleAt(t, handle, handlePageSize, len(pending)); !bytes.Equal(got, pending) {
+		t.Errorf("RefreshPartition perdió la escritura pendiente: %q", got)
+	}
+	if got := readFileAt(t, path, handlePageSize, len(pending)); !bytes.Equal(got, pending) {
+		t.Errorf("RefreshPartition no escribió la página pendiente: %q", got)
+	}
+}
+
+func TestRefreshPartitionRemovedDisk(t *testing.T) {
+	path, _ := openTestHandle(t, handlePageSize)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	RefreshPartition(path)
+
+	if getHandle(path) != nil {
+		t.Error("el handle de un disco eliminado sigue abierto")
+	}
+}
+
+// benchmarkPartition ejecuta op leyendo directo del archivo y con el handle abierto
+func benchmarkPartition(b *testing.B, op func(path string, sb *SuperBlock) error) {
+	silenceOutput(b)
+	path, sb := newBenchDisk(b)
+
+	b.Run("direct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := op(path, sb); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("handle", func(b *testing.B) {
+		handle, err := OpenPartition(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer handle.Close()
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := op(path, sb); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGenerateFileSystemTree(b *testing.B) {
+	benchmarkPartition(b, func(path string, sb *SuperBlock) error {
+		_, err := sb.GenerateFileSystemTree(path)
+		return err
+	})
+}
+
+func BenchmarkFindFileOrFolderByName(b *testing.B) {
+	benchmarkPartition(b, func(path string, sb *SuperBlock) error {
+		_, err := sb.FindFileOrFolderByName(path, nil, "file9.txt")
+		return err
+	})
+}
+
+func BenchmarkDeserializeInodeTable(b *testing.B) {
+	benchmarkPartition(b, func(path string, sb *SuperBlock) error {
+		inode := &INode{}
+		for i := int32(0); i < sb.SInodesCount; i++ {
+			if err := inode.Deserialize(path, int64(sb.SInodeStart+i*sb.SInodeS)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"os"
 )
 
 const (
@@ -17,41 +16,19 @@ type PointerBlock struct {
 
 // Serialize escribe la estructura PointerBlock en un archivo binario en la posición especificada
 func (pb *PointerBlock) Serialize(path string, offset int64) error {
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	// Serializar la estructura PointerBlock en un buffer
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, pb)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
-	// Serializar la estructura PointerBlock directamente en el archivo
-	err = binary.Write(file, binary.LittleEndian, pb)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Escribir el buffer en el disco (queda pendiente si hay una transacción activa)
+	return writeDisk(path, offset, buf.Bytes())
 }
 
 // Deserialize lee la estructura PointerBlock desde un archivo binario en la posición especificada
 func (pb *PointerBlock) Deserialize(path string, offset int64) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Mover el puntero del archivo a la posición especificada
-	_, err = file.Seek(offset, 0)
-	if err != nil {
-		return err
-	}
-
 	// Obtener el tamaño de la estructura PointerBlock
 	pbSize := binary.Size(pb)
 	if pbSize <= 0 {
@@ -59,8 +36,7 @@ func (pb *PointerBlock) Deserialize(path string, offset int64) error {
 	}
 
 	// Leer solo la cantidad de bytes que corresponden al tamaño de la estructura
-	buffer := make([]byte, pbSize)
-	_, err = file.Read(buffer)
+	buffer, err := readDisk(path, offset, pbSize)
 	if err != nil {
 		return err
 	}
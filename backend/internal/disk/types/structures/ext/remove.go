@@ -2,8 +2,6 @@ package ext2
 
 import (
 	"fmt"
-	"os"
-	"strings"
 )
 
 func (sb *SuperBlock) RemoveFileOrDirectory(path string, parentDirs []string, targetName string, uid int32, gid int32) error {
@@ -24,10 +22,13 @@ func (sb *SuperBlock) RemoveFileOrDirectory(path string, parentDirs []string, ta
 	if !sb.userHasWritePermission(targetInode, uid, gid) {
 		return fmt.Errorf("permisos insuficientes para eliminar el elemento")
 	}
-	// Obtener inodo del directorio padre
-	parentInodeIndex, err := sb.FindFileInode(path, parentDirs[:len(parentDirs)-1], parentDirs[len(parentDirs)-1])
-	if err != nil {
-		return fmt.Errorf("error al encontrar directorio padre: %v", err)
+	// Obtener inodo del directorio padre (la raíz si no hay directorios padre)
+	parentInodeIndex := int32(0)
+	if len(parentDirs) > 0 {
+		parentInodeIndex, err = sb.FindFileInode(path, parentDirs[:len(parentDirs)-1], parentDirs[len(parentDirs)-1])
+		if err != nil {
+			return fmt.Errorf("error al encontrar directorio padre: %v", err)
+		}
 	}
 
 	// Eliminar la entrada del directorio padre
@@ -69,11 +70,16 @@ func (sb *SuperBlock) removeFromParentDirectory(path string, parentInodeIndex in
 		}
 
 		for j, entry := range dirBlock.BContent {
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			if entryName == targetName {
+				// Liberar los bloques del nombre si es un nombre largo
+				if err := sb.freeEntryName(path, entry); err != nil {
+					return err
+				}
+
 				// Marcar la entrada como libre
 				dirBlock.BContent[j].BInodo = -1
-				copy(dirBlock.BContent[j].BName[:], []byte{'-'})
+				dirBlock.BContent[j].BName = [12]byte{'-'}
 
 				if err := dirBlock.Serialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS))); err != nil {
 					return err
@@ -122,7 +128,7 @@ func (sb *SuperBlock) verifyDirectoryDeletion(path string, dirInodeIndex int32,
 				continue
 			}
 
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(path, entry)
 			targetInode := &INode{}
 			if err := targetInode.Deserialize(path, int64(sb.SInodeStart+(entry.BInodo*sb.SInodeS))); err != nil {
 				return err
@@ -166,6 +172,11 @@ func (sb *SuperBlock) forceDeleteDirectoryContents(path string, dirInodeIndex in
 				continue
 			}
 
+			// Liberar los bloques del nombre si es un nombre largo
+			if err := sb.freeEntryName(path, entry); err != nil {
+				return err
+			}
+
 			// Obtener inodo directamente desde la entrada del directorio
 			targetInodeIndex := entry.BInodo
 
@@ -201,47 +212,21 @@ func (sb *SuperBlock) deleteByInode(path string, inodeIndex int32, uid int32, gi
 	return sb.freeInodeAndBlocks(path, inodeIndex, targetInode)
 }
 
+// freeInodeAndBlocks libera el inodo y todos sus bloques, incluidos los de
+// punteros y los bloques de datos a los que apuntan. SInodesCount y
+// SBlocksCount no se modifican: indican el siguiente índice a asignar
 func (sb *SuperBlock) freeInodeAndBlocks(path string, inodeIndex int32, inode *INode) error {
-	// Marcar inodo como libre
-	file, err := os.OpenFile(path, os.O_WRONLY, 0666)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Actualizar bitmap de inodos
-	_, err = file.Seek(int64(sb.SBmInodeStart+inodeIndex), 0)
+	refs, err := sb.InodeBlockList(path, inode)
 	if err != nil {
 		return err
 	}
-	_, err = file.Write([]byte{0})
-	if err != nil {
-		return err
-	}
-
-	// Actualizar contadores
-	sb.SFreeInodesCount++
-	sb.SInodesCount--
 
-	// Liberar bloques de datos
-	for _, blockIndex := range inode.IBlock {
-		if blockIndex == -1 {
-			continue
-		}
-
-		// Marcar bloque como libre
-		_, err = file.Seek(int64(sb.SBmBlockStart+blockIndex), 0)
-		if err != nil {
-			return err
-		}
-		_, err = file.Write([]byte{0})
+	for _, ref := range refs {
+		err = sb.freeBlock(path, ref.Block)
 		if err != nil {
 			return err
 		}
-
-		sb.SFreeBlocksCount++
-		sb.SBlocksCount--
 	}
 
-	return nil
+	return sb.freeInode(path, inodeIndex)
 }
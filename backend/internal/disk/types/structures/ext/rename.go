@@ -2,11 +2,14 @@ package ext2
 
 import (
 	"fmt"
-	"strings"
 	"time"
 )
 
 func (sb *SuperBlock) Rename(partitionPath string, parentDirs []string, oldName string, newName string, uid int32, gid int32) error {
+	if len(newName) > MaxNameLength {
+		return fmt.Errorf("el nombre '%s' excede el máximo de %d caracteres", newName, MaxNameLength)
+	}
+
 	// Buscar el inodo del elemento a renombrar
 	targetInodeIndex, err := sb.FindFileInode(partitionPath, parentDirs, oldName)
 	if err != nil {
@@ -23,10 +26,13 @@ func (sb *SuperBlock) Rename(partitionPath string, parentDirs []string, oldName
 		return fmt.Errorf("permisos insuficientes para renombrar el elemento")
 	}
 
-	// Buscar el directorio padre
-	parentInodeIndex, err := sb.FindFileInode(partitionPath, parentDirs[:len(parentDirs)-1], parentDirs[len(parentDirs)-1])
-	if err != nil {
-		return fmt.Errorf("error al encontrar directorio padre: %v", err)
+	// Buscar el directorio padre (la raíz si no hay directorios padre)
+	parentInodeIndex := int32(0)
+	if len(parentDirs) > 0 {
+		parentInodeIndex, err = sb.FindFileInode(partitionPath, parentDirs[:len(parentDirs)-1], parentDirs[len(parentDirs)-1])
+		if err != nil {
+			return fmt.Errorf("error al encontrar directorio padre: %v", err)
+		}
 	}
 
 	// Verificar que el nuevo nombre no exista
@@ -49,7 +55,6 @@ func (sb *SuperBlock) Rename(partitionPath string, parentDirs []string, oldName
 	return parentInode.Serialize(partitionPath, int64(sb.SInodeStart+(parentInodeIndex*sb.SInodeS)))
 }
 
-
 func (sb *SuperBlock) updateDirectoryEntry(partitionPath string, parentInodeIndex int32, oldName string, newName string) error {
 	parentInode := &INode{}
 	if err := parentInode.Deserialize(partitionPath, int64(sb.SInodeStart+(parentInodeIndex*sb.SInodeS))); err != nil {
@@ -73,7 +78,7 @@ func (sb *SuperBlock) updateDirectoryEntry(partitionPath string, parentInodeInde
 		}
 
 		for j, entry := range dirBlock.BContent {
-			entryName := strings.Trim(string(entry.BName[:]), "\x00")
+			entryName := sb.EntryName(partitionPath, entry)
 			if entryName == oldName {
 				targetBlockIndex = blockIndex
 				targetEntryIndex = j
@@ -93,10 +98,13 @@ func (sb *SuperBlock) updateDirectoryEntry(partitionPath string, parentInodeInde
 		return err
 	}
 
-	// Copiar nuevo nombre truncando a 12 bytes
-	var newNameBytes [12]byte
-	copy(newNameBytes[:], []byte(newName))
-	dirBlock.BContent[targetEntryIndex].BName = newNameBytes
+	// Liberar los bloques del nombre anterior y guardar el nuevo nombre
+	if err := sb.freeEntryName(partitionPath, dirBlock.BContent[targetEntryIndex]); err != nil {
+		return err
+	}
+	if err := sb.setEntryName(partitionPath, &dirBlock.BContent[targetEntryIndex], newName); err != nil {
+		return err
+	}
 
 	// Escribir bloque actualizado
 	if err := dirBlock.Serialize(partitionPath, int64(sb.SBlockStart+(targetBlockIndex*sb.SBlockS))); err != nil {
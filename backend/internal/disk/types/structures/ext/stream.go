@@ -0,0 +1,257 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// pointersPerBlock es la cantidad de punteros de un PointerBlock
+const pointersPerBlock = len(PointerBlock{}.PContent)
+
+// MaxFileSize es el tamaño máximo de un archivo: 12 bloques directos y los
+// bloques que alcanzan los punteros indirectos simple, doble y triple
+const MaxFileSize = (12 + pointersPerBlock + pointersPerBlock*pointersPerBlock +
+	pointersPerBlock*pointersPerBlock*pointersPerBlock) * FileBlockSize
+
+// WriteFileContent escribe size bytes leídos de reader en los bloques de un
+// archivo vacío, usando los bloques directos y después los indirectos simple,
+// doble y triple. El contenido se lee de a un bloque, sin cargarlo completo en
+// memoria, y el espacio se verifica antes de reservar el primer bloque
+func (sb *SuperBlock) WriteFileContent(path string, inodeIndex int32, reader io.Reader, size int64) error {
+	offset := int64(sb.SInodeStart + (inodeIndex * sb.SInodeS))
+	inode := &INode{}
+	err := inode.Deserialize(path, offset)
+	if err != nil {
+		return err
+	}
+
+	if inode.IType[0] != '1' || inode.ISize != 0 {
+		return fmt.Errorf("el inodo %d no es un archivo vacío", inodeIndex)
+	}
+
+	err = sb.fillFileBlocks(path, inode, reader, size)
+	if err != nil {
+		return err
+	}
+
+	inode.IMtime = float32(time.Now().Unix())
+	return inode.Serialize(path, offset)
+}
+
+// checkFileSpace verifica que un archivo de size bytes quepa en la partición
+// con los bloques que quedan a partir de SFirstBlo
+func (sb *SuperBlock) checkFileSpace(size int64) error {
+	if size > int64(MaxFileSize) {
+		return fmt.Errorf("el archivo de %d bytes excede el tamaño máximo de %d bytes", size, MaxFileSize)
+	}
+
+	dataBlocks := int((size + FileBlockSize - 1) / FileBlockSize)
+	needed := int32(fileBlocksNeeded(dataBlocks))
+	nextBlock := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
+	if needed > sb.SFreeBlocksCount || nextBlock+needed > sb.BlocksTotal() {
+		return fmt.Errorf("no hay bloques libres suficientes: se necesitan %d", needed)
+	}
+
+	return nil
+}
+
+// fillFileBlocks reserva los bloques de un inodo sin bloques asignados y los
+// llena con el contenido de reader. El llamador guarda el inodo
+func (sb *SuperBlock) fillFileBlocks(path string, inode *INode, reader io.Reader, size int64) error {
+	err := sb.checkFileSpace(size)
+	if err != nil {
+		return err
+	}
+
+	writer := &blockWriter{sb: sb, path: path, reader: reader, remaining: size}
+
+	// Bloques directos (0-11)
+	for i := 0; i < 12 && writer.remaining > 0; i++ {
+		inode.IBlock[i], err = writer.writeData()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Bloques indirectos simple (12), doble (13) y triple (14)
+	for depth := 1; depth <= 3 && writer.remaining > 0; depth++ {
+		inode.IBlock[11+depth], err = writer.writeIndirect(depth)
+		if err != nil {
+			return err
+		}
+	}
+
+	inode.ISize = int32(size)
+	return nil
+}
+
+// CopyFileContent escribe en w el contenido de un archivo a partir de su inodo,
+// de a un bloque. No actualiza la fecha de acceso
+func (sb *SuperBlock) CopyFileContent(path string, inode *INode, w io.Writer) error {
+	if inode.IType[0] != '1' {
+		return fmt.Errorf("el inodo no es un archivo")
+	}
+
+	refs, err := sb.InodeBlockList(path, inode)
+	if err != nil {
+		return err
+	}
+
+	remaining := int(inode.ISize)
+	for _, ref := range refs {
+		if ref.Pointer || remaining == 0 {
+			continue
+		}
+
+		fileBlock := &FileBlock{}
+		err = fileBlock.Deserialize(path, int64(sb.SBlockStart+(ref.Block*sb.SBlockS)))
+		if err != nil {
+			return fmt.Errorf("error al leer el bloque de archivo %d: %v", ref.Block, err)
+		}
+
+		n := min(remaining, FileBlockSize)
+		if _, err := w.Write(fileBlock.BContent[:n]); err != nil {
+			return err
+		}
+		remaining -= n
+	}
+
+	return nil
+}
+
+// fileBlocksNeeded devuelve los bloques de datos y de punteros que ocupa un
+// archivo con la cantidad de bloques de datos indicada
+func fileBlocksNeeded(dataBlocks int) int {
+	total := dataBlocks
+	remaining := max(dataBlocks-12, 0)
+
+	capacity := 1
+	for depth := 1; depth <= 3 && remaining > 0; depth++ {
+		capacity *= pointersPerBlock
+		used := min(remaining, capacity)
+		total += pointerBlocksNeeded(used, depth)
+		remaining -= used
+	}
+
+	return total
+}
+
+// pointerBlocksNeeded devuelve los bloques de punteros de un nivel indirecto
+// de la profundidad indicada que apunta a dataBlocks bloques de datos
+func pointerBlocksNeeded(dataBlocks int, depth int) int {
+	if depth == 1 {
+		return 1
+	}
+
+	perChild := 1
+	for i := 1; i < depth; i++ {
+		perChild *= pointersPerBlock
+	}
+
+	total := 1
+	for dataBlocks > 0 {
+		used := min(dataBlocks, perChild)
+		total += pointerBlocksNeeded(used, depth-1)
+		dataBlocks -= used
+	}
+	return total
+}
+
+// blockWriter reserva bloques de forma consecutiva desde SFirstBlo y los llena
+// con el contenido de reader. El bitmap y los contadores van en la transacción
+// activa, pero los bloques se escriben directo en el disco para que un archivo
+// grande no quede completo en memoria ni llene el journal
+type blockWriter struct {
+	sb        *SuperBlock
+	path      string
+	reader    io.Reader
+	remaining int64 // Bytes que faltan por escribir
+}
+
+// reserve toma el siguiente bloque libre y actualiza el bitmap y los contadores
+func (w *blockWriter) reserve() (int32, error) {
+	sb := w.sb
+	blockIndex := (sb.SFirstBlo - sb.SBlockStart) / sb.SBlockS
+
+	err := sb.UpdateBitmapBlock(w.path)
+	if err != nil {
+		return -1, err
+	}
+
+	sb.SBlocksCount++
+	sb.SFreeBlocksCount--
+	sb.SFirstBlo += sb.SBlockS
+
+	return blockIndex, nil
+}
+
+// writeBlock escribe un bloque de datos o de punteros recién reservado sin
+// pasar por la transacción activa
+func (w *blockWriter) writeBlock(blockIndex int32, block any) error {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, block)
+	if err != nil {
+		return err
+	}
+
+	return writeDiskDirect(w.path, int64(w.sb.SBlockStart+(blockIndex*w.sb.SBlockS)), buf.Bytes())
+}
+
+// writeData escribe el siguiente bloque de datos del archivo
+func (w *blockWriter) writeData() (int32, error) {
+	fileBlock := &FileBlock{}
+	n := min(w.remaining, FileBlockSize)
+
+	_, err := io.ReadFull(w.reader, fileBlock.BContent[:n])
+	if err != nil {
+		return -1, fmt.Errorf("error al leer el contenido: %v", err)
+	}
+
+	blockIndex, err := w.reserve()
+	if err != nil {
+		return -1, err
+	}
+
+	err = w.writeBlock(blockIndex, fileBlock)
+	if err != nil {
+		return -1, fmt.Errorf("error al serializar bloque de archivo %d: %v", blockIndex, err)
+	}
+
+	w.remaining -= n
+	return blockIndex, nil
+}
+
+// writeIndirect reserva un bloque de punteros y llena sus entradas con bloques
+// de datos (depth 1) o con bloques de punteros de un nivel menos
+func (w *blockWriter) writeIndirect(depth int) (int32, error) {
+	pointerIndex, err := w.reserve()
+	if err != nil {
+		return -1, err
+	}
+
+	pointerBlock := &PointerBlock{}
+	for i := range pointerBlock.PContent {
+		pointerBlock.PContent[i] = -1
+	}
+
+	for i := 0; i < pointersPerBlock && w.remaining > 0; i++ {
+		if depth == 1 {
+			pointerBlock.PContent[i], err = w.writeData()
+		} else {
+			pointerBlock.PContent[i], err = w.writeIndirect(depth - 1)
+		}
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	err = w.writeBlock(pointerIndex, pointerBlock)
+	if err != nil {
+		return -1, fmt.Errorf("error al serializar bloque de punteros %d: %v", pointerIndex, err)
+	}
+
+	return pointerIndex, nil
+}
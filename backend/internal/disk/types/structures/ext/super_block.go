@@ -3,15 +3,20 @@ package ext2
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"os"
 	"time"
 )
 
 const (
-	SuperBlockSize = 68
+	SuperBlockSize = 84
 )
 
+// ErrOldSuperBlock se devuelve al leer una partición formateada con un
+// superbloque más corto: sus campos del journal serían bytes del journal o de
+// los bitmaps, así que no se puede usar sin volver a formatearla
+var ErrOldSuperBlock = errors.New("la partición fue formateada con una versión anterior del superbloque, vuelva a formatearla con mkfs")
+
 type SuperBlock struct {
 	SFilesystemType  int32   // Guarda el número que identifica el sistema de archivos utilizado
 	SInodesCount     int32   // Guarda el número total de inodos
@@ -30,22 +35,14 @@ type SuperBlock struct {
 	SBmBlockStart    int32   // Guardará el inicio del bitmap de bloques
 	SInodeStart      int32   // Guardará el inicio de la tabla de inodos
 	SBlockStart      int32   // Guardará el inicio de la tabla de bloques
+	SJournalHead     int32   // Secuencia de la entrada más antigua del journal
+	SJournalTail     int32   // Secuencia que recibirá la próxima entrada del journal
+	SJournalPolicy   int32   // Qué hacer cuando el journal está lleno (JournalOverwrite o JournalError)
+	SJournalSize     int32   // Cantidad de slots del journal (0 en EXT2)
 }
 
 // SerializeSuperBlock escribe la estructura SuperBlock en su representación binaria en un archivo
 func (sb *SuperBlock) SerializeSuperBlock(path string, start int32) error {
-	file, err := os.OpenFile(path, os.O_RDWR, 0666)
-	if err != nil {
-		return fmt.Errorf("error al abrir el archivo: %v", err)
-	}
-	defer file.Close()
-
-	// Posicionarse en el inicio del SuperBlock
-	_, err = file.Seek(int64(start), 0)
-	if err != nil {
-		return fmt.Errorf("error al posicionarse en el disco: %v", err)
-	}
-
 	// Crear un buffer para almacenar los datos
 	buf := new(bytes.Buffer)
 
@@ -67,9 +64,13 @@ func (sb *SuperBlock) SerializeSuperBlock(path string, start int32) error {
 	binary.Write(buf, binary.LittleEndian, sb.SBmBlockStart)
 	binary.Write(buf, binary.LittleEndian, sb.SInodeStart)
 	binary.Write(buf, binary.LittleEndian, sb.SBlockStart)
+	binary.Write(buf, binary.LittleEndian, sb.SJournalHead)
+	binary.Write(buf, binary.LittleEndian, sb.SJournalTail)
+	binary.Write(buf, binary.LittleEndian, sb.SJournalPolicy)
+	binary.Write(buf, binary.LittleEndian, sb.SJournalSize)
 
-	// Escribir el buffer en el archivo
-	_, err = file.Write(buf.Bytes())
+	// Escribir el buffer en el disco (queda pendiente si hay una transacción activa)
+	err := writeDisk(path, int64(start), buf.Bytes())
 	if err != nil {
 		return fmt.Errorf("error al escribir el SuperBlock: %v", err)
 	}
@@ -79,107 +80,135 @@ func (sb *SuperBlock) SerializeSuperBlock(path string, start int32) error {
 
 // DeserializeSuperBlock lee una estructura SuperBlock desde su representación binaria en un archivo
 func (sb *SuperBlock) DeserializeSuperBlock(path string, start int32) error {
-	file, err := os.OpenFile(path, os.O_RDONLY, 0666)
+	buffer, err := readDisk(path, int64(start), SuperBlockSize)
 	if err != nil {
-		return fmt.Errorf("error al abrir el archivo: %v", err)
-	}
-	defer file.Close()
-
-	// Posicionarse en el inicio del SuperBlock
-	_, err = file.Seek(int64(start), 0)
-	if err != nil {
-		return fmt.Errorf("error al posicionarse en el disco: %v", err)
+		return fmt.Errorf("error al leer el disco: %v", err)
 	}
+	reader := bytes.NewReader(buffer)
 
 	// Leer los campos del SuperBlock
-	err = binary.Read(file, binary.LittleEndian, &sb.SFilesystemType)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SFilesystemType)
 	if err != nil {
 		return fmt.Errorf("error al leer SFilesystemType: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SInodesCount)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SInodesCount)
 	if err != nil {
 		return fmt.Errorf("error al leer SInodesCount: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SBlocksCount)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SBlocksCount)
 	if err != nil {
 		return fmt.Errorf("error al leer SBlocksCount: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SFreeBlocksCount)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SFreeBlocksCount)
 	if err != nil {
 		return fmt.Errorf("error al leer SFreeBlocksCount: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SFreeInodesCount)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SFreeInodesCount)
 	if err != nil {
 		return fmt.Errorf("error al leer SFreeInodesCount: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SMtime)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SMtime)
 	if err != nil {
 		return fmt.Errorf("error al leer SMtime: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SUmTime)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SUmTime)
 	if err != nil {
 		return fmt.Errorf("error al leer SUmTime: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SMntCount)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SMntCount)
 	if err != nil {
 		return fmt.Errorf("error al leer SMntCount: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SMagic)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SMagic)
 	if err != nil {
 		return fmt.Errorf("error al leer SMagic: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SInodeS)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SInodeS)
 	if err != nil {
 		return fmt.Errorf("error al leer SInodeS: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SBlockS)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SBlockS)
 	if err != nil {
 		return fmt.Errorf("error al leer SBlockS: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SFirstIno)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SFirstIno)
 	if err != nil {
 		return fmt.Errorf("error al leer SFirstIno: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SFirstBlo)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SFirstBlo)
 	if err != nil {
 		return fmt.Errorf("error al leer SFirstBlo: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SBmInodeStart)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SBmInodeStart)
 	if err != nil {
 		return fmt.Errorf("error al leer SBmInodeStart: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SBmBlockStart)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SBmBlockStart)
 	if err != nil {
 		return fmt.Errorf("error al leer SBmBlockStart: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SInodeStart)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SInodeStart)
 	if err != nil {
 		return fmt.Errorf("error al leer SInodeStart: %v", err)
 	}
 
-	err = binary.Read(file, binary.LittleEndian, &sb.SBlockStart)
+	err = binary.Read(reader, binary.LittleEndian, &sb.SBlockStart)
 	if err != nil {
 		return fmt.Errorf("error al leer SBlockStart: %v", err)
 	}
 
+	err = binary.Read(reader, binary.LittleEndian, &sb.SJournalHead)
+	if err != nil {
+		return fmt.Errorf("error al leer SJournalHead: %v", err)
+	}
+
+	err = binary.Read(reader, binary.LittleEndian, &sb.SJournalTail)
+	if err != nil {
+		return fmt.Errorf("error al leer SJournalTail: %v", err)
+	}
+
+	err = binary.Read(reader, binary.LittleEndian, &sb.SJournalPolicy)
+	if err != nil {
+		return fmt.Errorf("error al leer SJournalPolicy: %v", err)
+	}
+
+	err = binary.Read(reader, binary.LittleEndian, &sb.SJournalSize)
+	if err != nil {
+		return fmt.Errorf("error al leer SJournalSize: %v", err)
+	}
+
+	// El journal va justo después del superbloque y los bitmaps justo después del
+	// journal. En un superbloque más corto esa cuenta no cierra, porque los campos
+	// del final se leyeron de lo que sigue a un superbloque viejo
+	if sb.SMagic == 0xEF53 && !sb.hasCurrentLayout(start) {
+		return ErrOldSuperBlock
+	}
+
 	return nil
 }
 
+// hasCurrentLayout verifica que el bitmap de inodos empiece donde termina el
+// journal de un superbloque de SuperBlockSize bytes
+func (sb *SuperBlock) hasCurrentLayout(start int32) bool {
+	journalEnd := int64(start) + SuperBlockSize + int64(sb.SJournalSize)*int64(binary.Size(Journal{}))
+	return sb.SJournalSize >= 0 && int64(sb.SBmInodeStart) == journalEnd
+}
+
 func (sb *SuperBlock) Print() {
 	// Convertir el tiempo de montaje a una fecha
 	mountTime := time.Unix(int64(sb.SMtime), 0)
@@ -203,6 +232,10 @@ func (sb *SuperBlock) Print() {
 	fmt.Printf("Bitmap Block Start: %d\n", sb.SBmBlockStart)
 	fmt.Printf("Inode Start: %d\n", sb.SInodeStart)
 	fmt.Printf("Block Start: %d\n", sb.SBlockStart)
+	fmt.Printf("Journal Head: %d\n", sb.SJournalHead)
+	fmt.Printf("Journal Tail: %d\n", sb.SJournalTail)
+	fmt.Printf("Journal Policy: %s\n", JournalPolicyName(sb.SJournalPolicy))
+	fmt.Printf("Journal Size: %d\n", sb.SJournalSize)
 }
 
 func (sb *SuperBlock) PrintInodes(path string) error {
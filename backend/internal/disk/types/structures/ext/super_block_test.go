@@ -0,0 +1,60 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestDeserializeSuperBlockLayout(t *testing.T) {
+	journalSize := int32(binary.Size(Journal{}))
+
+	tests := []struct {
+		name string
+		// oldSize es el tamaño del superbloque con el que se formateó la partición
+		oldSize int32
+		slots   int32
+		want    error
+	}{
+		{name: "EXT2 actual", oldSize: SuperBlockSize, slots: 0},
+		{name: "EXT3 actual", oldSize: SuperBlockSize, slots: 4},
+		{name: "EXT2 de 68 bytes", oldSize: 68, slots: 0, want: ErrOldSuperBlock},
+		{name: "EXT3 de 80 bytes", oldSize: 80, slots: 4, want: ErrOldSuperBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := int32(100)
+			path, _ := newJournalDisk(t, 8)
+
+			sb := &SuperBlock{
+				SFilesystemType: 2,
+				SMagic:          0xEF53,
+				SBmInodeStart:   start + tt.oldSize + tt.slots*journalSize,
+			}
+			if tt.slots > 0 {
+				sb.SFilesystemType = 3
+				sb.SJournalSize = tt.slots
+			}
+			if err := sb.SerializeSuperBlock(path, start); err != nil {
+				t.Fatal(err)
+			}
+
+			// Lo que sigue a un superbloque viejo es el journal o el bitmap de inodos
+			var next bytes.Buffer
+			for i := int32(0); i < tt.slots; i++ {
+				binary.Write(&next, binary.LittleEndian, &Journal{J_count: i})
+			}
+			next.Write(bytes.Repeat([]byte{'1'}, 16))
+			if err := writeDisk(path, int64(start+tt.oldSize), next.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+
+			err := (&SuperBlock{}).DeserializeSuperBlock(path, start)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("DeserializeSuperBlock = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tipos de registro del journal transaccional. Ocupan la misma posición que
+// I_operation, por lo que se distinguen de las entradas de operaciones (mkdir, mkfile...)
+const (
+	TxBegin  = "TXBEGIN"
+	TxData   = "TXDATA"
+	TxCommit = "TXCOMMIT"
+)
+
+// txDataSize es la cantidad de bytes de imagen que caben en un slot del journal
+const txDataSize = 280
+
+// TxRecord es un registro del journal transaccional. Tiene el mismo tamaño que
+// Journal para poder usar los mismos slots del área de journaling
+type TxRecord struct {
+	T_count  int32            // 4 bytes - Índice del slot (misma posición que J_count)
+	T_kind   [10]byte         // 10 bytes - TXBEGIN, TXDATA o TXCOMMIT
+	T_id     int32            // 4 bytes - Identificador de la transacción
+	T_seq    int32            // 4 bytes - Orden del registro dentro de la transacción
+	T_offset int64            // 8 bytes - Posición del disco donde se aplica la imagen
+	T_length int32            // 4 bytes - Bytes válidos en T_data
+	T_data   [txDataSize]byte // 280 bytes - Imagen de los bytes a escribir
+	T_date   float32          // 4 bytes
+	// Total: 318 bytes
+}
+
+// txState guarda las escrituras pendientes de la transacción activa de un disco
+type txState struct {
+	path           string
+	partitionStart int32
+	journalStart   int64          // Inicio del área de journaling
+	slots          int32          // Cantidad de slots del área de journaling
+	id             int32          // Identificador de la transacción
+	pending        map[int64]byte // Bytes escritos durante la transacción, por posición
+	aborted        bool           // Una transacción anidada fue descartada
+	firstSlot      int32          // Slot desde el que se escriben los registros (después del tail)
+}
+
+// Transaction agrupa las escrituras de una operación para aplicarlas de forma
+// atómica: las imágenes se escriben primero en el journal entre un registro
+// TXBEGIN y un TXCOMMIT, y solo después se aplican en su posición definitiva
+type Transaction struct {
+	state  *txState // nil si la partición no tiene journaling
+	nested bool     // La transacción pertenece a otra iniciada antes sobre el mismo disco
+	done   bool
+}
+
+var (
+	txMutex  sync.Mutex
+	activeTx = map[string]*txState{} // Transacción activa por archivo de disco
+	lastTxID = int32(time.Now().Unix())
+)
+
+// BeginTransaction inicia una transacción sobre la partición que comienza en
+// partitionStart. Mientras esté activa, las estructuras serializadas en path
+// quedan pendientes hasta Commit y las lecturas ven los cambios pendientes.
+// Si la partición no tiene área de journaling, las escrituras van directo al disco.
+func BeginTransaction(path string, partitionStart int32) (*Transaction, error) {
+	txMutex.Lock()
+	if state, ok := activeTx[path]; ok {
+		txMutex.Unlock()
+		return &Transaction{state: state, nested: true}, nil
+	}
+	txMutex.Unlock()
+
+	sb := &SuperBlock{}
+	err := sb.DeserializeSuperBlock(path, partitionStart)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	journalStart, slots := sb.journalArea(partitionStart)
+	if slots <= 0 {
+		return &Transaction{}, nil
+	}
+
+	txMutex.Lock()
+	defer txMutex.Unlock()
+
+	lastTxID++
+	state := &txState{
+		path:           path,
+		partitionStart: partitionStart,
+		journalStart:   journalStart,
+		slots:          slots,
+		id:             lastTxID,
+		pending:        map[int64]byte{},
+	}
+	activeTx[path] = state
+
+	return &Transaction{state: state}, nil
+}
+
+// Commit escribe la transacción en el journal y luego la aplica en el disco.
+// En una transacción anidada no hace nada: los cambios se aplican con la externa.
+func (tx *Transaction) Commit() error {
+	if tx.done || tx.state == nil {
+		tx.done = true
+		return nil
+	}
+	tx.done = true
+
+	if tx.nested {
+		return nil
+	}
+
+	// El espacio en el journal se reserva mientras la transacción sigue activa,
+	// porque recuperar entradas antiguas modifica el superbloque pendiente
+	var err error
+	if !tx.state.aborted {
+		err = tx.state.reserve()
+	}
+
+	txMutex.Lock()
+	delete(activeTx, tx.state.path)
+	txMutex.Unlock()
+
+	if tx.state.aborted {
+		return fmt.Errorf("la transacción fue abortada, no se aplicaron los cambios")
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.state.flush()
+}
+
+// reserve ubica los registros de la transacción en la zona libre del journal
+// circular, a continuación del tail. Si no hay espacio se aplica la política del
+// superbloque: con JournalOverwrite se recuperan las entradas más antiguas y con
+// JournalError la transacción falla con ErrJournalFull. Una transacción que no
+// cabe ni con el journal vacío falla con ErrTransactionTooLarge y no se aplica.
+func (s *txState) reserve() error {
+	sb := &SuperBlock{}
+	err := sb.DeserializeSuperBlock(s.path, s.partitionStart)
+	if err != nil {
+		return fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	// TXBEGIN + un TXDATA por rango + TXCOMMIT
+	needed := int32(len(s.runs()) + 2)
+	if needed > s.slots {
+		return fmt.Errorf("%w: necesita %d slots y el journal tiene %d", ErrTransactionTooLarge, needed, s.slots)
+	}
+
+	for needed > s.slots-sb.JournalUsed() {
+		if sb.SJournalPolicy == JournalError {
+			return ErrJournalFull
+		}
+
+		sb.SJournalHead += needed - (s.slots - sb.JournalUsed())
+		fmt.Println("El journaling está lleno, se recuperarán las entradas más antiguas.")
+
+		// El nuevo head forma parte de la transacción y puede agregar un rango más
+		err = sb.SerializeSuperBlock(s.path, s.partitionStart)
+		if err != nil {
+			return err
+		}
+		needed = int32(len(s.runs()) + 2)
+	}
+
+	s.firstSlot = sb.SJournalTail % s.slots
+	return nil
+}
+
+// Rollback descarta los cambios pendientes si la transacción no se confirmó.
+// Se puede llamar con defer después de BeginTransaction.
+func (tx *Transaction) Rollback() {
+	if tx.done || tx.state == nil {
+		return
+	}
+	tx.done = true
+
+	if tx.nested {
+		tx.state.aborted = true
+		return
+	}
+
+	txMutex.Lock()
+	delete(activeTx, tx.state.path)
+	txMutex.Unlock()
+}
+
+// writeDisk escribe bytes en el disco. Si hay una transacción activa sobre el
+// archivo, la escritura queda pendiente hasta el commit.
+func writeDisk(path string, offset int64, data []byte) error {
+	txMutex.Lock()
+	state := activeTx[path]
+	txMutex.Unlock()
+
+	if state != nil {
+		for i, b := range data {
+			state.pending[offset+int64(i)] = b
+		}
+		return nil
+	}
+
+	return writeDiskDirect(path, offset, data)
+}
+
+// writeDiskDirect escribe bytes en el disco aunque haya una transacción activa.
+// Se usa para el contenido de los bloques recién reservados de un archivo: nada
+// los referencia hasta que la transacción confirma el inodo y los bitmaps, así
+// que escribirlos antes del commit (como el modo ordered de ext3) no deja el
+// disco inconsistente si la operación falla, y el journal solo guarda metadatos
+func writeDiskDirect(path string, offset int64, data []byte) error {
+	txMutex.Lock()
+	if state := activeTx[path]; state != nil {
+		// Una imagen pendiente en la misma posición taparía estos bytes al leer
+		for i := range data {
+			delete(state.pending, offset+int64(i))
+		}
+	}
+	txMutex.Unlock()
+
+	file, err := OpenDisk(path, os.O_WRONLY|os.O_CREATE)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteAt(data, offset)
+	return err
+}
+
+// readDisk lee bytes del disco aplicando encima las escrituras pendientes de la
+// transacción activa sobre el archivo
+func readDisk(path string, offset int64, size int) ([]byte, error) {
+	file, err := OpenDisk(path, os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buffer := make([]byte, size)
+	n, err := file.ReadAt(buffer, offset)
+	if err != nil && !(err == io.EOF && n > 0) {
+		return nil, err
+	}
+
+	txMutex.Lock()
+	state := activeTx[path]
+	txMutex.Unlock()
+
+	if state != nil {
+		for i := range buffer {
+			if b, ok := state.pending[offset+int64(i)]; ok {
+				buffer[i] = b
+			}
+		}
+	}
+
+	return buffer, nil
+}
+
+// txRun es un rango contiguo de bytes pendientes
+type txRun struct {
+	offset int64
+	data   []byte
+}
+
+// runs agrupa los bytes pendientes en rangos contiguos de a lo sumo txDataSize bytes
+func (s *txState) runs() []txRun {
+	offsets := make([]int64, 0, len(s.pending))
+	for offset := range s.pending {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	var runs []txRun
+	for _, offset := range offsets {
+		last := len(runs) - 1
+		if last >= 0 && runs[last].offset+int64(len(runs[last].data)) == offset && len(runs[last].data) < txDataSize {
+			runs[last].data = append(runs[last].data, s.pending[offset])
+			continue
+		}
+		runs = append(runs, txRun{offset: offset, data: []byte{s.pending[offset]}})
+	}
+
+	return runs
+}
+
+// flush escribe los registros de la transacción en slots libres del journal,
+// aplica las imágenes en su posición y libera los slots (checkpoint)
+func (s *txState) flush() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	runs := s.runs()
+
+	// TXBEGIN + un TXDATA por rango + TXCOMMIT
+	records := make([]TxRecord, 0, len(runs)+2)
+	records = append(records, s.newRecord(TxBegin, 0, nil))
+	for _, run := range runs {
+		records = append(records, s.newRecord(TxData, run.offset, run.data))
+	}
+	records = append(records, s.newRecord(TxCommit, 0, nil))
+	for i := range records {
+		records[i].T_seq = int32(i)
+	}
+
+	file, err := OpenDisk(s.path, os.O_RDWR)
+	if err != nil {
+		return fmt.Errorf("error al abrir el disco: %v", err)
+	}
+	defer file.Close()
+
+	slots := make([]int32, len(records))
+	for i := range slots {
+		slots[i] = (s.firstSlot + int32(i)) % s.slots
+	}
+
+	// 1. Registros de la transacción y, una vez en disco, el registro de commit
+	for i, record := range records[:len(records)-1] {
+		err = writeTxRecord(file, s.journalStart, slots[i], record)
+		if err != nil {
+			return fmt.Errorf("error al escribir en el journal: %v", err)
+		}
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+
+	err = writeTxRecord(file, s.journalStart, slots[len(slots)-1], records[len(records)-1])
+	if err != nil {
+		return fmt.Errorf("error al escribir el commit en el journal: %v", err)
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+
+	// 2. Aplicar las imágenes en su posición definitiva
+	err = applyRuns(file, runs)
+	if err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+
+	// 3. Checkpoint: los registros ya no son necesarios
+	return clearSlots(file, s.journalStart, slots)
+}
+
+// newRecord crea un registro de la transacción
+func (s *txState) newRecord(kind string, offset int64, data []byte) TxRecord {
+	record := TxRecord{
+		T_id:     s.id,
+		T_offset: offset,
+		T_length: int32(len(data)),
+		T_date:   float32(time.Now().Unix()),
+	}
+	copy(record.T_kind[:], kind)
+	copy(record.T_data[:], data)
+	return record
+}
+
+// writeTxRecord escribe un registro en un slot del journal
+func writeTxRecord(file DiskFile, journalStart int64, slot int32, record TxRecord) error {
+	record.T_count = slot
+
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, &record)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteAt(buf.Bytes(), journalStart+int64(binary.Size(Journal{}))*int64(slot))
+	return err
+}
+
+// applyRuns escribe los rangos de bytes en su posición definitiva
+func applyRuns(file DiskFile, runs []txRun) error {
+	for _, run := range runs {
+		_, err := file.WriteAt(run.data, run.offset)
+		if err != nil {
+			return fmt.Errorf("error al aplicar la transacción en el disco: %v", err)
+		}
+	}
+	return nil
+}
+
+// clearSlots deja los slots del journal como los inicializa mkfs
+func clearSlots(file DiskFile, journalStart int64, slots []int32) error {
+	for _, slot := range slots {
+		buf := new(bytes.Buffer)
+		err := binary.Write(buf, binary.LittleEndian, &Journal{J_count: slot})
+		if err != nil {
+			return err
+		}
+
+		_, err = file.WriteAt(buf.Bytes(), journalStart+int64(buf.Len())*int64(slot))
+		if err != nil {
+			return fmt.Errorf("error al liberar el slot %d del journal: %v", slot, err)
+		}
+	}
+	return file.Sync()
+}
+
+// journalArea devuelve el inicio y la cantidad de slots del área de journaling
+// según el tamaño registrado en el superbloque. EXT2 no tiene slots.
+func (sb *SuperBlock) journalArea(partitionStart int32) (int64, int32) {
+	if !sb.HasJournal() {
+		return 0, 0
+	}
+
+	journalStart := int64(partitionStart) + int64(binary.Size(SuperBlock{}))
+
+	// Un superbloque dañado no debe hacer que el journal invada el bitmap de inodos
+	available := (int64(sb.SBmInodeStart) - journalStart) / int64(binary.Size(Journal{}))
+	slots := min(int64(sb.SJournalSize), max(available, 0))
+
+	return journalStart, int32(slots)
+}
+
+// isTxRecord indica si un slot del journal contiene un registro transaccional
+func isTxRecord(journal Journal) bool {
+	operation := string(bytes.TrimRight(journal.J_content.I_operation[:], "\x00"))
+	return operation == TxBegin || operation == TxData || operation == TxCommit
+}
+
+// ReplayJournal se ejecuta al montar una partición: vuelve a aplicar las
+// transacciones que llegaron a su TXCOMMIT pero pudieron no aplicarse en el
+// disco, descarta las incompletas y libera sus slots.
+// Devuelve la cantidad de transacciones reaplicadas y descartadas.
+func ReplayJournal(path string, partitionStart int32) (int, int, error) {
+	sb := &SuperBlock{}
+	err := sb.DeserializeSuperBlock(path, partitionStart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al leer el superbloque: %v", err)
+	}
+
+	journalStart, slots := sb.journalArea(partitionStart)
+	if slots <= 0 {
+		return 0, 0, nil
+	}
+
+	file, err := OpenDisk(path, os.O_RDWR)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al abrir el disco: %v", err)
+	}
+	defer file.Close()
+
+	records := map[int32][]TxRecord{}
+	committed := map[int32]bool{}
+	var used []int32
+
+	slotSize := int64(binary.Size(Journal{}))
+	for i := int32(0); i < slots; i++ {
+		buffer := make([]byte, slotSize)
+		_, err := file.ReadAt(buffer, journalStart+slotSize*int64(i))
+		if err != nil {
+			return 0, 0, fmt.Errorf("error al leer el journal: %v", err)
+		}
+
+		journal := Journal{}
+		err = binary.Read(bytes.NewReader(buffer), binary.LittleEndian, &journal)
+		if err != nil || !isTxRecord(journal) {
+			continue
+		}
+
+		record := TxRecord{}
+		err = binary.Read(bytes.NewReader(buffer), binary.LittleEndian, &record)
+		if err != nil {
+			continue
+		}
+
+		used = append(used, i)
+		switch string(bytes.TrimRight(record.T_kind[:], "\x00")) {
+		case TxData:
+			records[record.T_id] = append(records[record.T_id], record)
+		case TxCommit:
+			committed[record.T_id] = true
+		}
+	}
+
+	if len(used) == 0 {
+		return 0, 0, nil
+	}
+
+	replayed, discarded := 0, 0
+	for id, data := range records {
+		if !committed[id] {
+			discarded++
+			continue
+		}
+
+		sort.Slice(data, func(i, j int) bool { return data[i].T_seq < data[j].T_seq })
+		for _, record := range data {
+			_, err = file.WriteAt(record.T_data[:record.T_length], record.T_offset)
+			if err != nil {
+				return replayed, discarded, fmt.Errorf("error al reaplicar la transacción %d: %v", id, err)
+			}
+		}
+		replayed++
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return replayed, discarded, err
+	}
+
+	return replayed, discarded, clearSlots(file, journalStart, used)
+}
@@ -0,0 +1,154 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newJournalDisk crea un disco con solo el superbloque de una partición EXT3
+// cuyo journal tiene la cantidad de slots indicada, seguido de 4 KB de datos
+func newJournalDisk(t *testing.T, slots int32) (string, int64) {
+	t.Helper()
+
+	sbSize := int32(binary.Size(SuperBlock{}))
+	dataStart := sbSize + slots*int32(binary.Size(Journal{}))
+
+	path := filepath.Join(t.TempDir(), "disco.mia")
+	if err := os.WriteFile(path, make([]byte, dataStart+4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sb := &SuperBlock{
+		SFilesystemType: 3,
+		SMagic:          0xEF53,
+		SJournalSize:    slots,
+		SBmInodeStart:   dataStart,
+	}
+	if err := sb.SerializeSuperBlock(path, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, int64(dataStart)
+}
+
+func TestTransactionCommit(t *testing.T) {
+	path, data := newJournalDisk(t, 4)
+
+	tx, err := BeginTransaction(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// Dos rangos separados: TXBEGIN + 2 TXDATA + TXCOMMIT llenan el journal
+	for _, offset := range []int64{data, data + 100} {
+		if err := writeDisk(path, offset, []byte("abc")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	for _, offset := range []int64{data, data + 100} {
+		got, err := readDisk(path, offset, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "abc" {
+			t.Errorf("bytes en %d = %q, se esperaba %q", offset, got, "abc")
+		}
+	}
+}
+
+func TestTransactionTooLarge(t *testing.T) {
+	path, data := newJournalDisk(t, 4)
+
+	tx, err := BeginTransaction(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// Tres rangos separados necesitan 5 slots y el journal solo tiene 4
+	for _, offset := range []int64{data, data + 100, data + 200} {
+		if err := writeDisk(path, offset, []byte("abc")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = tx.Commit()
+	if !errors.Is(err, ErrTransactionTooLarge) {
+		t.Fatalf("Commit = %v, se esperaba ErrTransactionTooLarge", err)
+	}
+
+	// Ninguna de las escrituras llega al disco
+	got, err := readDisk(path, data, 203)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, make([]byte, 203)) {
+		t.Errorf("la transacción rechazada modificó el disco")
+	}
+}
+
+func TestTransactionLargeFile(t *testing.T) {
+	silenceOutput(t)
+
+	// Un disco de 2 MB convertido a EXT3 con tablas y journal del mismo tamaño que mkfs
+	const size = 2 * 1024 * 1024
+	path, sb := newTestDisk(t, size)
+	n := int32((size - SuperBlockSize) / (binary.Size(Journal{}) + 4 + INodeSize + 3*FileBlockSize))
+	if err := sb.AddJournalArea(path, 0, n); err != nil {
+		t.Fatal(err)
+	}
+
+	content := make([]byte, MaxFileSize-100)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	tx, err := BeginTransaction(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if err := sb.CreateFile(path, nil, "grande.bin", 0, "", false, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	inodeIndex, err := sb.FindFileInode(path, nil, "grande.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.WriteFileContent(path, inodeIndex, bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.SerializeSuperBlock(path, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Los bloques del archivo van directo al disco: la transacción solo guarda
+	// el bitmap, el inodo, la entrada de la carpeta y el superbloque
+	if pending := len(activeTx[path].pending); pending > len(content)/10 {
+		t.Errorf("la transacción tiene %d bytes pendientes para un archivo de %d", pending, len(content))
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	inode := readInode(t, path, sb, inodeIndex)
+	got, err := sb.InodeContent(path, inode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("el contenido leído no coincide con el escrito (%d bytes, se esperaban %d)", len(got), len(content))
+	}
+
+	expectProblems(t, check(t, path, sb, false))
+}
@@ -0,0 +1,107 @@
+package ext2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// AddJournalArea convierte una partición EXT2 en EXT3 sin perder sus archivos.
+// El journal ocupa el espacio que sigue al superbloque, así que las tablas se
+// reducen a n inodos y 3n bloques y se mueven detrás de sus n slots. Los punteros
+// de inodos, directorios y bloques de apuntadores son índices dentro de cada
+// tabla y siguen siendo válidos; solo cambian las direcciones del superbloque.
+// Falla sin modificar el disco si hay inodos o bloques en uso fuera de las tablas reducidas.
+func (sb *SuperBlock) AddJournalArea(path string, partitionStart int32, n int32) error {
+	if sb.SMagic != 0xEF53 {
+		return fmt.Errorf("el superbloque no tiene un número mágico válido")
+	}
+	if sb.HasJournal() {
+		return fmt.Errorf("la partición ya tiene journaling")
+	}
+	if n <= 0 {
+		return fmt.Errorf("la partición no tiene espacio para el journal")
+	}
+
+	inodesTotal, blocksTotal := sb.InodesTotal(), sb.BlocksTotal()
+	if n > inodesTotal || 3*n > blocksTotal {
+		return fmt.Errorf("la partición no tiene espacio para el journal")
+	}
+
+	inodeBitmap, err := readBitmap(path, int64(sb.SBmInodeStart), inodesTotal)
+	if err != nil {
+		return fmt.Errorf("error al leer el bitmap de inodos: %v", err)
+	}
+	blockBitmap, err := readBitmap(path, int64(sb.SBmBlockStart), blocksTotal)
+	if err != nil {
+		return fmt.Errorf("error al leer el bitmap de bloques: %v", err)
+	}
+
+	// Los inodos y bloques se asignan en orden, así que basta con que el último en
+	// uso quepa en las tablas reducidas
+	inodesUsed := max(sb.SInodesCount, (sb.SFirstIno-sb.SInodeStart)/sb.SInodeS, lastUsed(inodeBitmap)+1)
+	blocksUsed := max(sb.SBlocksCount, (sb.SFirstBlo-sb.SBlockStart)/sb.SBlockS, lastUsed(blockBitmap)+1)
+	if inodesUsed > n || blocksUsed > 3*n {
+		return fmt.Errorf("no hay espacio libre para el journal: hay %d inodos y %d bloques en uso y con journal caben %d y %d",
+			inodesUsed, blocksUsed, n, 3*n)
+	}
+
+	inodeTable, err := readDisk(path, int64(sb.SInodeStart), int(n*sb.SInodeS))
+	if err != nil {
+		return fmt.Errorf("error al leer la tabla de inodos: %v", err)
+	}
+	blockTable, err := readDisk(path, int64(sb.SBlockStart), int(3*n*sb.SBlockS))
+	if err != nil {
+		return fmt.Errorf("error al leer la tabla de bloques: %v", err)
+	}
+
+	// Armar la nueva disposición completa en memoria: las áreas nuevas se
+	// superponen con las viejas y no se pueden copiar en su lugar
+	journalStart := partitionStart + int32(binary.Size(SuperBlock{}))
+	image := new(bytes.Buffer)
+	for i := int32(0); i < n; i++ {
+		err = binary.Write(image, binary.LittleEndian, &Journal{J_count: i})
+		if err != nil {
+			return fmt.Errorf("error al inicializar el journal: %v", err)
+		}
+	}
+
+	bmInodeStart := journalStart + int32(image.Len())
+	image.Write(inodeBitmap[:n])
+	bmBlockStart := journalStart + int32(image.Len())
+	image.Write(blockBitmap[:3*n])
+	inodeStart := journalStart + int32(image.Len())
+	image.Write(inodeTable)
+	blockStart := journalStart + int32(image.Len())
+	image.Write(blockTable)
+
+	err = writeDisk(path, int64(journalStart), image.Bytes())
+	if err != nil {
+		return fmt.Errorf("error al escribir las tablas: %v", err)
+	}
+
+	sb.SFirstIno = inodeStart + (sb.SFirstIno - sb.SInodeStart)
+	sb.SFirstBlo = blockStart + (sb.SFirstBlo - sb.SBlockStart)
+	sb.SFreeInodesCount -= inodesTotal - n
+	sb.SFreeBlocksCount -= blocksTotal - 3*n
+	sb.SBmInodeStart = bmInodeStart
+	sb.SBmBlockStart = bmBlockStart
+	sb.SInodeStart = inodeStart
+	sb.SBlockStart = blockStart
+	sb.SFilesystemType = 3
+	sb.SJournalSize = n
+	sb.SJournalHead = 0
+	sb.SJournalTail = 0
+
+	return sb.SerializeSuperBlock(path, partitionStart)
+}
+
+// lastUsed devuelve la posición de la última entrada en uso de un bitmap, o -1
+func lastUsed(bitmap []byte) int32 {
+	for i := len(bitmap) - 1; i >= 0; i-- {
+		if bitmapUsed(bitmap[i]) {
+			return int32(i)
+		}
+	}
+	return -1
+}
@@ -0,0 +1,233 @@
+package ext2
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// BlockRef describe un bloque referenciado por un inodo
+type BlockRef struct {
+	Block   int32 // Número de bloque
+	Level   int   // Nivel de indirección (0 = directo, 1 = simple, 2 = doble, 3 = triple)
+	Pointer bool  // Indica si es un bloque de punteros en lugar de un bloque de datos
+}
+
+// DirEntry representa una entrada de directorio (sin "." ni "..")
+type DirEntry struct {
+	Name  string
+	Inode int32
+}
+
+// InodeBlockList devuelve todos los bloques usados por un inodo, incluyendo
+// los bloques de punteros de los niveles indirectos
+func (sb *SuperBlock) InodeBlockList(path string, inode *INode) ([]BlockRef, error) {
+	var refs []BlockRef
+
+	// Bloques directos (0-11)
+	for i := 0; i < 12; i++ {
+		if sb.isValidBlock(inode.IBlock[i]) {
+			refs = append(refs, BlockRef{Block: inode.IBlock[i], Level: 0})
+		}
+	}
+
+	// Bloques indirectos simple (12), doble (13) y triple (14)
+	for level := 1; level <= 3; level++ {
+		blockIndex := inode.IBlock[11+level]
+		if !sb.isValidBlock(blockIndex) {
+			continue
+		}
+
+		err := sb.collectIndirectBlocks(path, blockIndex, level, level, &refs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return refs, nil
+}
+
+// collectIndirectBlocks recorre un bloque de punteros y agrega sus bloques a la lista
+func (sb *SuperBlock) collectIndirectBlocks(path string, blockIndex int32, level int, depth int, refs *[]BlockRef) error {
+	*refs = append(*refs, BlockRef{Block: blockIndex, Level: level, Pointer: true})
+
+	pointerBlock := &PointerBlock{}
+	err := pointerBlock.Deserialize(path, int64(sb.SBlockStart+(blockIndex*sb.SBlockS)))
+	if err != nil {
+		return fmt.Errorf("error al leer el bloque de punteros %d: %v", blockIndex, err)
+	}
+
+	for _, ptr := range pointerBlock.PContent {
+		if !sb.isValidBlock(ptr) {
+			continue
+		}
+
+		// En el último nivel los punteros apuntan a bloques de datos
+		if depth == 1 {
+			*refs = append(*refs, BlockRef{Block: ptr, Level: level})
+			continue
+		}
+
+		err = sb.collectIndirectBlocks(path, ptr, level, depth-1, refs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InodesTotal devuelve la cantidad total de inodos de la partición, calculada a
+// partir de la disposición del superbloque para no depender de los contadores
+func (sb *SuperBlock) InodesTotal() int32 {
+	return sb.SBmBlockStart - sb.SBmInodeStart
+}
+
+// BlocksTotal devuelve la cantidad total de bloques de la partición, calculada a
+// partir de la disposición del superbloque para no depender de los contadores
+func (sb *SuperBlock) BlocksTotal() int32 {
+	return sb.SInodeStart - sb.SBmBlockStart
+}
+
+// isValidBlock verifica que un número de bloque esté dentro de la tabla de bloques
+func (sb *SuperBlock) isValidBlock(blockIndex int32) bool {
+	return blockIndex >= 0 && blockIndex < sb.BlocksTotal()
+}
+
+// DirectoryEntries devuelve las entradas de un directorio, omitiendo "." y ".."
+func (sb *SuperBlock) DirectoryEntries(path string, inode *INode) ([]DirEntry, error) {
+	if inode.IType[0] != '0' {
+		return nil, fmt.Errorf("el inodo no es un directorio")
+	}
+
+	refs, err := sb.InodeBlockList(path, inode)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []DirEntry
+	for _, ref := range refs {
+		if ref.Pointer {
+			continue
+		}
+
+		dirBlock := &DirBlock{}
+		err = dirBlock.Deserialize(path, int64(sb.SBlockStart+(ref.Block*sb.SBlockS)))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer el bloque de directorio %d: %v", ref.Block, err)
+		}
+
+		for _, entry := range dirBlock.BContent {
+			if entry.BInodo == -1 {
+				continue
+			}
+
+			entryName := sb.EntryName(path, entry)
+			if entryName == "" || entryName == "." || entryName == ".." {
+				continue
+			}
+
+			entries = append(entries, DirEntry{Name: entryName, Inode: entry.BInodo})
+		}
+	}
+
+	return entries, nil
+}
+
+// InodeContent devuelve el contenido de un archivo a partir de su inodo. A
+// diferencia de ReadFile no actualiza la fecha de acceso
+func (sb *SuperBlock) InodeContent(path string, inode *INode) ([]byte, error) {
+	content := bytes.NewBuffer(make([]byte, 0, max(inode.ISize, 0)))
+	err := sb.CopyFileContent(path, inode, content)
+	if err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// FileContent busca un archivo por su ruta y devuelve su contenido sin
+// actualizar la fecha de acceso, por lo que se puede usar con el disco bloqueado
+// solo para lectura
+func (sb *SuperBlock) FileContent(path string, parentDirs []string, fileName string) (string, error) {
+	inodeIndex, err := sb.FindFileInode(path, parentDirs, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	inode := &INode{}
+	err = inode.Deserialize(path, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
+	if err != nil {
+		return "", err
+	}
+	if inode.IType[0] != '1' {
+		return "", fmt.Errorf("'%s' no es un archivo", fileName)
+	}
+
+	content, err := sb.InodeContent(path, inode)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// DiskUsage calcula recursivamente los bytes y bloques usados a partir de un inodo.
+// Si visit no es nil, se llama con el uso acumulado de cada entrada recorrida.
+func (sb *SuperBlock) DiskUsage(
+	path string,
+	inodeIndex int32,
+	entryPath string,
+	visit func(entryPath string, bytes int64, blocks int32),
+) (int64, int32, error) {
+	return sb.diskUsage(path, inodeIndex, entryPath, visit, map[int32]bool{})
+}
+
+// diskUsage implementa DiskUsage evitando recorrer dos veces el mismo inodo
+func (sb *SuperBlock) diskUsage(
+	path string,
+	inodeIndex int32,
+	entryPath string,
+	visit func(entryPath string, bytes int64, blocks int32),
+	visited map[int32]bool,
+) (int64, int32, error) {
+	if visited[inodeIndex] {
+		return 0, 0, nil
+	}
+	visited[inodeIndex] = true
+
+	inode := &INode{}
+	err := inode.Deserialize(path, int64(sb.SInodeStart+(inodeIndex*sb.SInodeS)))
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al leer el inodo %d: %v", inodeIndex, err)
+	}
+
+	refs, err := sb.InodeBlockList(path, inode)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	totalBytes := int64(inode.ISize)
+	totalBlocks := int32(len(refs))
+
+	if inode.IType[0] == '0' {
+		entries, err := sb.DirectoryEntries(path, inode)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for _, entry := range entries {
+			childPath := strings.TrimSuffix(entryPath, "/") + "/" + entry.Name
+			childBytes, childBlocks, err := sb.diskUsage(path, entry.Inode, childPath, visit, visited)
+			if err != nil {
+				return 0, 0, err
+			}
+			totalBytes += childBytes
+			totalBlocks += childBlocks
+		}
+	}
+
+	if visit != nil {
+		visit(entryPath, totalBytes, totalBlocks)
+	}
+
+	return totalBytes, totalBlocks, nil
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommandStreamRequest es el cuerpo del endpoint de ejecución con streaming
+type CommandStreamRequest struct {
+	Command     string `json:"command"`
+	StopOnError bool   `json:"stopOnError"` // Detener el script en la primera línea con error (como "on error stop")
+}
+
+// LineEvent es el resultado de ejecutar una línea del script
+type LineEvent struct {
+	Line       int    `json:"line"`           // Número de línea dentro del script (desde 1)
+	File       string `json:"file,omitempty"` // Script incluido con exec al que pertenece la línea
+	Command    string `json:"command"`
+	Input      string `json:"input"`
+	Status     string `json:"status"` // "ok" o "error"
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// DoneEvent es el evento final con el resumen de la ejecución
+type DoneEvent struct {
+	Executed int    `json:"executed"`
+	Failed   int    `json:"failed"`
+	Skipped  int    `json:"skipped"` // Líneas del script principal que no se ejecutaron al detenerse
+	Stopped  bool   `json:"stopped"`
+	Token    string `json:"token,omitempty"`
+}
+
+// HandleCommandStream ejecuta un script línea por línea y envía un evento por
+// cada línea en cuanto termina. Por defecto usa Server-Sent Events (eventos
+// "line" y "done"); con ?format=ndjson envía un objeto JSON por línea
+func HandleCommandStream(c *gin.Context) {
+	var req CommandStreamRequest
+
+	// Hacer bind del JSON al struct
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"output": err.Error(),
+		})
+		return
+	}
+
+	ndjson := false
+	switch strings.ToLower(c.DefaultQuery("format", "sse")) {
+	case "sse":
+	case "ndjson":
+		ndjson = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"output": "formato no válido: use sse o ndjson",
+		})
+		return
+	}
+
+	// Resolver la sesión del cliente que envía el script
+	session := sessionFromRequest(c)
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	if ndjson {
+		c.Header("Content-Type", "application/x-ndjson")
+	} else {
+		c.Header("Content-Type", "text/event-stream")
+	}
+
+	// send escribe un evento con el formato elegido y lo envía de inmediato
+	send := func(event string, data interface{}) {
+		if ndjson {
+			payload, err := json.Marshal(gin.H{"event": event, "data": data})
+			if err != nil {
+				return
+			}
+			c.Writer.Write(append(payload, '\n'))
+		} else {
+			c.SSEvent(event, data)
+		}
+		c.Writer.Flush()
+	}
+
+	done := DoneEvent{}
+	runner := newScriptRunner(c.Request.Context(), session, req.StopOnError, func(event LineEvent) {
+		done.Executed++
+		if event.Status == "error" {
+			done.Failed++
+		}
+		send("line", event)
+	})
+	runner.run(req.Command, "")
+
+	done.Stopped = runner.stopped
+	done.Skipped = runner.skipped
+	done.Token = session.Token
+	send("done", done)
+}
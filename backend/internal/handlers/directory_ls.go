@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -23,20 +24,9 @@ func HandleDirectoryLs(c *gin.Context) {
 	partitionName := c.Query("partition")
 	dirPath := c.Query("path")
 
-	// Validar parámetros
-	if diskPath == "" {
-		c.JSON(http.StatusBadRequest, DirectoryLsResponse{
-			Success: false,
-			Message: "No se proporcionó la ruta del disco (parámetro 'disk')",
-		})
-		return
-	}
-
-	if partitionName == "" {
-		c.JSON(http.StatusBadRequest, DirectoryLsResponse{
-			Success: false,
-			Message: "No se proporcionó el nombre de la partición (parámetro 'partition')",
-		})
+	// Solo se listan directorios de la partición de la sesión del cliente
+	session, diskPath, partitionName, ok := requireSession(c, diskPath, partitionName)
+	if !ok {
 		return
 	}
 
@@ -46,7 +36,11 @@ func HandleDirectoryLs(c *gin.Context) {
 	}
 
 	// Obtener el listado de archivos/directorios
-	jsonContent, err := partition_operations.ListDirectory(diskPath, partitionName, dirPath)
+	jsonContent, err := partition_operations.ListDirectory(session, diskPath, partitionName, dirPath)
+	if errors.Is(err, partition_operations.ErrPermissionDenied) {
+		abortWithError(c, http.StatusForbidden, "forbidden", fmt.Sprintf("Error al listar el directorio: %v", err))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, DirectoryLsResponse{
 			Success: false,
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	partition_operations "disk.simulator.com/m/v2/internal/disk/operations/partitions"
+	ext2 "disk.simulator.com/m/v2/internal/disk/types/structures/ext"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleUpload recibe un archivo en un formulario multipart (campo "file") y
+// lo crea en la ruta "path" de la partición de la sesión. Si la ruta termina en
+// "/" se usa el nombre del archivo subido; con "r=true" se crean las carpetas
+// que falten
+func HandleUpload(c *gin.Context) {
+	session, _, _, ok := requireSession(c, c.PostForm("disk"), c.PostForm("partition"))
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Se requiere el archivo en el campo 'file': " + err.Error(),
+		})
+		return
+	}
+
+	filePath := c.PostForm("path")
+	if filePath == "" || strings.HasSuffix(filePath, "/") {
+		filePath += path.Base(fileHeader.Filename)
+	}
+	r, _ := strconv.ParseBool(c.PostForm("r"))
+
+	if fileHeader.Size > int64(ext2.MaxFileSize) {
+		abortWithError(c, http.StatusRequestEntityTooLarge, "too_large",
+			fmt.Sprintf("El archivo de %d bytes excede el tamaño máximo de %d bytes", fileHeader.Size, ext2.MaxFileSize))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Error al leer el archivo subido: " + err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	err = partition_operations.UploadFile(session, filePath, file, fileHeader.Size, r)
+	if errors.Is(err, partition_operations.ErrPermissionDenied) {
+		abortWithError(c, http.StatusForbidden, "forbidden", "Error al subir el archivo: "+err.Error())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Error al subir el archivo: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"path":    filePath,
+		"size":    fileHeader.Size,
+	})
+}
+
+// HandleDownload envía el contenido del archivo "path" de la partición de la
+// sesión como application/octet-stream, con su tamaño en Content-Length
+func HandleDownload(c *gin.Context) {
+	session, _, _, ok := requireSession(c, c.Query("disk"), c.Query("partition"))
+	if !ok {
+		return
+	}
+
+	filePath := c.Query("path")
+	if filePath == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Se requiere path",
+		})
+		return
+	}
+
+	started := false
+	err := partition_operations.DownloadFile(session, filePath, func(size int64) io.Writer {
+		started = true
+		c.Header("Content-Type", "application/octet-stream")
+		c.Header("Content-Length", strconv.FormatInt(size, 10))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(filePath)))
+		c.Status(http.StatusOK)
+		return c.Writer
+	})
+
+	// Si el contenido ya empezó a enviarse no se puede cambiar la respuesta
+	if err != nil && started {
+		c.Abort()
+		return
+	}
+	if errors.Is(err, partition_operations.ErrPermissionDenied) {
+		abortWithError(c, http.StatusForbidden, "forbidden", "Error al descargar el archivo: "+err.Error())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Error al descargar el archivo: " + err.Error(),
+		})
+		return
+	}
+}
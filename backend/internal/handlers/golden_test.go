@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	"disk.simulator.com/m/v2/internal/disk/operations/reports"
+)
+
+// Con -update se regeneran los archivos .golden a partir de la ejecución actual:
+//
+//	go test ./internal/handlers -run TestGolden -update
+var update = flag.Bool("update", false, "regenerar los archivos golden")
+
+// goldenDir guarda los scripts (.smia) y sus resultados esperados (.golden).
+// TestMain lo convierte en ruta absoluta antes de cambiar de directorio
+var goldenDir = filepath.Join("testdata", "golden")
+
+// goldenReports son los reportes que se vuelcan de cada partición montada al
+// terminar el script; el mbr se vuelca una sola vez por disco
+var goldenReports = []string{"sb", "inode", "block"}
+
+// usersFilePrefix es el inicio de users.txt y de sus copias (p. ej. las que
+// deja import), cuyos bloques se ocultan en el volcado
+const usersFilePrefix = "1,G,root"
+
+var (
+	// Fechas de los reportes JSON y de la salida de los comandos
+	jsonDatePattern = regexp.MustCompile(`"(creationDate|mountTime|unmountTime|atime|ctime|mtime|date)": "[^"]*"`)
+	datePattern     = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?|\d{2}/\d{2}/\d{4} \d{2}:\d{2}:\d{2}`)
+	// La firma del disco es aleatoria y las contraseñas llevan una sal aleatoria
+	signaturePattern = regexp.MustCompile(`"diskSignature": -?\d+`)
+	bcryptPattern    = regexp.MustCompile(`\$2[aby]\$\d{2}\$[./A-Za-z0-9]{53}`)
+	// Resumen de fsck cuando encuentra problemas
+	fsckProblemsPattern = regexp.MustCompile(`(?m)^\d+ problems found`)
+)
+
+// fsckProblemsDirective es la línea con la que un script declara que daña la
+// partición a propósito. En los demás scripts fsck debe terminar limpio: los
+// comandos normales nunca deben dejar el sistema de archivos inconsistente
+const fsckProblemsDirective = "# fsck: problemas esperados"
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	var err error
+	goldenDir, err = filepath.Abs(goldenDir)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// El registro de discos y el estado de montaje se guardan en el directorio de
+	// MOUNT_STATE_PATH; las pruebas no deben tocar los archivos del repositorio
+	dir, err := os.MkdirTemp("", "golden-state-")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	os.Setenv("MOUNT_STATE_PATH", filepath.Join(dir, "mount_state.json"))
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// TestGolden ejecuta cada script de testdata/golden con el mismo intérprete
+// que HandleCommand sobre discos temporales y compara la salida de cada línea
+// y el estado final de los discos (MBR/EBR, superbloque, inodos y bloques)
+// con su archivo .golden. En los scripts $DIR es el directorio temporal de los
+// discos y $DATA el directorio testdata/golden, para usar archivos del host
+func TestGolden(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join(goldenDir, "*.smia"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) == 0 {
+		t.Fatalf("no hay scripts en %s", goldenDir)
+	}
+
+	for _, script := range scripts {
+		name := strings.TrimSuffix(filepath.Base(script), ".smia")
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(script)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			got := normalizeGolden(runGoldenScript(t, string(content), dir), dir)
+
+			goldenPath := strings.TrimSuffix(script, ".smia") + ".golden"
+			if *update {
+				if err := os.WriteFile(goldenPath, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("no se pudo leer %s (use -update para crearlo): %v", goldenPath, err)
+			}
+			if got != string(want) {
+				t.Errorf("%s no coincide con %s:\n%s", name, filepath.Base(goldenPath), goldenDiff(string(want), got))
+			}
+		})
+	}
+}
+
+// runGoldenScript ejecuta el script con una sesión nueva y una tabla de montaje
+// vacía, y retorna la salida de cada línea seguida del estado de los discos
+func runGoldenScript(t *testing.T, script string, dir string) string {
+	memory.GetInstance().Reset()
+	t.Cleanup(memory.GetInstance().Reset)
+
+	// Las operaciones imprimen su progreso en consola; solo interesa la salida de los comandos
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}()
+
+	allowFsckProblems := slices.Contains(strings.Split(script, "\n"), fsckProblemsDirective)
+
+	var output strings.Builder
+	runner := newScriptRunner(context.Background(), &auth.LoggedUser{}, false, func(event LineEvent) {
+		if !allowFsckProblems && fsckProblemsPattern.MatchString(event.Output) {
+			t.Errorf("línea %d: fsck encontró problemas en un script que no daña la partición:\n%s", event.Line, event.Output)
+		}
+
+		fmt.Fprintf(&output, "## %d: %s\n", event.Line, event.Input)
+		if event.Status == "error" {
+			fmt.Fprintf(&output, "error: %s\n", event.Error)
+		} else if text := strings.TrimRight(event.Output, "\n"); text != "" {
+			fmt.Fprintln(&output, text)
+		}
+	})
+	runner.vars["DIR"] = dir
+	runner.vars["DATA"] = goldenDir
+	runner.run(script, "")
+
+	output.WriteString(dumpGoldenState())
+	return output.String()
+}
+
+// dumpGoldenState vuelca como JSON el MBR de cada disco con particiones montadas
+// y los reportes de cada partición montada, ordenados por ID
+func dumpGoldenState() string {
+	mounted := slices.Clone(memory.GetInstance().GetMountedPartitions())
+	sort.Slice(mounted, func(i, j int) bool { return mounted[i].ID < mounted[j].ID })
+
+	var output strings.Builder
+	dumped := map[string]bool{}
+
+	for _, partition := range mounted {
+		if !dumped[partition.Path] {
+			dumped[partition.Path] = true
+			fmt.Fprintf(&output, "\n=== mbr %s ===\n", partition.Path)
+			output.WriteString(goldenReport("mbr", partition.ID))
+		}
+
+		for _, name := range goldenReports {
+			fmt.Fprintf(&output, "\n=== %s %s (%s) ===\n", name, partition.ID, partition.Name)
+			output.WriteString(goldenReport(name, partition.ID))
+		}
+	}
+
+	return output.String()
+}
+
+// goldenReport retorna el reporte como JSON indentado, o el error al generarlo
+func goldenReport(name string, id string) string {
+	data, err := reports.ReportData(name, id, "")
+	if err != nil {
+		return fmt.Sprintf("error: %v\n", err)
+	}
+
+	// Los hashes de users.txt quedan partidos entre bloques y no se pueden
+	// normalizar; su contenido ya aparece en la salida de cat
+	if blocks, ok := data.([]reports.BlockData); ok {
+		usersFiles := map[int32]bool{}
+		for _, block := range blocks {
+			if block.Kind == "file" && strings.HasPrefix(block.Content, usersFilePrefix) {
+				usersFiles[block.Owner] = true
+			}
+		}
+		for i := range blocks {
+			if usersFiles[blocks[i].Owner] && blocks[i].Kind == "file" {
+				blocks[i].Content = "<users.txt>"
+			}
+		}
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("error: %v\n", err)
+	}
+	return string(content) + "\n"
+}
+
+// normalizeGolden reemplaza lo que cambia entre ejecuciones: los directorios,
+// las fechas, la firma del disco y los hashes de las contraseñas. Los bytes
+// nulos de los archivos creados con -size se escriben como \0 para que los
+// archivos .golden sigan siendo texto
+func normalizeGolden(output string, dir string) string {
+	output = strings.ReplaceAll(output, dir, "$DIR")
+	output = strings.ReplaceAll(output, goldenDir, "$DATA")
+	output = jsonDatePattern.ReplaceAllString(output, `"$1": "<fecha>"`)
+	output = datePattern.ReplaceAllString(output, "<fecha>")
+	output = signaturePattern.ReplaceAllString(output, `"diskSignature": 0`)
+	output = bcryptPattern.ReplaceAllString(output, "<hash>")
+	output = strings.ReplaceAll(output, "\x00", `\0`)
+	return output
+}
+
+// goldenDiff muestra la primera línea distinta con algo de contexto
+func goldenDiff(want string, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			start := max(i-3, 0)
+			previous := strings.Join(gotLines[start:min(i, len(gotLines))], "\n")
+			return fmt.Sprintf("línea %d\n%s\n- esperado: %q\n+ obtenido: %q", i+1, previous, w, g)
+		}
+	}
+	return ""
+}
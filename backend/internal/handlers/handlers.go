@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"strings"
 
+	"disk.simulator.com/m/v2/internal/args"
 	"disk.simulator.com/m/v2/internal/commands"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,11 +27,40 @@ func HandleCommand(c *gin.Context) {
 		return
 	}
 
-	// Separar el comando línea por línea
-	lines := strings.Split(req.Command, "\n")
+	// Resolver la sesión del cliente que envía el script
+	session := sessionFromRequest(c)
+
 	var output []string
 
-	for _, line := range lines {
+	// Las líneas con error no detienen el script salvo que use "on error stop"
+	runner := newScriptRunner(c.Request.Context(), session, false, func(event LineEvent) {
+		if event.Status == "error" {
+			output = append(output, fmt.Sprintf("Error: %s \n", event.Error))
+		} else {
+			println(event.Output)
+			output = append(output, event.Output)
+		}
+	})
+	runner.run(req.Command, "")
+
+	c.JSON(http.StatusOK, gin.H{
+		"output": strings.Join(output, "\n"),
+		"token":  session.Token,
+	})
+}
+
+// scriptLine es una línea ejecutable de un script con su número original
+type scriptLine struct {
+	Number int
+	Text   string
+}
+
+// scriptLines separa un script en líneas ejecutables, descartando líneas
+// vacías y comentarios pero conservando el número de línea de cada comando
+func scriptLines(script string) []scriptLine {
+	var lines []scriptLine
+
+	for i, line := range strings.Split(script, "\n") {
 		line = strings.TrimSpace(line)
 
 		// Ignorar líneas vacías
@@ -42,72 +73,66 @@ func HandleCommand(c *gin.Context) {
 			continue
 		}
 
-		// Eliminar cualquier comentario que esté después del comando
-		if idx := strings.Index(line, "#"); idx >= 0 {
-			line = strings.TrimSpace(line[:idx])
-		}
+		// Eliminar el comentario que esté después del comando ('#' fuera de comillas)
+		line = args.StripComment(line)
 
 		// Si después de quitar el comentario la línea está vacía, ignorarla
 		if line == "" {
 			continue
 		}
 
-		// Get the command prefix
-		parts := strings.Split(line, " ")
-		command := strings.ToLower(parts[0])
-
-		// Convertir el comando en la línea a minúsculas para mantener consistencia
-		lowercaseLine := strings.Replace(line, parts[0], command, 1)
-
-		// Verificar qué tipo de comando es
-		isDiskCommand := isDiskCommand(command)
-		isPartitionCommand := isPartitionCommand(command)
-
-		var cmdOutput string
-		var err error
-
-		if isDiskCommand {
-			// Ejecutar como comando de disco
-			cmdOutput, err = commands.ParseDiskCommand(command, lowercaseLine)
-		} else if isPartitionCommand {
-			// Ejecutar como comando de partición
-			cmdOutput, err = commands.ParsePartitionCommand(command, lowercaseLine)
-		} else if isAuthCommand(command) {
-			// Ejecutar como comando de autenticación
-			cmdOutput, err = commands.ParseAuthCommand(command, lowercaseLine)
-		} else {
-			// Comando desconocido
-			err = fmt.Errorf("comando desconocido: %s", command)
-		}
+		lines = append(lines, scriptLine{Number: i + 1, Text: line})
+	}
 
-		if err != nil {
-			output = append(output, fmt.Sprintf("Error: %s \n", err.Error()))
-		} else {
-			println(cmdOutput)
-			output = append(output, cmdOutput)
-		}
+	return lines
+}
+
+// runCommandLine ejecuta una línea de comando con la sesión del cliente y
+// retorna el nombre del comando junto con su salida
+func runCommandLine(session *auth.LoggedUser, line string) (string, string, error) {
+	// Get the command prefix
+	parts := strings.Fields(line)
+	command := strings.ToLower(parts[0])
+
+	// Convertir el comando en la línea a minúsculas para mantener consistencia
+	lowercaseLine := strings.Replace(line, parts[0], command, 1)
+
+	var cmdOutput string
+	var err error
+
+	// Verificar qué tipo de comando es
+	if isDiskCommand(command) {
+		// Ejecutar como comando de disco
+		cmdOutput, err = commands.ParseDiskCommand(session, command, lowercaseLine)
+	} else if isPartitionCommand(command) {
+		// Ejecutar como comando de partición
+		cmdOutput, err = commands.ParsePartitionCommand(session, command, lowercaseLine)
+	} else if isAuthCommand(command) {
+		// Ejecutar como comando de autenticación
+		cmdOutput, err = commands.ParseAuthCommand(session, command, lowercaseLine)
+	} else {
+		// Comando desconocido
+		err = fmt.Errorf("comando desconocido: %s", command)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"output": strings.Join(output, "\n"),
-	})
+	return command, cmdOutput, err
 }
 
 // isDiskCommand verifica si el comando es un comando de disco
 func isDiskCommand(cmd string) bool {
-	diskCommands := []string{"mkdisk", "rmdisk", "fdisk", "rep", "mount", "mounted", "unmount", "journaling", "recovery", "loss"}
+	diskCommands := []string{"mkdisk", "rmdisk", "fdisk", "rep", "mount", "mounted", "unmount", "journaling", "recovery", "loss", "snapshot", "rollback", "snapshots"}
 	return containsIgnoreCase(diskCommands, cmd)
 }
 
 // isPartitionCommand verifica si el comando es un comando de partición
 func isPartitionCommand(cmd string) bool {
-	partitionCommands := []string{"mkfs", "mkdir", "mkfile", "cat", "rename", "move", "copy", "find", "chown", "chmod", "rm", "edit", "ls", "df", "du", "stat", "cp", "mv", "remove"}
+	partitionCommands := []string{"mkfs", "mkdir", "mkfile", "cat", "rename", "move", "copy", "find", "chown", "chmod", "rm", "edit", "ls", "df", "du", "stat", "cp", "mv", "remove", "fsck", "tune", "export", "import"}
 	return containsIgnoreCase(partitionCommands, cmd)
 }
 
 // isAuthCommand verifica si el comando es un comando de autenticación
 func isAuthCommand(cmd string) bool {
-	authCommands := []string{"login", "logout", "mkgrp", "mkusr", "rmgrp", "rmusr", "chgrp"}
+	authCommands := []string{"login", "logout", "mkgrp", "mkusr", "rmgrp", "rmusr", "chgrp", "chpass"}
 	return containsIgnoreCase(authCommands, cmd)
 }
 
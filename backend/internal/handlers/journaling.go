@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,6 +13,7 @@ import (
 
 // JournalEntry representa una entrada de journaling para la respuesta JSON
 type JournalEntry struct {
+	Sequence  int32     `json:"sequence"`
 	Operation string    `json:"operation"`
 	Path      string    `json:"path"`
 	Content   string    `json:"content"`
@@ -22,9 +22,14 @@ type JournalEntry struct {
 
 // JournalingResponse representa la respuesta del endpoint de journaling
 type JournalingResponse struct {
-	Success bool           `json:"success"`
-	Message string         `json:"message,omitempty"`
-	Entries []JournalEntry `json:"entries,omitempty"`
+	Success  bool           `json:"success"`
+	Message  string         `json:"message,omitempty"`
+	Head     int32          `json:"head"`     // Secuencia de la entrada más antigua
+	Tail     int32          `json:"tail"`     // Secuencia de la próxima entrada
+	Capacity int32          `json:"capacity"` // Slots del journal circular
+	Used     int32          `json:"used"`     // Entradas vivas entre head y tail
+	Policy   string         `json:"policy"`   // Política cuando el journal está lleno
+	Entries  []JournalEntry `json:"entries,omitempty"`
 }
 
 // GetJournaling maneja la solicitud para obtener las entradas del journaling
@@ -94,6 +99,8 @@ func GetJournaling(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	defer memory.RLockDisk(diskPath)()
+
 	// Leer el SuperBlock para acceder al journaling
 	sb := &ext2.SuperBlock{}
 	err := sb.DeserializeSuperBlock(diskPath, partitionData.Partition.Part_start)
@@ -110,23 +117,15 @@ func GetJournaling(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("SBmInodeStart: %d, Part_start: %d\n", sb.SBmInodeStart, partitionData.Partition.Part_start)
 
 	// Verificar si el filesystem es ext3 (tiene journaling)
-	if sb.SFilesystemType != 3 {
+	if !sb.HasJournal() {
 		response.Message = "La partición no usa ext3 y no tiene journaling habilitado"
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	// El inicio del journaling es justo después del SuperBlock
-	// Calculamos el tamaño del SuperBlock de manera directa
-	superBlockSize := binary.Size(ext2.SuperBlock{})
-	journalingStart := int64(partitionData.Partition.Part_start) + int64(superBlockSize)
-
-	fmt.Printf("Tamaño del SuperBlock: %d bytes\n", superBlockSize)
-	fmt.Printf("Inicio del journaling calculado: %d\n", journalingStart)
-
-	// Obtener las entradas de journaling
-	journals, err := ext2.GetJournaling(diskPath, journalingStart, sb.SFreeInodesCount)
+	// Obtener las entradas vivas del journal circular (entre head y tail)
+	journals, err := ext2.GetJournaling(diskPath, partitionData.Partition.Part_start)
 	if err != nil {
 		response.Message = "Error al leer el journaling: " + err.Error()
 		w.Header().Set("Content-Type", "application/json")
@@ -139,19 +138,17 @@ func GetJournaling(w http.ResponseWriter, r *http.Request) {
 	// Convertir las entradas al formato de respuesta
 	entries := make([]JournalEntry, 0, len(journals))
 	for _, journal := range journals {
-		// Convertir tiempo Unix a time.Time
-		date := time.Unix(int64(journal.J_content.I_date), 0)
-
-		// Limpiar los strings (quitar caracteres nulos)
-		operation := strings.TrimSpace(strings.Trim(string(journal.J_content.I_operation[:]), "\x00"))
-		path := strings.TrimSpace(strings.Trim(string(journal.J_content.I_path[:]), "\x00"))
-		content := strings.TrimSpace(strings.Trim(string(journal.J_content.I_content[:]), "\x00"))
+		// La ruta y el contenido ya vienen reensamblados desde sus slots de continuación
+		operation := strings.TrimSpace(journal.Operation)
+		path := strings.TrimSpace(journal.Path)
+		content := strings.TrimSpace(journal.Content)
 
 		entries = append(entries, JournalEntry{
+			Sequence:  journal.Sequence,
 			Operation: operation,
 			Path:      path,
 			Content:   content,
-			Date:      date,
+			Date:      journal.Date,
 		})
 
 		fmt.Printf("Journaling entry: Operation=%s, Path=%s\n", operation, path)
@@ -160,6 +157,11 @@ func GetJournaling(w http.ResponseWriter, r *http.Request) {
 	// Actualizar respuesta con las entradas
 	response.Success = true
 	response.Message = ""
+	response.Head = sb.SJournalHead
+	response.Tail = sb.SJournalTail
+	response.Capacity = sb.JournalCapacity(partitionData.Partition.Part_start)
+	response.Used = sb.JournalUsed()
+	response.Policy = ext2.JournalPolicyName(sb.SJournalPolicy)
 	response.Entries = entries
 
 	// Enviar respuesta JSON
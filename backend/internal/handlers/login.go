@@ -16,6 +16,7 @@ type LoginRequest struct {
 type LoginResponse struct {
 	Success bool   `json:"success"`
 	Msg     string `json:"msg"`
+	Token   string `json:"token,omitempty"`
 }
 
 func HandleLogin(c *gin.Context) {
@@ -35,9 +36,11 @@ func HandleLogin(c *gin.Context) {
 	password := req.Password
 	partition := req.Partition
 
-	fmt.Println("Login request:", user, password, partition)
+	fmt.Println("Login request:", user, partition)
 
-	err := auth.Login(user, password, partition)
+	// Cada login crea una sesión nueva identificada por su propio token
+	session := &auth.LoggedUser{}
+	err := auth.Login(session, user, password, partition)
 
 	if err != nil {
 		c.JSON(400, LoginResponse{
@@ -50,6 +53,7 @@ func HandleLogin(c *gin.Context) {
 	c.JSON(200, LoginResponse{
 		Success: true,
 		Msg:     "Login successful",
+		Token:   session.Token,
 	})
 
 }
@@ -11,7 +11,15 @@ type LogoutResponse struct {
 }
 
 func HandleLogout(c *gin.Context) {
-	auth.Logout()
+	err := auth.Logout(sessionFromRequest(c))
+
+	if err != nil {
+		c.JSON(400, LogoutResponse{
+			Success: false,
+			Msg:     err.Error(),
+		})
+		return
+	}
 
 	c.JSON(200, LogoutResponse{
 		Success: true,
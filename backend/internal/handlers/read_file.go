@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	partition_operations "disk.simulator.com/m/v2/internal/disk/operations/partitions"
@@ -28,6 +29,14 @@ func HandleReadFile(c *gin.Context) {
 		return
 	}
 
+	// Solo se leen archivos de la partición de la sesión del cliente
+	session, diskPath, partitionName, ok := requireSession(c, req.DiskPath, req.PartitionName)
+	if !ok {
+		return
+	}
+	req.DiskPath = diskPath
+	req.PartitionName = partitionName
+
 	// Validar que todos los campos requeridos están presentes
 	if req.DiskPath == "" || req.PartitionName == "" || req.FilePath == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -41,7 +50,11 @@ func HandleReadFile(c *gin.Context) {
 	parentDirs, fileName := utils.GetParentDirectories(req.FilePath)
 
 	// Usar la función existente ReadFileContent para leer el archivo
-	content, err := partition_operations.ReadFileContent(req.DiskPath, req.PartitionName, parentDirs, fileName)
+	content, err := partition_operations.ReadFileContent(session, req.DiskPath, req.PartitionName, parentDirs, fileName)
+	if errors.Is(err, partition_operations.ErrPermissionDenied) {
+		abortWithError(c, http.StatusForbidden, "forbidden", "Error al leer el archivo: "+err.Error())
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	partition_operations "disk.simulator.com/m/v2/internal/disk/operations/partitions"
+	"disk.simulator.com/m/v2/internal/disk/operations/reports"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportResponse es la respuesta del endpoint de reportes
+type ReportResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Report  string      `json:"report,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// HandleReport devuelve como JSON los datos del reporte indicado en la ruta
+// (/reports/:name) para que el frontend los dibuje
+func HandleReport(c *gin.Context) {
+	name := strings.ToLower(strings.TrimSpace(c.Param("name")))
+	id := c.Query("id")
+	pathFile := c.Query("path")
+
+	// Los reportes muestran el contenido de la partición, así que solo se generan
+	// para la partición en la que inició sesión el cliente
+	session, _, _, ok := requireSession(c, "", "")
+	if !ok {
+		return
+	}
+
+	if !slices.Contains(reports.ReportNames, name) {
+		c.JSON(http.StatusNotFound, ReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("Reporte '%s' desconocido, use uno de: %v", name, reports.ReportNames),
+		})
+		return
+	}
+
+	// Si no se especifica la partición, usar la de la sesión del cliente
+	if id == "" {
+		id = session.ID
+	}
+
+	mounted, _, err := memory.GetInstance().GetMountedPartition(id)
+	if err != nil || !mounted.UnmountTime.Before(mounted.MountTime) {
+		abortWithError(c, http.StatusNotFound, "not_found", fmt.Sprintf("La partición '%s' no está montada", id))
+		return
+	}
+
+	if !strings.EqualFold(id, session.ID) {
+		abortWithError(c, http.StatusForbidden, "forbidden", "La sesión no tiene acceso a la partición solicitada")
+		return
+	}
+
+	// Los reportes file y ls muestran el contenido de una ruta, que el usuario
+	// debe poder leer como con /read-file y /directory
+	if (name == "file" && pathFile != "") || name == "ls" {
+		checkPath := pathFile
+		if checkPath == "" {
+			checkPath = "/"
+		}
+
+		err := partition_operations.CheckReadAccess(session, id, checkPath)
+		if errors.Is(err, partition_operations.ErrPermissionDenied) {
+			abortWithError(c, http.StatusForbidden, "forbidden", fmt.Sprintf("Error al generar el reporte: %v", err))
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ReportResponse{
+				Success: false,
+				Message: fmt.Sprintf("Error al generar el reporte: %v", err),
+			})
+			return
+		}
+	}
+
+	data, err := reports.ReportData(name, id, pathFile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("Error al generar el reporte: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ReportResponse{
+		Success: true,
+		Report:  name,
+		Data:    data,
+	})
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"disk.simulator.com/m/v2/internal/args"
+	"disk.simulator.com/m/v2/internal/commands"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+)
+
+// maxExecDepth limita los exec anidados para evitar scripts que se incluyen a sí mismos
+const maxExecDepth = 8
+
+// lastIDVar guarda el ID de la última partición montada por el script
+const lastIDVar = "LAST_ID"
+
+// variableName valida los nombres usados en set y en mount ... -> VAR
+var variableName = regexp.MustCompile(`^\w+$`)
+
+// scriptRunner interpreta un script: además de los comandos del simulador
+// entiende las directivas set, exec, if exists y on error
+//
+//	set DISCO=/home/user/disco.mia         define una variable, usada luego como $DISCO o ${DISCO}
+//	mount -path=$DISCO -name=P1 -> PART     guarda el ID asignado en PART (siempre también en $LAST_ID)
+//	exec -path=/home/user/otro.smia        ejecuta otro script con las mismas variables
+//	if exists $DISCO rmdisk -path=$DISCO   ejecuta el comando solo si la ruta existe en el host
+//	if not exists $DISCO mkdisk ...        ejecuta el comando solo si la ruta no existe
+//	on error stop                          detiene el script en el siguiente error (o continue)
+type scriptRunner struct {
+	ctx         context.Context // Al cancelarse se dejan de ejecutar líneas
+	session     *auth.LoggedUser
+	vars        map[string]string
+	stopOnError bool
+	depth       int
+	emit        func(LineEvent) // Recibe el resultado de cada comando o directiva fallida
+	stopped     bool
+	skipped     int // Líneas del script principal que no se ejecutaron al detenerse
+}
+
+func newScriptRunner(ctx context.Context, session *auth.LoggedUser, stopOnError bool, emit func(LineEvent)) *scriptRunner {
+	return &scriptRunner{
+		ctx:         ctx,
+		session:     session,
+		vars:        map[string]string{},
+		stopOnError: stopOnError,
+		emit:        emit,
+	}
+}
+
+// run ejecuta las líneas del script; file es la ruta del script incluido con
+// exec o "" para el script principal. Retorna false si el script se detuvo
+func (r *scriptRunner) run(script string, file string) bool {
+	lines := scriptLines(script)
+	for i, line := range lines {
+		// Si el cliente cerró la conexión no tiene sentido seguir ejecutando
+		if r.ctx.Err() != nil || !r.runLine(line, file) {
+			r.stopped = true
+			if r.depth == 0 {
+				r.skipped = len(lines) - i
+				if r.ctx.Err() == nil {
+					r.skipped-- // La línea que falló sí se ejecutó
+				}
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// runLine ejecuta una línea y reporta su resultado. Retorna false si el
+// script debe detenerse
+func (r *scriptRunner) runLine(line scriptLine, file string) bool {
+	start := time.Now()
+	command, output, isCommand, err := r.execute(line.Text, file)
+	if !isCommand && err == nil {
+		return true
+	}
+
+	event := LineEvent{
+		Line:       line.Number,
+		File:       file,
+		Command:    command,
+		Input:      line.Text,
+		Status:     "ok",
+		Output:     output,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		event.Status = "error"
+		event.Error = err.Error()
+	}
+	r.emit(event)
+
+	return err == nil || !r.stopOnError
+}
+
+// execute interpreta una línea ya sin comentarios. isCommand indica si se
+// ejecutó un comando del simulador (las directivas solo se reportan si fallan)
+func (r *scriptRunner) execute(text string, file string) (string, string, bool, error) {
+	directive := strings.ToLower(strings.Fields(text)[0])
+
+	// on error continue|stop no necesita sustituir variables
+	if directive == "on" {
+		fields := strings.Fields(strings.ToLower(text))
+		if len(fields) != 3 || fields[1] != "error" || (fields[2] != "continue" && fields[2] != "stop") {
+			return directive, "", false, fmt.Errorf("uso: on error continue|stop")
+		}
+		r.stopOnError = fields[2] == "stop"
+		return directive, "", false, nil
+	}
+
+	// set VAR=valor admite espacios sin comillas en el valor
+	if directive == "set" {
+		name, value, found := strings.Cut(strings.TrimSpace(text[len("set"):]), "=")
+		name = strings.TrimSpace(name)
+		if !found || !variableName.MatchString(name) {
+			return directive, "", false, fmt.Errorf("uso: set VAR=valor")
+		}
+
+		tokens, err := args.Expand(value, r.lookup)
+		if err != nil {
+			return directive, "", false, err
+		}
+		words := make([]string, len(tokens))
+		for i, token := range tokens {
+			words[i] = token.Text
+		}
+		r.vars[name] = strings.Join(words, " ")
+		return directive, "", false, nil
+	}
+
+	// Las variables se expanden en cada token, respetando las comillas
+	tokens, err := args.Expand(text, r.lookup)
+	if err != nil {
+		return directive, "", false, err
+	}
+
+	switch directive {
+	case "if":
+		negate := len(tokens) > 1 && strings.EqualFold(tokens[1].Text, "not")
+		if negate {
+			tokens = append(tokens[:1], tokens[2:]...)
+		}
+		if len(tokens) < 4 || !strings.EqualFold(tokens[1].Text, "exists") {
+			return directive, "", false, fmt.Errorf("uso: if [not] exists <ruta> <comando>")
+		}
+
+		_, statErr := os.Stat(tokens[2].Text)
+		if (statErr == nil) == negate {
+			return directive, "", false, nil
+		}
+
+		// El comando se toma tal como está escrito para conservar sus comillas
+		return r.execute(string([]rune(text)[tokens[3].Column-1:]), file)
+
+	case "exec":
+		return directive, "", false, r.exec(args.Join(tokens))
+	}
+
+	// mount ... -> VAR guarda el ID asignado en VAR; la flecha solo cuenta si
+	// es el penúltimo token y está escrita sin comillas
+	target := ""
+	if n := len(tokens); n >= 3 && tokens[n-2].Plain && tokens[n-2].Text == "->" {
+		target = tokens[n-1].Text
+		if !variableName.MatchString(target) {
+			return directive, "", false, fmt.Errorf("nombre de variable no válido: '%s'", target)
+		}
+		tokens = tokens[:n-2]
+	}
+
+	command, output, err := runCommandLine(r.session, args.Join(tokens))
+	if err == nil && command == "mount" {
+		if id, ok := commands.MountedID(output); ok {
+			r.vars[lastIDVar] = id
+			if target != "" {
+				r.vars[target] = id
+			}
+		}
+	} else if err == nil && target != "" {
+		err = fmt.Errorf("solo se puede capturar el ID de mount con '->'")
+	}
+
+	return command, output, true, err
+}
+
+// exec ejecuta el script indicado con -path= compartiendo las variables
+func (r *scriptRunner) exec(text string) error {
+	fields, err := args.ParseArgs(text)
+	if err != nil {
+		return err
+	}
+
+	path := ""
+	for _, field := range fields[1:] {
+		if value, found := strings.CutPrefix(field, "--path="); found {
+			path = value
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("uso: exec -path=<script>")
+	}
+	if r.depth >= maxExecDepth {
+		return fmt.Errorf("demasiados exec anidados (máximo %d)", maxExecDepth)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error al leer el script '%s': %v", path, err)
+	}
+
+	r.depth++
+	defer func() { r.depth-- }()
+
+	if !r.run(string(content), path) {
+		return fmt.Errorf("el script '%s' se detuvo por un error", path)
+	}
+	return nil
+}
+
+// lookup retorna el valor de una variable del script
+func (r *scriptRunner) lookup(name string) (string, bool) {
+	value, ok := r.vars[name]
+	return value, ok
+}
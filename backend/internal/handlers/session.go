@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/memory"
+	"disk.simulator.com/m/v2/internal/disk/operations/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// sessionFromRequest obtiene la sesión del cliente a partir del header
+// "Authorization: Bearer <token>". Si no hay un token válido retorna una sesión
+// nueva sin usuario, que se registra al ejecutar login.
+func sessionFromRequest(c *gin.Context) *auth.LoggedUser {
+	token := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+	if token != "" {
+		if session, err := auth.GetSessionStore().GetSession(token); err == nil {
+			return session
+		}
+	}
+	return &auth.LoggedUser{}
+}
+
+// sessionPartition retorna el disco y el nombre de la partición en la que
+// inició sesión el cliente, para usarlos cuando la petición no los especifica
+func sessionPartition(session *auth.LoggedUser) (string, string, bool) {
+	if session.User == nil {
+		return "", "", false
+	}
+
+	mounted, diskPath, err := memory.GetInstance().GetMountedPartition(session.ID)
+	if err != nil {
+		return "", "", false
+	}
+	return diskPath, mounted.Name, true
+}
+
+// requireSession retorna la sesión del cliente y el disco y la partición de la
+// petición; si no se indican se usan los de la sesión. Responde 400 si solo se
+// indica uno de los dos, 401 si el cliente no inició sesión y 403 si pide otra
+// partición, ya que los permisos se evalúan con los usuarios de la partición en
+// la que inició sesión
+func requireSession(c *gin.Context, diskPath string, partitionName string) (*auth.LoggedUser, string, string, bool) {
+	if (diskPath == "") != (partitionName == "") {
+		abortWithError(c, http.StatusBadRequest, "bad_request", "Se deben indicar el disco y la partición juntos, o ninguno de los dos")
+		return nil, "", "", false
+	}
+
+	session := sessionFromRequest(c)
+	sessionDisk, sessionPartitionName, ok := sessionPartition(session)
+	if !ok {
+		abortWithError(c, http.StatusUnauthorized, "unauthorized", "Se requiere iniciar sesión")
+		return nil, "", "", false
+	}
+
+	if diskPath == "" && partitionName == "" {
+		return session, sessionDisk, sessionPartitionName, true
+	}
+
+	if filepath.Clean(diskPath) != filepath.Clean(sessionDisk) || partitionName != sessionPartitionName {
+		abortWithError(c, http.StatusForbidden, "forbidden", "La sesión no tiene acceso a la partición solicitada")
+		return nil, "", "", false
+	}
+	return session, diskPath, partitionName, true
+}
+
+// abortWithError responde con un error estructurado: un código estable para el
+// frontend y un mensaje legible
+func abortWithError(c *gin.Context, status int, code string, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"success": false,
+		"error":   code,
+		"message": message,
+	})
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/types/structures/authentication"
+)
+
+// FindGroupByGID busca un grupo activo por su GID
+func FindGroupByGID(fileText string, gid string) (*authentication.Group, int) {
+	lines := strings.Split(fileText, "\n")
+
+	for i, line := range lines {
+		data := strings.Split(strings.TrimSpace(line), ",")
+		if len(data) > 2 && data[1] == "G" && data[0] == gid && data[0] != "0" {
+			return &authentication.Group{
+				GID:  data[0],
+				Name: data[2],
+			}, i
+		}
+	}
+
+	return nil, -1
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strings"
+
+	"disk.simulator.com/m/v2/internal/disk/types/structures/authentication"
+)
+
+// FindUserByUID busca un usuario activo por su UID
+func FindUserByUID(fileText string, uid string) (*authentication.User, int) {
+	lines := strings.Split(fileText, "\n")
+
+	for i, line := range lines {
+		data := strings.Split(strings.TrimSpace(line), ",")
+		if len(data) > 4 && data[1] == "U" && data[0] == uid && data[0] != "0" {
+			return &authentication.User{
+				Username: data[3],
+				Password: data[4],
+				UID:      data[0],
+				Group:    data[2],
+			}, i
+		}
+	}
+
+	return nil, -1
+}
@@ -1,4 +1,5 @@
 import { DirectoryLsResponse } from "@/types/FileSystem";
+import { authHeaders } from "@/utils/session";
 
 export async function listDirectory(
   disk: string,
@@ -18,9 +19,7 @@ export async function listDirectory(
     // Realizar la petición
     const response = await fetch(url.toString(), {
       method: "GET",
-      headers: {
-        "Content-Type": "application/json",
-      },
+      headers: authHeaders(),
     });
 
     if (!response.ok) {
@@ -1,6 +1,9 @@
+import { saveSessionToken } from "@/utils/session";
+
 interface LoginResponse {
   success?: boolean;
   msg?: string;
+  token?: string;
 }
 
 export const login = async (
@@ -25,8 +28,9 @@ export const login = async (
 
     const data: LoginResponse = await response.json();
 
-    // Si la autenticación fue exitosa, podríamos establecer una cookie o estado aquí
+    // Guardar el token para enviarlo en las siguientes peticiones
     if (data.success) {
+      saveSessionToken(data.token);
       console.log("Login successful");
     }
 
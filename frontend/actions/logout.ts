@@ -1,3 +1,5 @@
+import { authHeaders, clearSessionToken } from "@/utils/session";
+
 interface LogoutResponse {
   success?: boolean;
   msg?: string;
@@ -8,12 +10,13 @@ export const logout = async (): Promise<LogoutResponse> => {
     const apiUrl = process.env.API_URL || "http://3.85.93.122:8080";
     const response = await fetch(`${apiUrl}/logout`, {
       method: "POST",
-      headers: {
-        "Content-Type": "application/json",
-      },
+      headers: authHeaders(),
     });
 
     const data: LogoutResponse = await response.json();
+    if (data.success) {
+      clearSessionToken();
+    }
     return data;
   } catch (error) {
     console.error("Logout error:", error);
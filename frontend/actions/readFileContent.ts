@@ -1,4 +1,5 @@
 import { FileContent } from "@/types/FileSystem";
+import { authHeaders } from "@/utils/session";
 
 /**
  * Lee el contenido de un archivo
@@ -19,9 +20,7 @@ export async function readFileContent(
     const apiUrl = process.env.API_URL || "http://3.85.93.122:8080";
     const response = await fetch(`${apiUrl}/read-file`, {
       method: "POST",
-      headers: {
-        "Content-Type": "application/json",
-      },
+      headers: authHeaders(),
       body: JSON.stringify({
         diskPath,
         partitionName,
@@ -1,5 +1,8 @@
+import { authHeaders, saveSessionToken } from "@/utils/session";
+
 interface CommandResponse {
   output: string;
+  token?: string;
 }
 
 export const sendCommand = async (command: string): Promise<string> => {
@@ -9,12 +12,13 @@ export const sendCommand = async (command: string): Promise<string> => {
 
   const response = await fetch(`${apiUrl}/command`, {
     method: "POST",
-    headers: {
-      "Content-Type": "application/json",
-    },
+    headers: authHeaders(),
     body: JSON.stringify({ command }),
   });
 
   const data: CommandResponse = await response.json();
+
+  // Un login dentro del comando crea una sesión con un token nuevo
+  saveSessionToken(data.token);
   return data.output;
 };
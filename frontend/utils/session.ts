@@ -0,0 +1,45 @@
+const TOKEN_KEY = "sessionToken";
+
+/**
+ * Retorna el token de la sesión guardado al iniciar sesión, si existe
+ */
+export function getSessionToken(): string | null {
+  if (typeof window === "undefined") return null;
+  return window.localStorage.getItem(TOKEN_KEY);
+}
+
+/**
+ * Guarda el token de la sesión que retornó el servidor
+ *
+ * @param token - Token de la sesión, o vacío para no modificar el actual
+ */
+export function saveSessionToken(token?: string): void {
+  if (typeof window === "undefined" || !token) return;
+  window.localStorage.setItem(TOKEN_KEY, token);
+}
+
+/**
+ * Elimina el token de la sesión al cerrar sesión
+ */
+export function clearSessionToken(): void {
+  if (typeof window === "undefined") return;
+  window.localStorage.removeItem(TOKEN_KEY);
+}
+
+/**
+ * Headers de una petición JSON con el token de la sesión, si existe
+ *
+ * @returns Headers con "Authorization: Bearer <token>"
+ */
+export function authHeaders(): Record<string, string> {
+  const headers: Record<string, string> = {
+    "Content-Type": "application/json",
+  };
+
+  const token = getSessionToken();
+  if (token) {
+    headers.Authorization = `Bearer ${token}`;
+  }
+
+  return headers;
+}